@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"time"
 
 	"Kevinmajesta/OrderManagementAPI/configs"
 	seeder "Kevinmajesta/OrderManagementAPI/db/seed"
@@ -44,9 +45,18 @@ func main() {
 	seeder.SeedUser(db)
 	seeder.SeedProducts(db)
 
+	// Store settings are cached in memory, so every route group shares one instance
+	settingService := builder.BuildSettingService(db)
+	worker.StartMembershipWorker(builder.BuildMembershipService(db, settingService), 24*time.Hour)
+	worker.StartReportWorker(builder.BuildReportSubscriptionService(db, cfg, settingService), time.Minute)
+
+	// Order events reach the displays connected to this process, including
+	// status changes made by the Midtrans webhook
+	orderEventService := builder.BuildOrderEventService()
+
 	// Build Echo route groups
-	publicRoutes := builder.BuildPublicRoutes(db, redisDB, tokenUseCase, encryptTool, cfg, midtransService)
-	privateRoutes := builder.BuildPrivateRoutes(db, redisDB, encryptTool, cfg, tokenUseCase, midtransService)
+	publicRoutes := builder.BuildPublicRoutes(db, redisDB, tokenUseCase, encryptTool, cfg, midtransService, settingService, orderEventService)
+	privateRoutes := builder.BuildPrivateRoutes(db, redisDB, encryptTool, cfg, tokenUseCase, midtransService, settingService, orderEventService)
 
 	// Start server
 	srv := server.NewServer("app", publicRoutes, privateRoutes, cfg.JWT.SecretKey)
@@ -16,6 +16,7 @@ type Config struct {
 	Encrypt  EncryptConfig  `envPrefix:"ENCRYPT_"`
 	SMTP     SMTPConfig     `envPrefix:"SMTP_"`
 	Midtrans MidtransConfig `envPrefix:"MIDTRANS_"`
+	Receipt  ReceiptConfig  `envPrefix:"RECEIPT_"`
 }
 
 type SMTPConfig struct {
@@ -54,6 +55,12 @@ type MidtransConfig struct {
 	IsProduction string `env:"IS_PRODUCTION"`
 }
 
+// ReceiptConfig holds the key that signs the QR code of a receipt. The JWT
+// secret key is used when it is empty.
+type ReceiptConfig struct {
+	SigningKey string `env:"SIGNING_KEY"`
+}
+
 func NewConfig(envPath string) (*Config, error) {
 	// Memuat .env file. Penting: jika file tidak ada atau ada masalah,
 	// godotenv.Load() akan mengembalikan error.
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS store_settings (
+    setting_key VARCHAR(100) PRIMARY KEY,
+    setting_value TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO store_settings (setting_key, setting_value) VALUES
+    ('store_name', 'Cuaniaga Store'),
+    ('store_address', 'Jl. Raya No. 123'),
+    ('store_phone', '+62 812 3456 7890'),
+    ('tax_rate', '0.10'),
+    ('receipt_prefix', 'RCP')
+ON CONFLICT (setting_key) DO NOTHING;
+
+COMMIT;
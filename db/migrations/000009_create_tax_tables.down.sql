@@ -0,0 +1,19 @@
+BEGIN;
+
+DELETE FROM store_settings WHERE setting_key = 'prices_include_tax';
+
+ALTER TABLE order_items
+DROP COLUMN IF EXISTS tax_amount,
+DROP COLUMN IF EXISTS tax_rate,
+DROP COLUMN IF EXISTS tax_class_id;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS tax_amount;
+
+ALTER TABLE products
+DROP COLUMN IF EXISTS tax_class_id;
+
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS tax_classes;
+
+COMMIT;
@@ -0,0 +1,39 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS tax_classes (
+    tax_class_id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    tax_rate_id UUID PRIMARY KEY,
+    tax_class_id UUID NOT NULL,
+    rate NUMERIC(6,4) NOT NULL CHECK (rate >= 0 AND rate <= 1),
+    effective_from TIMESTAMPTZ NOT NULL,
+    effective_to TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT tax_rates_class_fk FOREIGN KEY (tax_class_id) REFERENCES tax_classes(tax_class_id) ON DELETE CASCADE,
+    CONSTRAINT tax_rates_period_check CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tax_rates_class_effective ON tax_rates (tax_class_id, effective_from);
+
+ALTER TABLE products
+ADD COLUMN tax_class_id UUID REFERENCES tax_classes(tax_class_id) ON DELETE SET NULL;
+
+ALTER TABLE orders
+ADD COLUMN tax_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items
+ADD COLUMN tax_class_id UUID,
+ADD COLUMN tax_rate NUMERIC(6,4) NOT NULL DEFAULT 0,
+ADD COLUMN tax_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+INSERT INTO store_settings (setting_key, setting_value) VALUES
+    ('prices_include_tax', 'false')
+ON CONFLICT (setting_key) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE receipt_items
+DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE receipts
+DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE order_items
+DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE products
+DROP COLUMN IF EXISTS category;
+
+DROP TABLE IF EXISTS order_promotions;
+DROP TABLE IF EXISTS promotions;
+
+COMMIT;
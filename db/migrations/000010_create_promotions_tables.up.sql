@@ -0,0 +1,51 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS promotions (
+    promotion_id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    percentage NUMERIC(6,4) NOT NULL DEFAULT 0 CHECK (percentage >= 0 AND percentage <= 1),
+    amount NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    product_id UUID REFERENCES products(product_id) ON DELETE CASCADE,
+    category VARCHAR(100),
+    buy_quantity INT NOT NULL DEFAULT 0,
+    get_quantity INT NOT NULL DEFAULT 0,
+    min_spend NUMERIC(10,2) NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ,
+    usage_limit INT,
+    usage_count INT NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT false,
+    priority INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT promotions_usage_check CHECK (usage_limit IS NULL OR usage_count <= usage_limit)
+);
+
+CREATE TABLE IF NOT EXISTS order_promotions (
+    order_promotion_id UUID PRIMARY KEY,
+    order_id UUID NOT NULL,
+    promotion_id UUID NOT NULL,
+    discount_amount NUMERIC(10,2) NOT NULL CHECK (discount_amount >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT order_promotions_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE,
+    CONSTRAINT order_promotions_promotion_fk FOREIGN KEY (promotion_id) REFERENCES promotions(promotion_id) ON DELETE RESTRICT
+);
+
+ALTER TABLE products
+ADD COLUMN category VARCHAR(100);
+
+ALTER TABLE orders
+ADD COLUMN discount_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items
+ADD COLUMN discount_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE receipts
+ADD COLUMN discount_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE receipt_items
+ADD COLUMN discount_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+COMMIT;
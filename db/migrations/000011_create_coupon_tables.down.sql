@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS coupon_code;
+
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupon_codes;
+
+ALTER TABLE promotions
+DROP COLUMN IF EXISTS per_customer_limit,
+DROP COLUMN IF EXISTS requires_code;
+
+COMMIT;
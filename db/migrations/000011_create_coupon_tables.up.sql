@@ -0,0 +1,39 @@
+BEGIN;
+
+ALTER TABLE promotions
+ADD COLUMN requires_code BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN per_customer_limit INT CHECK (per_customer_limit IS NULL OR per_customer_limit > 0);
+
+CREATE TABLE IF NOT EXISTS coupon_codes (
+    coupon_code_id UUID PRIMARY KEY,
+    promotion_id UUID NOT NULL,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    max_redemptions INT CHECK (max_redemptions IS NULL OR max_redemptions > 0),
+    redemption_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT coupon_codes_promotion_fk FOREIGN KEY (promotion_id) REFERENCES promotions(promotion_id) ON DELETE CASCADE,
+    CONSTRAINT coupon_codes_redemption_check CHECK (max_redemptions IS NULL OR redemption_count <= max_redemptions)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_codes_promotion_id ON coupon_codes(promotion_id);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    coupon_redemption_id UUID PRIMARY KEY,
+    coupon_code_id UUID NOT NULL,
+    promotion_id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    discount_amount NUMERIC(10,2) NOT NULL CHECK (discount_amount >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT coupon_redemptions_code_fk FOREIGN KEY (coupon_code_id) REFERENCES coupon_codes(coupon_code_id) ON DELETE RESTRICT,
+    CONSTRAINT coupon_redemptions_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_promotion_user ON coupon_redemptions(promotion_id, user_id);
+
+ALTER TABLE orders
+ADD COLUMN coupon_code VARCHAR(50);
+
+COMMIT;
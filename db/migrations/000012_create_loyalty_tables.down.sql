@@ -0,0 +1,12 @@
+BEGIN;
+
+DELETE FROM store_settings WHERE setting_key IN ('loyalty_spend_per_point', 'loyalty_point_value');
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS points_amount,
+DROP COLUMN IF EXISTS points_redeemed;
+
+DROP TABLE IF EXISTS loyalty_transactions;
+DROP TABLE IF EXISTS loyalty_accounts;
+
+COMMIT;
@@ -0,0 +1,37 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS loyalty_accounts (
+    user_id UUID PRIMARY KEY,
+    points_balance INT NOT NULL DEFAULT 0 CHECK (points_balance >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT loyalty_accounts_user_fk FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_transactions (
+    loyalty_transaction_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    order_id UUID,
+    type VARCHAR(20) NOT NULL,
+    points INT NOT NULL,
+    balance_after INT NOT NULL,
+    description VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT loyalty_transactions_user_fk FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    CONSTRAINT loyalty_transactions_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_user_id ON loyalty_transactions(user_id, created_at);
+
+-- Each order earns, redeems and reverses at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_order_type ON loyalty_transactions(order_id, type) WHERE order_id IS NOT NULL;
+
+ALTER TABLE orders
+ADD COLUMN points_redeemed INT NOT NULL DEFAULT 0 CHECK (points_redeemed >= 0),
+ADD COLUMN points_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+INSERT INTO store_settings (setting_key, setting_value) VALUES
+    ('loyalty_spend_per_point', '10000'),
+    ('loyalty_point_value', '100')
+ON CONFLICT (setting_key) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DELETE FROM store_settings WHERE setting_key = 'membership_window_days';
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS tier_discount_amount,
+DROP COLUMN IF EXISTS membership_tier_id;
+
+DROP TABLE IF EXISTS user_memberships;
+DROP TABLE IF EXISTS membership_tiers;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS membership_tiers (
+    tier_id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    min_spend NUMERIC(12,2) NOT NULL UNIQUE CHECK (min_spend >= 0),
+    discount_rate NUMERIC(6,4) NOT NULL DEFAULT 0 CHECK (discount_rate >= 0 AND discount_rate <= 1),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS user_memberships (
+    user_id UUID PRIMARY KEY,
+    tier_id UUID,
+    qualifying_spend NUMERIC(12,2) NOT NULL DEFAULT 0,
+    evaluated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT user_memberships_user_fk FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    CONSTRAINT user_memberships_tier_fk FOREIGN KEY (tier_id) REFERENCES membership_tiers(tier_id) ON DELETE SET NULL
+);
+
+ALTER TABLE orders
+ADD COLUMN membership_tier_id UUID,
+ADD COLUMN tier_discount_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+INSERT INTO store_settings (setting_key, setting_value) VALUES
+    ('membership_window_days', '365')
+ON CONFLICT (setting_key) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+DELETE FROM store_settings WHERE setting_key = 'gift_card_validity_days';
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS gift_card_amount,
+DROP COLUMN IF EXISTS gift_card_id;
+
+DELETE FROM order_items WHERE item_type <> 'product';
+
+ALTER TABLE order_items
+DROP CONSTRAINT IF EXISTS order_items_item_type_check,
+DROP COLUMN IF EXISTS gift_card_id,
+DROP COLUMN IF EXISTS item_type,
+ALTER COLUMN product_id SET NOT NULL;
+
+DROP TABLE IF EXISTS gift_card_transactions;
+DROP TABLE IF EXISTS gift_cards;
+
+COMMIT;
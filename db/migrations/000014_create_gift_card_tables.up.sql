@@ -0,0 +1,47 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gift_cards (
+    gift_card_id UUID PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    initial_value NUMERIC(12,2) NOT NULL CHECK (initial_value > 0),
+    balance NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    expires_at TIMESTAMPTZ,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'void')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS gift_card_transactions (
+    gift_card_transaction_id UUID PRIMARY KEY,
+    gift_card_id UUID NOT NULL,
+    order_id UUID,
+    type VARCHAR(20) NOT NULL,
+    amount NUMERIC(12,2) NOT NULL,
+    balance_after NUMERIC(12,2) NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT gift_card_transactions_card_fk FOREIGN KEY (gift_card_id) REFERENCES gift_cards(gift_card_id) ON DELETE CASCADE,
+    CONSTRAINT gift_card_transactions_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_card_transactions_card_id ON gift_card_transactions(gift_card_id, created_at);
+
+-- An order redeems from, and reverses, each card at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_card_transactions_order_type ON gift_card_transactions(gift_card_id, order_id, type) WHERE order_id IS NOT NULL;
+
+-- Gift card lines have no product
+ALTER TABLE order_items
+ALTER COLUMN product_id DROP NOT NULL,
+ADD COLUMN item_type VARCHAR(20) NOT NULL DEFAULT 'product' CHECK (item_type IN ('product', 'gift_card')),
+ADD COLUMN gift_card_id UUID REFERENCES gift_cards(gift_card_id) ON DELETE SET NULL,
+ADD CONSTRAINT order_items_item_type_check CHECK ((item_type = 'product') = (product_id IS NOT NULL));
+
+ALTER TABLE orders
+ADD COLUMN gift_card_id UUID REFERENCES gift_cards(gift_card_id) ON DELETE SET NULL,
+ADD COLUMN gift_card_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+INSERT INTO store_settings (setting_key, setting_value) VALUES
+    ('gift_card_validity_days', '365')
+ON CONFLICT (setting_key) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,49 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS order_payments (
+    order_payment_id UUID PRIMARY KEY,
+    order_id UUID NOT NULL,
+    method VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'midtrans', 'gift_card', 'points')),
+    amount NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    tendered_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+    change_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'settled', 'cancelled', 'refunded')),
+    settled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT order_payments_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_payments_order_id ON order_payments(order_id);
+
+-- Existing orders get one tender for what was paid in money, plus their points and gift card
+INSERT INTO order_payments (order_payment_id, order_id, method, amount, tendered_amount, change_amount, status, settled_at, created_at)
+SELECT gen_random_uuid(), order_id, COALESCE(payment_method, 'midtrans'),
+       total_price - points_amount - gift_card_amount,
+       COALESCE(paid_amount, 0), COALESCE(change_amount, 0),
+       CASE
+           WHEN status IN ('paid', 'shipped', 'delivered') THEN 'settled'
+           WHEN status = 'refunded' THEN 'refunded'
+           WHEN status = 'cancelled' THEN 'cancelled'
+           ELSE 'pending'
+       END,
+       CASE WHEN status IN ('paid', 'shipped', 'delivered') THEN updated_at END,
+       created_at
+FROM orders
+WHERE total_price - points_amount - gift_card_amount > 0
+  AND COALESCE(payment_method, 'midtrans') IN ('cash', 'midtrans');
+
+INSERT INTO order_payments (order_payment_id, order_id, method, amount, status, settled_at, created_at)
+SELECT gen_random_uuid(), order_id, 'points', points_amount,
+       CASE WHEN status IN ('cancelled', 'refunded') THEN 'refunded' ELSE 'settled' END,
+       created_at, created_at
+FROM orders
+WHERE points_amount > 0;
+
+INSERT INTO order_payments (order_payment_id, order_id, method, amount, status, settled_at, created_at)
+SELECT gen_random_uuid(), order_id, 'gift_card', gift_card_amount,
+       CASE WHEN status IN ('cancelled', 'refunded') THEN 'refunded' ELSE 'settled' END,
+       created_at, created_at
+FROM orders
+WHERE gift_card_amount > 0;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_orders_shift_id;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS shift_id,
+DROP COLUMN IF EXISTS cashier_id;
+
+DROP TABLE IF EXISTS cash_movements;
+DROP TABLE IF EXISTS cash_shifts;
+
+COMMIT;
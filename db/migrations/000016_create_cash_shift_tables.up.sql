@@ -0,0 +1,44 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS cash_shifts (
+    shift_id UUID PRIMARY KEY,
+    cashier_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opening_float NUMERIC(12,2) NOT NULL CHECK (opening_float >= 0),
+    expected_cash NUMERIC(12,2),
+    counted_cash NUMERIC(12,2) CHECK (counted_cash >= 0),
+    variance NUMERIC(12,2),
+    note VARCHAR(255),
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at TIMESTAMPTZ,
+    CONSTRAINT cash_shifts_cashier_fk FOREIGN KEY (cashier_id) REFERENCES users(user_id) ON DELETE RESTRICT
+);
+
+-- A cashier has at most one open drawer
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cash_shifts_open_cashier ON cash_shifts(cashier_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_cash_shifts_opened_at ON cash_shifts(opened_at);
+
+CREATE TABLE IF NOT EXISTS cash_movements (
+    cash_movement_id UUID PRIMARY KEY,
+    shift_id UUID NOT NULL,
+    order_id UUID,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('sale', 'refund', 'pay_in', 'pay_out')),
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    reason VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT cash_movements_shift_fk FOREIGN KEY (shift_id) REFERENCES cash_shifts(shift_id) ON DELETE CASCADE,
+    CONSTRAINT cash_movements_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_cash_movements_shift_id ON cash_movements(shift_id);
+
+-- The cash of an order goes in and comes back out at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cash_movements_order_type ON cash_movements(order_id, type) WHERE order_id IS NOT NULL;
+
+ALTER TABLE orders
+ADD COLUMN cashier_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+ADD COLUMN shift_id UUID REFERENCES cash_shifts(shift_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_shift_id ON orders(shift_id);
+
+COMMIT;
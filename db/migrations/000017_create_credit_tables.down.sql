@@ -0,0 +1,14 @@
+BEGIN;
+
+DELETE FROM order_payments WHERE method = 'credit';
+
+ALTER TABLE order_payments DROP CONSTRAINT IF EXISTS order_payments_method_check;
+ALTER TABLE order_payments ADD CONSTRAINT order_payments_method_check
+    CHECK (method IN ('cash', 'midtrans', 'gift_card', 'points'));
+
+DROP TABLE IF EXISTS credit_allocations;
+DROP TABLE IF EXISTS credit_payments;
+DROP TABLE IF EXISTS credit_invoices;
+DROP TABLE IF EXISTS credit_accounts;
+
+COMMIT;
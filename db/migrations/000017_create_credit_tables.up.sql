@@ -0,0 +1,61 @@
+BEGIN;
+
+-- Customers approved to buy on credit. Balance is what they owe on open invoices.
+CREATE TABLE IF NOT EXISTS credit_accounts (
+    user_id UUID PRIMARY KEY,
+    credit_limit NUMERIC(12,2) NOT NULL CHECK (credit_limit >= 0),
+    balance NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    payment_terms_days INT NOT NULL DEFAULT 30 CHECK (payment_terms_days >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended')),
+    approved_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT credit_accounts_user_fk FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS credit_invoices (
+    invoice_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    order_id UUID NOT NULL UNIQUE,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    paid_amount NUMERIC(12,2) NOT NULL DEFAULT 0 CHECK (paid_amount >= 0 AND paid_amount <= amount),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'paid', 'void')),
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    due_date TIMESTAMPTZ NOT NULL,
+    paid_at TIMESTAMPTZ,
+    CONSTRAINT credit_invoices_account_fk FOREIGN KEY (user_id) REFERENCES credit_accounts(user_id) ON DELETE RESTRICT,
+    CONSTRAINT credit_invoices_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_invoices_user_status ON credit_invoices(user_id, status, issued_at);
+
+CREATE TABLE IF NOT EXISTS credit_payments (
+    credit_payment_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    method VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'transfer')),
+    reference VARCHAR(100),
+    note VARCHAR(255),
+    received_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT credit_payments_account_fk FOREIGN KEY (user_id) REFERENCES credit_accounts(user_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_payments_user_id ON credit_payments(user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS credit_allocations (
+    credit_allocation_id UUID PRIMARY KEY,
+    credit_payment_id UUID NOT NULL,
+    invoice_id UUID NOT NULL,
+    amount NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    CONSTRAINT credit_allocations_payment_fk FOREIGN KEY (credit_payment_id) REFERENCES credit_payments(credit_payment_id) ON DELETE CASCADE,
+    CONSTRAINT credit_allocations_invoice_fk FOREIGN KEY (invoice_id) REFERENCES credit_invoices(invoice_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_allocations_invoice_id ON credit_allocations(invoice_id);
+
+ALTER TABLE order_payments DROP CONSTRAINT IF EXISTS order_payments_method_check;
+ALTER TABLE order_payments ADD CONSTRAINT order_payments_method_check
+    CHECK (method IN ('cash', 'midtrans', 'gift_card', 'points', 'credit'));
+
+COMMIT;
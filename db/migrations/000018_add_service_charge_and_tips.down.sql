@@ -0,0 +1,16 @@
+BEGIN;
+
+DELETE FROM store_settings WHERE setting_key = 'service_charge_rate';
+
+ALTER TABLE receipts
+DROP COLUMN IF EXISTS tip_amount,
+DROP COLUMN IF EXISTS service_charge_amount;
+
+DROP INDEX IF EXISTS idx_orders_cashier_id;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS tip_amount,
+DROP COLUMN IF EXISTS service_charge_amount,
+DROP COLUMN IF EXISTS service_charge_rate;
+
+COMMIT;
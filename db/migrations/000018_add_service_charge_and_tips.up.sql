@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE orders
+ADD COLUMN service_charge_rate NUMERIC(6,4) NOT NULL DEFAULT 0 CHECK (service_charge_rate >= 0 AND service_charge_rate <= 1),
+ADD COLUMN service_charge_amount NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (service_charge_amount >= 0),
+ADD COLUMN tip_amount NUMERIC(10,2) NOT NULL DEFAULT 0 CHECK (tip_amount >= 0);
+
+-- Tips are reported per cashier
+CREATE INDEX IF NOT EXISTS idx_orders_cashier_id ON orders(cashier_id);
+
+ALTER TABLE receipts
+ADD COLUMN service_charge_amount NUMERIC(10,2) NOT NULL DEFAULT 0,
+ADD COLUMN tip_amount NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+INSERT INTO store_settings (setting_key, setting_value) VALUES
+    ('service_charge_rate', '0')
+ON CONFLICT (setting_key) DO NOTHING;
+
+COMMIT;
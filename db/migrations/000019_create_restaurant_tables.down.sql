@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS tab_id;
+
+DROP TABLE IF EXISTS tab_items;
+DROP TABLE IF EXISTS kitchen_tickets;
+DROP TABLE IF EXISTS tabs;
+DROP TABLE IF EXISTS dining_tables;
+
+COMMIT;
@@ -0,0 +1,75 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS dining_tables (
+    table_id UUID PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    area VARCHAR(50),
+    seats INT NOT NULL DEFAULT 2 CHECK (seats > 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- A tab is an open bill on a table. Version goes up on every change so a
+-- payment can tell the tab did not change while it was being paid.
+CREATE TABLE IF NOT EXISTS tabs (
+    tab_id UUID PRIMARY KEY,
+    table_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    opened_by UUID,
+    guest_count INT NOT NULL DEFAULT 1 CHECK (guest_count > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed', 'merged', 'cancelled')),
+    rounds INT NOT NULL DEFAULT 0,
+    version INT NOT NULL DEFAULT 0,
+    order_id UUID,
+    merged_into UUID,
+    note VARCHAR(255),
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at TIMESTAMPTZ,
+    CONSTRAINT tabs_table_fk FOREIGN KEY (table_id) REFERENCES dining_tables(table_id) ON DELETE RESTRICT,
+    CONSTRAINT tabs_user_fk FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE RESTRICT,
+    CONSTRAINT tabs_opened_by_fk FOREIGN KEY (opened_by) REFERENCES users(user_id) ON DELETE SET NULL,
+    CONSTRAINT tabs_order_fk FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE SET NULL,
+    CONSTRAINT tabs_merged_into_fk FOREIGN KEY (merged_into) REFERENCES tabs(tab_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tabs_table_open ON tabs(table_id) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS kitchen_tickets (
+    ticket_id UUID PRIMARY KEY,
+    tab_id UUID NOT NULL,
+    table_id UUID NOT NULL,
+    round INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'sent' CHECK (status IN ('held', 'sent', 'done')),
+    note VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ,
+    done_at TIMESTAMPTZ,
+    CONSTRAINT kitchen_tickets_tab_fk FOREIGN KEY (tab_id) REFERENCES tabs(tab_id) ON DELETE CASCADE,
+    CONSTRAINT kitchen_tickets_table_fk FOREIGN KEY (table_id) REFERENCES dining_tables(table_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_kitchen_tickets_status ON kitchen_tickets(status, created_at);
+
+-- Items stay on their kitchen ticket when a bill is split or merged
+CREATE TABLE IF NOT EXISTS tab_items (
+    tab_item_id UUID PRIMARY KEY,
+    tab_id UUID NOT NULL,
+    ticket_id UUID NOT NULL,
+    round INT NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    note VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT tab_items_tab_fk FOREIGN KEY (tab_id) REFERENCES tabs(tab_id) ON DELETE CASCADE,
+    CONSTRAINT tab_items_ticket_fk FOREIGN KEY (ticket_id) REFERENCES kitchen_tickets(ticket_id) ON DELETE CASCADE,
+    CONSTRAINT tab_items_product_fk FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_tab_items_tab_id ON tab_items(tab_id);
+CREATE INDEX IF NOT EXISTS idx_tab_items_ticket_id ON tab_items(ticket_id);
+
+ALTER TABLE orders
+ADD COLUMN tab_id UUID REFERENCES tabs(tab_id) ON DELETE SET NULL;
+
+COMMIT;
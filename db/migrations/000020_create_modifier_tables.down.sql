@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP TABLE IF EXISTS receipt_item_modifiers;
+DROP TABLE IF EXISTS order_item_modifiers;
+DROP TABLE IF EXISTS tab_item_modifiers;
+DROP TABLE IF EXISTS cart_item_modifiers;
+DROP TABLE IF EXISTS product_modifier_groups;
+DROP TABLE IF EXISTS modifier_options;
+DROP TABLE IF EXISTS modifier_groups;
+
+COMMIT;
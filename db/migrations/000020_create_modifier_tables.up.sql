@@ -0,0 +1,79 @@
+BEGIN;
+
+-- A group such as size or sugar level. A required group needs at least one
+-- option chosen, and min_select/max_select bound how many can be chosen.
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    modifier_group_id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    is_required BOOLEAN NOT NULL DEFAULT false,
+    min_select INT NOT NULL DEFAULT 0,
+    max_select INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (min_select >= 0 AND max_select >= 1 AND max_select >= min_select)
+);
+
+CREATE TABLE IF NOT EXISTS modifier_options (
+    modifier_option_id UUID PRIMARY KEY,
+    modifier_group_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    price_delta NUMERIC(10,2) NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT modifier_options_group_fk FOREIGN KEY (modifier_group_id) REFERENCES modifier_groups(modifier_group_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_modifier_options_group_id ON modifier_options(modifier_group_id);
+
+CREATE TABLE IF NOT EXISTS product_modifier_groups (
+    product_id UUID NOT NULL,
+    modifier_group_id UUID NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (product_id, modifier_group_id),
+    CONSTRAINT product_modifier_groups_product_fk FOREIGN KEY (product_id) REFERENCES products(product_id) ON DELETE CASCADE,
+    CONSTRAINT product_modifier_groups_group_fk FOREIGN KEY (modifier_group_id) REFERENCES modifier_groups(modifier_group_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cart_item_modifiers (
+    cart_item_id UUID NOT NULL,
+    modifier_option_id UUID NOT NULL,
+    PRIMARY KEY (cart_item_id, modifier_option_id),
+    CONSTRAINT cart_item_modifiers_item_fk FOREIGN KEY (cart_item_id) REFERENCES cart_items(cart_item_id) ON DELETE CASCADE,
+    CONSTRAINT cart_item_modifiers_option_fk FOREIGN KEY (modifier_option_id) REFERENCES modifier_options(modifier_option_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tab_item_modifiers (
+    tab_item_id UUID NOT NULL,
+    modifier_option_id UUID NOT NULL,
+    PRIMARY KEY (tab_item_id, modifier_option_id),
+    CONSTRAINT tab_item_modifiers_item_fk FOREIGN KEY (tab_item_id) REFERENCES tab_items(tab_item_id) ON DELETE CASCADE,
+    CONSTRAINT tab_item_modifiers_option_fk FOREIGN KEY (modifier_option_id) REFERENCES modifier_options(modifier_option_id) ON DELETE RESTRICT
+);
+
+-- Order lines keep the names and prices of the options at the time of sale
+CREATE TABLE IF NOT EXISTS order_item_modifiers (
+    order_item_modifier_id UUID PRIMARY KEY,
+    orderitem_id UUID NOT NULL,
+    modifier_option_id UUID,
+    group_name VARCHAR(100) NOT NULL,
+    option_name VARCHAR(100) NOT NULL,
+    price_delta NUMERIC(10,2) NOT NULL DEFAULT 0,
+    CONSTRAINT order_item_modifiers_item_fk FOREIGN KEY (orderitem_id) REFERENCES order_items(orderitem_id) ON DELETE CASCADE,
+    CONSTRAINT order_item_modifiers_option_fk FOREIGN KEY (modifier_option_id) REFERENCES modifier_options(modifier_option_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_item_modifiers_orderitem_id ON order_item_modifiers(orderitem_id);
+
+CREATE TABLE IF NOT EXISTS receipt_item_modifiers (
+    receipt_item_modifier_id UUID PRIMARY KEY,
+    receipt_item_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    price_delta NUMERIC(10,2) NOT NULL DEFAULT 0,
+    CONSTRAINT receipt_item_modifiers_item_fk FOREIGN KEY (receipt_item_id) REFERENCES receipt_items(receipt_item_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_receipt_item_modifiers_receipt_item_id ON receipt_item_modifiers(receipt_item_id);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_orders_fulfilment_open;
+
+ALTER TABLE orders
+DROP COLUMN IF EXISTS fulfilment_updated_at,
+DROP COLUMN IF EXISTS fulfilment_status;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- Fulfilment is tracked apart from status, which follows the payment. Orders
+-- placed before the order display existed are treated as served.
+ALTER TABLE orders
+ADD COLUMN fulfilment_status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (fulfilment_status IN ('queued', 'preparing', 'ready', 'served')),
+ADD COLUMN fulfilment_updated_at TIMESTAMPTZ;
+
+UPDATE orders SET fulfilment_status = 'served';
+
+CREATE INDEX IF NOT EXISTS idx_orders_fulfilment_open ON orders(created_at) WHERE fulfilment_status <> 'served';
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DELETE FROM store_settings WHERE setting_key = 'receipt_number_format';
+
+ALTER TABLE receipts
+ALTER COLUMN receipt_number TYPE VARCHAR(20);
+
+DROP TABLE IF EXISTS receipt_counters;
+
+COMMIT;
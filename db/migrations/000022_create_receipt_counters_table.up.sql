@@ -0,0 +1,27 @@
+BEGIN;
+
+-- One row per day holds the last receipt number handed out. It is bumped in
+-- the transaction that creates the receipt, so a rolled back receipt gives
+-- its number back and numbers stay gap-free.
+CREATE TABLE IF NOT EXISTS receipt_counters (
+    counter_date DATE PRIMARY KEY,
+    last_number INT NOT NULL CHECK (last_number > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Carry on from the receipts already made with the old numbering
+INSERT INTO receipt_counters (counter_date, last_number)
+SELECT created_at::date, COUNT(*)
+FROM receipts
+GROUP BY created_at::date
+ON CONFLICT (counter_date) DO NOTHING;
+
+-- Numbers built from receipt_number_format can be longer than the old ones
+ALTER TABLE receipts
+ALTER COLUMN receipt_number TYPE VARCHAR(40);
+
+INSERT INTO store_settings (setting_key, setting_value) VALUES
+    ('receipt_number_format', '{prefix}{yyyy}{mm}{dd}{seq:4}')
+ON CONFLICT (setting_key) DO NOTHING;
+
+COMMIT;
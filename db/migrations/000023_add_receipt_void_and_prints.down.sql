@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS receipt_prints;
+
+DROP INDEX IF EXISTS idx_receipts_voided_at;
+
+ALTER TABLE receipts
+DROP COLUMN IF EXISTS voided_by,
+DROP COLUMN IF EXISTS void_reason,
+DROP COLUMN IF EXISTS voided_at;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+-- A void keeps the receipt and records who voided it and why. The order can
+-- then be given a new receipt.
+ALTER TABLE receipts
+ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS void_reason TEXT,
+ADD COLUMN IF NOT EXISTS voided_by UUID REFERENCES users(user_id) ON DELETE SET NULL;
+
+-- Every time a receipt is printed. Print 0 is the original, every later one
+-- is a reprint.
+CREATE TABLE IF NOT EXISTS receipt_prints (
+    print_id UUID PRIMARY KEY,
+    receipt_id UUID NOT NULL REFERENCES receipts(receipt_id) ON DELETE CASCADE,
+    print_number INT NOT NULL CHECK (print_number >= 0),
+    format VARCHAR(20) NOT NULL,
+    printed_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (receipt_id, print_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_receipt_prints_created_at ON receipt_prints(created_at);
+CREATE INDEX IF NOT EXISTS idx_receipts_voided_at ON receipts(voided_at) WHERE voided_at IS NOT NULL;
+
+COMMIT;
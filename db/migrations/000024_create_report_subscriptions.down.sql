@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS report_deliveries;
+DROP TABLE IF EXISTS report_subscriptions;
+
+COMMIT;
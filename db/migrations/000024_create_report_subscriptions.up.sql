@@ -0,0 +1,36 @@
+BEGIN;
+
+-- Sales reports emailed to admins once their period has closed. recipients is
+-- a comma separated list of addresses.
+CREATE TABLE IF NOT EXISTS report_subscriptions (
+    subscription_id UUID PRIMARY KEY,
+    report_type VARCHAR(20) NOT NULL CHECK (report_type IN ('daily', 'monthly')),
+    send_hour INT NOT NULL DEFAULT 7 CHECK (send_hour BETWEEN 0 AND 23),
+    recipients TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL DEFAULT 'xlsx' CHECK (format IN ('csv', 'xlsx')),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per subscription and period, so a report is never sent twice.
+-- Pending rows are retried at next_attempt_at.
+CREATE TABLE IF NOT EXISTS report_deliveries (
+    delivery_id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES report_subscriptions(subscription_id) ON DELETE CASCADE,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (subscription_id, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_deliveries_due ON report_deliveries(next_attempt_at) WHERE status = 'pending';
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+ALTER TABLE receipt_items
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE receipts
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE cart_items
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE carts
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE order_items
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE orders
+ALTER COLUMN order_date TYPE TIMESTAMP USING order_date AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+COMMIT;
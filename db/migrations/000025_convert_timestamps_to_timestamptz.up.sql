@@ -0,0 +1,32 @@
+BEGIN;
+
+-- These columns were stored without a time zone, as Asia/Jakarta wall clock
+-- time: the zone the connection string gives the database session. They
+-- become TIMESTAMPTZ so an order keeps its instant whatever time zone the
+-- store or the server uses. If the API or the database ran in another zone
+-- before this migration, change 'Asia/Jakarta' below to that zone.
+ALTER TABLE orders
+ALTER COLUMN order_date TYPE TIMESTAMPTZ USING order_date AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE order_items
+ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE carts
+ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE cart_items
+ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE receipts
+ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'Asia/Jakarta',
+ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'Asia/Jakarta';
+
+ALTER TABLE receipt_items
+ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'Asia/Jakarta';
+
+COMMIT;
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -74,7 +75,7 @@ func SeedProducts(db *gorm.DB) {
 			ProductID:   uuid.New(),
 			Name:        "Kemeja Lengan Panjang",
 			Description: "Kemeja bahan katun premium",
-			Price:       150000,
+			Price:       money.New(150000),
 			Stock:       20,
 			PhotoURL:    "/assets/photos/kemeja1.jpg",
 		},
@@ -82,7 +83,7 @@ func SeedProducts(db *gorm.DB) {
 			ProductID:   uuid.New(),
 			Name:        "Celana Jeans Slim Fit",
 			Description: "Celana jeans biru cocok untuk sehari-hari",
-			Price:       200000,
+			Price:       money.New(200000),
 			Stock:       15,
 			PhotoURL:    "/assets/photos/jeans1.jpg",
 		},
@@ -90,7 +91,7 @@ func SeedProducts(db *gorm.DB) {
 			ProductID:   uuid.New(),
 			Name:        "Sepatu Sneakers",
 			Description: "Sneakers kekinian warna putih",
-			Price:       300000,
+			Price:       money.New(300000),
 			Stock:       10,
 			PhotoURL:    "/assets/photos/sneakers1.jpg",
 		},
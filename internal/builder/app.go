@@ -17,8 +17,29 @@ import (
 	"gorm.io/gorm"
 )
 
+func BuildSettingService(db *gorm.DB) service.SettingService {
+	settingRepository := repository.NewSettingRepository(db)
+	return service.NewSettingService(settingRepository)
+}
+
+func BuildMembershipService(db *gorm.DB, settingService service.SettingService) service.MembershipService {
+	membershipRepository := repository.NewMembershipRepository(db)
+	return service.NewMembershipService(membershipRepository, settingService)
+}
+
+// BuildReportSubscriptionService builds the service the report worker uses to
+// send scheduled sales reports.
+func BuildReportSubscriptionService(db *gorm.DB, cfg *configs.Config, settingService service.SettingService) service.ReportSubscriptionService {
+	salesReportService := service.NewSalesReportService(repository.NewSalesReportRepository(db), settingService)
+	return service.NewReportSubscriptionService(repository.NewReportSubscriptionRepository(db), salesReportService, settingService, email.NewEmailSender(cfg))
+}
+
+func BuildOrderEventService() service.OrderEventService {
+	return service.NewOrderEventService()
+}
+
 func BuildPublicRoutes(db *gorm.DB, redisDB *redis.Client, tokenUseCase token.TokenUseCase, encryptTool encrypt.EncryptTool,
-	cfg *configs.Config, midtransService *midtrans.MidtransService) []*route.Route {
+	cfg *configs.Config, midtransService *midtrans.MidtransService, settingService service.SettingService, orderEventService service.OrderEventService) []*route.Route {
 	EmailSenderService := email.NewEmailSender(cfg)
 	userRepository := repository.NewUserRepository(db, nil)
 	userService := service.NewUserService(userRepository, tokenUseCase, encryptTool, EmailSenderService)
@@ -32,13 +53,25 @@ func BuildPublicRoutes(db *gorm.DB, redisDB *redis.Client, tokenUseCase token.To
 	// Order service for Midtrans webhook
 	cacheable := cache.NewCacheable(redisDB)
 	orderRepository := repository.NewOrderRepository(db, cacheable)
-	orderService := service.NewOrderService(orderRepository, db, midtransService)
-	midtransHandler := handler.NewMidtransHandler(orderService)
+	taxService := service.NewTaxService(repository.NewTaxRepository(db), settingService)
+	promotionRepository := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(promotionRepository)
+	couponService := service.NewCouponService(repository.NewCouponRepository(db), promotionRepository)
+	loyaltyService := service.NewLoyaltyService(repository.NewLoyaltyRepository(db), settingService)
+	membershipService := service.NewMembershipService(repository.NewMembershipRepository(db), settingService)
+	giftCardService := service.NewGiftCardService(repository.NewGiftCardRepository(db), settingService, db)
+	shiftService := service.NewShiftService(repository.NewShiftRepository(db), settingService, db)
+	creditService := service.NewCreditService(repository.NewCreditRepository(db), settingService, EmailSenderService, db)
+	modifierService := service.NewModifierService(repository.NewModifierRepository(db))
+	receiptService := service.NewReceiptService(repository.NewReceiptRepository(db), orderRepository, settingService, receiptSigningKey(cfg), db)
+	orderService := service.NewOrderService(orderRepository, taxService, promotionService, couponService, loyaltyService, membershipService, giftCardService, shiftService, creditService, settingService, modifierService, orderEventService, receiptService, db, midtransService)
+	midtransHandler := handler.NewMidtransHandler(orderService, cfg.Midtrans.ServerKey)
+	receiptHandler := handler.NewReceiptHandler(receiptService)
 
-	return router.PublicRoutes(userHandler, adminHandler, midtransHandler)
+	return router.PublicRoutes(userHandler, adminHandler, midtransHandler, receiptHandler)
 }
 
-func BuildPrivateRoutes(db *gorm.DB, redisDB *redis.Client, encryptTool encrypt.EncryptTool, cfg *configs.Config, tokenUseCase token.TokenUseCase, midtransService *midtrans.MidtransService) []*route.Route {
+func BuildPrivateRoutes(db *gorm.DB, redisDB *redis.Client, encryptTool encrypt.EncryptTool, cfg *configs.Config, tokenUseCase token.TokenUseCase, midtransService *midtrans.MidtransService, settingService service.SettingService, orderEventService service.OrderEventService) []*route.Route {
 	cacheable := cache.NewCacheable(redisDB)
 	userRepository := repository.NewUserRepository(db, cacheable)
 	userService := service.NewUserService(userRepository, nil, encryptTool, nil)
@@ -53,21 +86,75 @@ func BuildPrivateRoutes(db *gorm.DB, redisDB *redis.Client, encryptTool encrypt.
 	productService := service.NewProductService(productRepository)
 	productHandler := handler.NewProductHandler(productService)
 
+	taxRepository := repository.NewTaxRepository(db)
+	taxService := service.NewTaxService(taxRepository, settingService)
+	taxHandler := handler.NewTaxHandler(taxService)
+
+	promotionRepository := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(promotionRepository)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+
+	couponRepository := repository.NewCouponRepository(db)
+	couponService := service.NewCouponService(couponRepository, promotionRepository)
+	couponHandler := handler.NewCouponHandler(couponService)
+
+	loyaltyRepository := repository.NewLoyaltyRepository(db)
+	loyaltyService := service.NewLoyaltyService(loyaltyRepository, settingService)
+	loyaltyHandler := handler.NewLoyaltyHandler(loyaltyService)
+
+	membershipRepository := repository.NewMembershipRepository(db)
+	membershipService := service.NewMembershipService(membershipRepository, settingService)
+	membershipHandler := handler.NewMembershipHandler(membershipService)
+
+	giftCardRepository := repository.NewGiftCardRepository(db)
+	giftCardService := service.NewGiftCardService(giftCardRepository, settingService, db)
+	giftCardHandler := handler.NewGiftCardHandler(giftCardService)
+
+	shiftRepository := repository.NewShiftRepository(db)
+	shiftService := service.NewShiftService(shiftRepository, settingService, db)
+	shiftHandler := handler.NewShiftHandler(shiftService)
+
+	creditRepository := repository.NewCreditRepository(db)
+	creditService := service.NewCreditService(creditRepository, settingService, email.NewEmailSender(cfg), db)
+	creditHandler := handler.NewCreditHandler(creditService)
+
+	modifierRepository := repository.NewModifierRepository(db)
+	modifierService := service.NewModifierService(modifierRepository)
+	modifierHandler := handler.NewModifierHandler(modifierService)
+
 	orderRepository := repository.NewOrderRepository(db, cacheable)
-	orderService := service.NewOrderService(orderRepository, db, midtransService)
-	orderHandler := handler.NewOrderHandler(orderService)
+	receiptRepository := repository.NewReceiptRepository(db)
+	receiptService := service.NewReceiptService(receiptRepository, orderRepository, settingService, receiptSigningKey(cfg), db)
+	orderService := service.NewOrderService(orderRepository, taxService, promotionService, couponService, loyaltyService, membershipService, giftCardService, shiftService, creditService, settingService, modifierService, orderEventService, receiptService, db, midtransService)
+	orderHandler := handler.NewOrderHandler(orderService, orderEventService)
 
 	cartRepository := repository.NewCartRepository(db)
-	cartService := service.NewCartService(cartRepository, orderService, productRepository)
+	cartService := service.NewCartService(cartRepository, orderService, productRepository, modifierService)
 	cartHandler := handler.NewCartHandler(cartService)
 
-	receiptRepository := repository.NewReceiptRepository(db)
-	receiptService := service.NewReceiptService(receiptRepository, orderRepository, db)
+	tabRepository := repository.NewTabRepository(db)
+	tabService := service.NewTabService(tabRepository, orderService, modifierService, db)
+	tabHandler := handler.NewTabHandler(tabService)
+
 	receiptHandler := handler.NewReceiptHandler(receiptService)
 
 	salesReportRepository := repository.NewSalesReportRepository(db)
-	salesReportService := service.NewSalesReportService(salesReportRepository)
+	salesReportService := service.NewSalesReportService(salesReportRepository, settingService)
 	salesReportHandler := handler.NewSalesReportHandler(salesReportService)
 
-	return router.PrivateRoutes(userHandler, adminHandler, productHandler, *orderHandler, cartHandler, receiptHandler, salesReportHandler)
+	reportSubscriptionService := service.NewReportSubscriptionService(repository.NewReportSubscriptionRepository(db), salesReportService, settingService, email.NewEmailSender(cfg))
+	reportSubscriptionHandler := handler.NewReportSubscriptionHandler(reportSubscriptionService)
+
+	settingHandler := handler.NewSettingHandler(settingService)
+
+	return router.PrivateRoutes(userHandler, adminHandler, productHandler, *orderHandler, cartHandler, receiptHandler, salesReportHandler, settingHandler, taxHandler, promotionHandler, couponHandler, loyaltyHandler, membershipHandler, giftCardHandler, shiftHandler, creditHandler, tabHandler, modifierHandler, reportSubscriptionHandler)
+}
+
+// receiptSigningKey is the key that signs receipt QR codes, the JWT secret
+// key when none is configured.
+func receiptSigningKey(cfg *configs.Config) string {
+	if cfg.Receipt.SigningKey != "" {
+		return cfg.Receipt.SigningKey
+	}
+	return cfg.JWT.SecretKey
 }
@@ -16,10 +16,11 @@ type Cart struct {
 }
 
 type CartItem struct {
-	CartItemID uuid.UUID `json:"cart_item_id" gorm:"type:uuid;primaryKey"`
-	CartID     uuid.UUID `json:"cart_id" gorm:"column:cart_id"`
-	ProductID  uuid.UUID `json:"product_id" gorm:"column:product_id"`
-	Quantity   int       `json:"quantity"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	CartItemID uuid.UUID          `json:"cart_item_id" gorm:"type:uuid;primaryKey"`
+	CartID     uuid.UUID          `json:"cart_id" gorm:"column:cart_id"`
+	ProductID  uuid.UUID          `json:"product_id" gorm:"column:product_id"`
+	Quantity   int                `json:"quantity"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	Modifiers  []CartItemModifier `json:"modifiers" gorm:"foreignKey:CartItemID"`
 }
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ShiftOpen   = "open"
+	ShiftClosed = "closed"
+)
+
+const (
+	CashSale   = "sale"
+	CashRefund = "refund"
+	CashPayIn  = "pay_in"
+	CashPayOut = "pay_out"
+)
+
+// CashShift is one cashier's session on a cash drawer. ExpectedCash,
+// CountedCash and Variance are set when the shift is closed.
+type CashShift struct {
+	ShiftID      uuid.UUID      `json:"shift_id" gorm:"type:uuid;primaryKey"`
+	CashierID    uuid.UUID      `json:"cashier_id" gorm:"column:cashier_id"`
+	Status       string         `json:"status" gorm:"column:status"`
+	OpeningFloat money.Amount   `json:"opening_float" gorm:"column:opening_float"`
+	ExpectedCash *money.Amount  `json:"expected_cash" gorm:"column:expected_cash"`
+	CountedCash  *money.Amount  `json:"counted_cash" gorm:"column:counted_cash"`
+	Variance     *money.Amount  `json:"variance" gorm:"column:variance"`
+	Note         string         `json:"note" gorm:"column:note"`
+	OpenedAt     time.Time      `json:"opened_at" gorm:"column:opened_at"`
+	ClosedAt     *time.Time     `json:"closed_at" gorm:"column:closed_at"`
+	Movements    []CashMovement `json:"movements,omitempty" gorm:"foreignKey:ShiftID"`
+}
+
+// CashMovement is cash going into or out of the drawer. Amount is always
+// positive, the type tells the direction.
+type CashMovement struct {
+	CashMovementID uuid.UUID    `json:"cash_movement_id" gorm:"type:uuid;primaryKey"`
+	ShiftID        uuid.UUID    `json:"shift_id" gorm:"column:shift_id"`
+	OrderID        *uuid.UUID   `json:"order_id" gorm:"column:order_id"`
+	Type           string       `json:"type" gorm:"column:type"`
+	Amount         money.Amount `json:"amount" gorm:"column:amount"`
+	Reason         string       `json:"reason" gorm:"column:reason"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// ShiftReport is the cash-up of a shift. Variance is counted minus expected
+// cash, so it is negative when the drawer is short.
+type ShiftReport struct {
+	Shift        CashShift           `json:"shift"`
+	CashierName  string              `json:"cashier_name"`
+	OpeningFloat money.Amount        `json:"opening_float"`
+	CashSales    money.Amount        `json:"cash_sales"`
+	CashRefunds  money.Amount        `json:"cash_refunds"`
+	PayIns       money.Amount        `json:"pay_ins"`
+	PayOuts      money.Amount        `json:"pay_outs"`
+	ExpectedCash money.Amount        `json:"expected_cash"`
+	CountedCash  *money.Amount       `json:"counted_cash"`
+	Variance     *money.Amount       `json:"variance"`
+	OrderCount   int64               `json:"order_count"`
+	Tenders      []PaymentMethodStat `json:"tenders"`
+}
+
+// ZReport is the end-of-day report over every shift opened that day.
+// Counted cash and variance only cover the closed shifts.
+type ZReport struct {
+	StoreName    string              `json:"store_name"`
+	Date         time.Time           `json:"date"`
+	ShiftCount   int                 `json:"shift_count"`
+	OpenShifts   int                 `json:"open_shifts"`
+	OpeningFloat money.Amount        `json:"opening_float"`
+	CashSales    money.Amount        `json:"cash_sales"`
+	CashRefunds  money.Amount        `json:"cash_refunds"`
+	PayIns       money.Amount        `json:"pay_ins"`
+	PayOuts      money.Amount        `json:"pay_outs"`
+	ExpectedCash money.Amount        `json:"expected_cash"`
+	CountedCash  money.Amount        `json:"counted_cash"`
+	Variance     money.Amount        `json:"variance"`
+	OrderCount   int64               `json:"order_count"`
+	Tenders      []PaymentMethodStat `json:"tenders"`
+	Shifts       []ShiftReport       `json:"shifts"`
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// CouponCode unlocks a promotion that has RequiresCode set. A nil
+// MaxRedemptions makes the code usable any number of times.
+type CouponCode struct {
+	CouponCodeID    uuid.UUID  `json:"coupon_code_id" gorm:"type:uuid;primaryKey"`
+	PromotionID     uuid.UUID  `json:"promotion_id" gorm:"column:promotion_id"`
+	Code            string     `json:"code" gorm:"column:code;unique"`
+	MaxRedemptions  *int       `json:"max_redemptions" gorm:"column:max_redemptions"`
+	RedemptionCount int        `json:"redemption_count" gorm:"column:redemption_count"`
+	ExpiresAt       *time.Time `json:"expires_at" gorm:"column:expires_at"`
+	Active          bool       `json:"active" gorm:"column:active"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type CouponRedemption struct {
+	CouponRedemptionID uuid.UUID    `json:"coupon_redemption_id" gorm:"type:uuid;primaryKey"`
+	CouponCodeID       uuid.UUID    `json:"coupon_code_id" gorm:"column:coupon_code_id"`
+	PromotionID        uuid.UUID    `json:"promotion_id" gorm:"column:promotion_id"`
+	OrderID            uuid.UUID    `json:"order_id" gorm:"column:order_id"`
+	UserID             uuid.UUID    `json:"user_id" gorm:"column:user_id"`
+	DiscountAmount     money.Amount `json:"discount_amount" gorm:"column:discount_amount"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+type CouponRedemptionReport struct {
+	PromotionID      uuid.UUID        `json:"promotion_id"`
+	PromotionName    string           `json:"promotion_name"`
+	TotalCodes       int64            `json:"total_codes"`
+	RedeemedCodes    int64            `json:"redeemed_codes"`
+	TotalRedemptions int64            `json:"total_redemptions"`
+	UniqueCustomers  int64            `json:"unique_customers"`
+	TotalDiscount    money.Amount     `json:"total_discount"`
+	Codes            []CouponCodeStat `json:"codes"`
+}
+
+type CouponCodeStat struct {
+	Code           string       `json:"code"`
+	Redemptions    int64        `json:"redemptions"`
+	TotalDiscount  money.Amount `json:"total_discount"`
+	LastRedeemedAt *time.Time   `json:"last_redeemed_at"`
+}
@@ -0,0 +1,115 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CreditActive    = "active"
+	CreditSuspended = "suspended"
+)
+
+const (
+	InvoiceOpen = "open"
+	InvoicePaid = "paid"
+	InvoiceVoid = "void"
+)
+
+const (
+	CreditPaymentCash     = "cash"
+	CreditPaymentTransfer = "transfer"
+)
+
+// CreditAccount lets an approved customer pay later. Balance is what is
+// still owed on open invoices and may not go over CreditLimit.
+type CreditAccount struct {
+	UserID           uuid.UUID    `json:"user_id" gorm:"type:uuid;primaryKey"`
+	CreditLimit      money.Amount `json:"credit_limit" gorm:"column:credit_limit"`
+	Balance          money.Amount `json:"balance" gorm:"column:balance"`
+	PaymentTermsDays int          `json:"payment_terms_days" gorm:"column:payment_terms_days"`
+	Status           string       `json:"status" gorm:"column:status"`
+	ApprovedAt       time.Time    `json:"approved_at" gorm:"column:approved_at"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// Available is how much more the customer can buy on credit.
+func (a *CreditAccount) Available() money.Amount {
+	return money.Max(a.CreditLimit.Sub(a.Balance), money.Zero)
+}
+
+// CreditInvoice is the amount an order charged to a credit account.
+type CreditInvoice struct {
+	InvoiceID  uuid.UUID    `json:"invoice_id" gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID    `json:"user_id" gorm:"column:user_id"`
+	OrderID    uuid.UUID    `json:"order_id" gorm:"column:order_id"`
+	Amount     money.Amount `json:"amount" gorm:"column:amount"`
+	PaidAmount money.Amount `json:"paid_amount" gorm:"column:paid_amount"`
+	Status     string       `json:"status" gorm:"column:status"`
+	IssuedAt   time.Time    `json:"issued_at" gorm:"column:issued_at"`
+	DueDate    time.Time    `json:"due_date" gorm:"column:due_date"`
+	PaidAt     *time.Time   `json:"paid_at" gorm:"column:paid_at"`
+}
+
+func (i *CreditInvoice) Outstanding() money.Amount {
+	return i.Amount.Sub(i.PaidAmount)
+}
+
+// CreditPayment is money received from a customer, spread over invoices by
+// its allocations.
+type CreditPayment struct {
+	CreditPaymentID uuid.UUID          `json:"credit_payment_id" gorm:"type:uuid;primaryKey"`
+	UserID          uuid.UUID          `json:"user_id" gorm:"column:user_id"`
+	Amount          money.Amount       `json:"amount" gorm:"column:amount"`
+	Method          string             `json:"method" gorm:"column:method"`
+	Reference       string             `json:"reference" gorm:"column:reference"`
+	Note            string             `json:"note" gorm:"column:note"`
+	ReceivedBy      *uuid.UUID         `json:"received_by" gorm:"column:received_by"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Allocations     []CreditAllocation `json:"allocations" gorm:"foreignKey:CreditPaymentID"`
+}
+
+type CreditAllocation struct {
+	CreditAllocationID uuid.UUID    `json:"credit_allocation_id" gorm:"type:uuid;primaryKey"`
+	CreditPaymentID    uuid.UUID    `json:"credit_payment_id" gorm:"column:credit_payment_id"`
+	InvoiceID          uuid.UUID    `json:"invoice_id" gorm:"column:invoice_id"`
+	Amount             money.Amount `json:"amount" gorm:"column:amount"`
+}
+
+// AgingBuckets split what is owed by the age of the invoice in days.
+type AgingBuckets struct {
+	Days0To30  money.Amount `json:"days_0_30"`
+	Days31To60 money.Amount `json:"days_31_60"`
+	Days61To90 money.Amount `json:"days_61_90"`
+	Over90     money.Amount `json:"over_90"`
+	Total      money.Amount `json:"total"`
+}
+
+type CustomerAging struct {
+	UserID      uuid.UUID    `json:"user_id"`
+	Fullname    string       `json:"fullname"`
+	Email       string       `json:"email"`
+	CreditLimit money.Amount `json:"credit_limit"`
+	AgingBuckets
+}
+
+type AgingReport struct {
+	AsOf      time.Time       `json:"as_of"`
+	Totals    AgingBuckets    `json:"totals"`
+	Customers []CustomerAging `json:"customers"`
+}
+
+// CreditStatement lists what a customer owes and what they paid recently.
+type CreditStatement struct {
+	Account       CreditAccount   `json:"account"`
+	Fullname      string          `json:"fullname"`
+	Email         string          `json:"email"`
+	StatementDate time.Time       `json:"statement_date"`
+	Aging         AgingBuckets    `json:"aging"`
+	OpenInvoices  []CreditInvoice `json:"open_invoices"`
+	Payments      []CreditPayment `json:"payments"`
+}
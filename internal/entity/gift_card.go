@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// GiftCardPending cards were sold on an order that is not paid yet
+	GiftCardPending = "pending"
+	GiftCardActive  = "active"
+	GiftCardVoid    = "void"
+)
+
+const (
+	GiftCardIssue         = "issue"
+	GiftCardRedeem        = "redeem"
+	GiftCardTopUp         = "top_up"
+	GiftCardReverseRedeem = "reverse_redeem"
+	GiftCardVoided        = "void"
+)
+
+type GiftCard struct {
+	GiftCardID   uuid.UUID             `json:"gift_card_id" gorm:"type:uuid;primaryKey"`
+	Code         string                `json:"code" gorm:"column:code"`
+	InitialValue money.Amount          `json:"initial_value" gorm:"column:initial_value"`
+	Balance      money.Amount          `json:"balance" gorm:"column:balance"`
+	ExpiresAt    *time.Time            `json:"expires_at" gorm:"column:expires_at"`
+	Status       string                `json:"status" gorm:"column:status"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+	Transactions []GiftCardTransaction `json:"transactions,omitempty" gorm:"foreignKey:GiftCardID"`
+}
+
+// IsUsableAt reports whether the card can pay for an order at the given time.
+func (g *GiftCard) IsUsableAt(at time.Time) bool {
+	if g.Status != GiftCardActive {
+		return false
+	}
+	return g.ExpiresAt == nil || at.Before(*g.ExpiresAt)
+}
+
+// MaskedCode only shows the last four characters, for receipts.
+func (g *GiftCard) MaskedCode() string {
+	if len(g.Code) <= 4 {
+		return g.Code
+	}
+	return "****" + g.Code[len(g.Code)-4:]
+}
+
+// GiftCardTransaction is one ledger entry. Amount is positive when the
+// balance goes up and negative when it goes down.
+type GiftCardTransaction struct {
+	GiftCardTransactionID uuid.UUID    `json:"gift_card_transaction_id" gorm:"type:uuid;primaryKey"`
+	GiftCardID            uuid.UUID    `json:"gift_card_id" gorm:"column:gift_card_id"`
+	OrderID               *uuid.UUID   `json:"order_id" gorm:"column:order_id"`
+	Type                  string       `json:"type" gorm:"column:type"`
+	Amount                money.Amount `json:"amount" gorm:"column:amount"`
+	BalanceAfter          money.Amount `json:"balance_after" gorm:"column:balance_after"`
+	Note                  string       `json:"note" gorm:"column:note"`
+	CreatedAt             time.Time    `json:"created_at"`
+}
+
+// GiftCardBalance is what a customer or cashier sees when checking a card.
+type GiftCardBalance struct {
+	Code      string       `json:"code"`
+	Balance   money.Amount `json:"balance"`
+	ExpiresAt *time.Time   `json:"expires_at"`
+	Status    string       `json:"status"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LoyaltyEarn          = "earn"
+	LoyaltyRedeem        = "redeem"
+	LoyaltyReverseEarn   = "reverse_earn"
+	LoyaltyReverseRedeem = "reverse_redeem"
+)
+
+type LoyaltyAccount struct {
+	UserID        uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey"`
+	PointsBalance int       `json:"points_balance" gorm:"column:points_balance"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// LoyaltyTransaction is one ledger entry. Points are positive when credited
+// and negative when debited.
+type LoyaltyTransaction struct {
+	LoyaltyTransactionID uuid.UUID  `json:"loyalty_transaction_id" gorm:"type:uuid;primaryKey"`
+	UserID               uuid.UUID  `json:"user_id" gorm:"column:user_id"`
+	OrderID              *uuid.UUID `json:"order_id" gorm:"column:order_id"`
+	Type                 string     `json:"type" gorm:"column:type"`
+	Points               int        `json:"points" gorm:"column:points"`
+	BalanceAfter         int        `json:"balance_after" gorm:"column:balance_after"`
+	Description          string     `json:"description" gorm:"column:description"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+type LoyaltyBalance struct {
+	UserID     uuid.UUID    `json:"user_id"`
+	Points     int          `json:"points"`
+	PointValue money.Amount `json:"point_value"`
+	Value      money.Amount `json:"value"`
+}
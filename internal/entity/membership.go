@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// MembershipTier is reached by spending at least MinSpend within the rolling
+// membership window. Members get DiscountRate off every order.
+type MembershipTier struct {
+	TierID       uuid.UUID    `json:"tier_id" gorm:"type:uuid;primaryKey"`
+	Name         string       `json:"name" gorm:"column:name"`
+	MinSpend     money.Amount `json:"min_spend" gorm:"column:min_spend"`
+	DiscountRate float64      `json:"discount_rate" gorm:"column:discount_rate"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+type UserMembership struct {
+	UserID          uuid.UUID       `json:"user_id" gorm:"type:uuid;primaryKey"`
+	TierID          *uuid.UUID      `json:"tier_id" gorm:"column:tier_id"`
+	Tier            *MembershipTier `json:"tier" gorm:"foreignKey:TierID;references:TierID"`
+	QualifyingSpend money.Amount    `json:"qualifying_spend" gorm:"column:qualifying_spend"`
+	EvaluatedAt     time.Time       `json:"evaluated_at" gorm:"column:evaluated_at"`
+}
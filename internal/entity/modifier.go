@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// ModifierGroup is a choice made for a product, such as size or sugar
+// level. A required group needs at least one option, and MinSelect and
+// MaxSelect bound how many options can be chosen.
+type ModifierGroup struct {
+	ModifierGroupID uuid.UUID        `json:"modifier_group_id" gorm:"type:uuid;primaryKey"`
+	Name            string           `json:"name" gorm:"column:name"`
+	IsRequired      bool             `json:"is_required" gorm:"column:is_required"`
+	MinSelect       int              `json:"min_select" gorm:"column:min_select"`
+	MaxSelect       int              `json:"max_select" gorm:"column:max_select"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	Options         []ModifierOption `json:"options" gorm:"foreignKey:ModifierGroupID"`
+}
+
+// ModifierOption is one choice of a group. PriceDelta is added to the unit
+// price of the product and may be negative.
+type ModifierOption struct {
+	ModifierOptionID uuid.UUID    `json:"modifier_option_id" gorm:"type:uuid;primaryKey"`
+	ModifierGroupID  uuid.UUID    `json:"modifier_group_id" gorm:"column:modifier_group_id"`
+	Name             string       `json:"name" gorm:"column:name"`
+	PriceDelta       money.Amount `json:"price_delta" gorm:"column:price_delta"`
+	IsActive         bool         `json:"is_active" gorm:"column:is_active"`
+	SortOrder        int          `json:"sort_order" gorm:"column:sort_order"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// ProductModifierGroup attaches a group to a product.
+type ProductModifierGroup struct {
+	ProductID       uuid.UUID `json:"product_id" gorm:"type:uuid;primaryKey"`
+	ModifierGroupID uuid.UUID `json:"modifier_group_id" gorm:"type:uuid;primaryKey"`
+	SortOrder       int       `json:"sort_order" gorm:"column:sort_order"`
+}
+
+// CartItemModifier is an option chosen for a cart item.
+type CartItemModifier struct {
+	CartItemID       uuid.UUID       `json:"cart_item_id" gorm:"type:uuid;primaryKey"`
+	ModifierOptionID uuid.UUID       `json:"modifier_option_id" gorm:"type:uuid;primaryKey"`
+	Option           *ModifierOption `json:"option,omitempty" gorm:"foreignKey:ModifierOptionID"`
+}
+
+// TabItemModifier is an option chosen for a tab item.
+type TabItemModifier struct {
+	TabItemID        uuid.UUID       `json:"tab_item_id" gorm:"type:uuid;primaryKey"`
+	ModifierOptionID uuid.UUID       `json:"modifier_option_id" gorm:"type:uuid;primaryKey"`
+	Option           *ModifierOption `json:"option,omitempty" gorm:"foreignKey:ModifierOptionID"`
+}
+
+// OrderItemModifier is an option sold with an order line. The names and price
+// are copied so the order does not change when the option does.
+type OrderItemModifier struct {
+	OrderItemModifierID uuid.UUID    `json:"order_item_modifier_id" gorm:"type:uuid;primaryKey"`
+	OrderItemID         uuid.UUID    `json:"order_item_id" gorm:"column:orderitem_id"`
+	ModifierOptionID    *uuid.UUID   `json:"modifier_option_id" gorm:"column:modifier_option_id"`
+	GroupName           string       `json:"group_name" gorm:"column:group_name"`
+	OptionName          string       `json:"option_name" gorm:"column:option_name"`
+	PriceDelta          money.Amount `json:"price_delta" gorm:"column:price_delta"`
+}
+
+// ReceiptItemModifier is a line printed under a receipt item, such as
+// "Size: Large".
+type ReceiptItemModifier struct {
+	ReceiptItemModifierID uuid.UUID    `json:"receipt_item_modifier_id" gorm:"type:uuid;primaryKey"`
+	ReceiptItemID         uuid.UUID    `json:"receipt_item_id" gorm:"column:receipt_item_id"`
+	Name                  string       `json:"name" gorm:"column:name"`
+	PriceDelta            money.Amount `json:"price_delta" gorm:"column:price_delta"`
+}
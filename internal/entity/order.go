@@ -3,31 +3,92 @@ package entity
 import (
 	"time"
 
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
 	"github.com/google/uuid"
 )
 
 type Order struct {
-	OrderID       uuid.UUID   `json:"order_id" gorm:"type:uuid;primaryKey"`
-	UserID        uuid.UUID   `json:"user_id" gorm:"column:user_id"`
-	TotalPrice    float64     `json:"total_price"`
-	PaymentMethod string      `json:"payment_method" gorm:"column:payment_method"`
-	PaidAmount    float64     `json:"paid_amount" gorm:"column:paid_amount"`
-	ChangeAmount  float64     `json:"change_amount" gorm:"column:change_amount"`
-	Status        string      `json:"status" gorm:"default:'pending'"`
-	CreatedAt     time.Time   `json:"created_at"`
-	UpdatedAt     time.Time   `json:"updated_at"`
-	OrderItems    []OrderItem `json:"order_items" gorm:"foreignKey:OrderID"`
-	SnapToken     string      `json:"snap_token" gorm:"-"`
-	RedirectURL   string      `json:"redirect_url" gorm:"-"`
+	OrderID        uuid.UUID    `json:"order_id" gorm:"type:uuid;primaryKey"`
+	UserID         uuid.UUID    `json:"user_id" gorm:"column:user_id"`
+	TotalPrice     money.Amount `json:"total_price"`
+	TaxAmount      money.Amount `json:"tax_amount" gorm:"column:tax_amount"`
+	DiscountAmount money.Amount `json:"discount_amount" gorm:"column:discount_amount"`
+	CouponCode     string       `json:"coupon_code" gorm:"column:coupon_code"`
+	// ServiceChargeAmount and TipAmount are part of TotalPrice but not of the
+	// product lines. The tip goes to the cashier of the order.
+	ServiceChargeRate   float64      `json:"service_charge_rate" gorm:"column:service_charge_rate"`
+	ServiceChargeAmount money.Amount `json:"service_charge_amount" gorm:"column:service_charge_amount"`
+	TipAmount           money.Amount `json:"tip_amount" gorm:"column:tip_amount"`
+	// TierDiscountAmount is the part of DiscountAmount given by the membership tier
+	MembershipTierID   *uuid.UUID   `json:"membership_tier_id" gorm:"column:membership_tier_id"`
+	TierDiscountAmount money.Amount `json:"tier_discount_amount" gorm:"column:tier_discount_amount"`
+	PointsRedeemed     int          `json:"points_redeemed" gorm:"column:points_redeemed"`
+	PointsAmount       money.Amount `json:"points_amount" gorm:"column:points_amount"`
+	GiftCardID         *uuid.UUID   `json:"gift_card_id" gorm:"column:gift_card_id"`
+	GiftCardAmount     money.Amount `json:"gift_card_amount" gorm:"column:gift_card_amount"`
+	PaymentMethod      string       `json:"payment_method" gorm:"column:payment_method"`
+	PaidAmount         money.Amount `json:"paid_amount" gorm:"column:paid_amount"`
+	ChangeAmount       money.Amount `json:"change_amount" gorm:"column:change_amount"`
+	CashierID          *uuid.UUID   `json:"cashier_id" gorm:"column:cashier_id"`
+	ShiftID            *uuid.UUID   `json:"shift_id" gorm:"column:shift_id"`
+	TabID              *uuid.UUID   `json:"tab_id" gorm:"column:tab_id"`
+	Status             string       `json:"status" gorm:"default:'pending'"`
+	// FulfilmentStatus is how far the order is on the order display. It moves
+	// apart from Status, which follows the payment.
+	FulfilmentStatus    string           `json:"fulfilment_status" gorm:"column:fulfilment_status;default:'queued'"`
+	FulfilmentUpdatedAt *time.Time       `json:"fulfilment_updated_at" gorm:"column:fulfilment_updated_at"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+	OrderItems          []OrderItem      `json:"order_items" gorm:"foreignKey:OrderID"`
+	Promotions          []OrderPromotion `json:"promotions" gorm:"foreignKey:OrderID"`
+	Payments            []OrderPayment   `json:"payments" gorm:"foreignKey:OrderID"`
+	// GiftCardCode is the card to pay with and GiftCardLimit the most to take
+	// from it, zero for as much as is needed. GiftCardSales are the values of
+	// gift cards being bought.
+	GiftCardCode  string         `json:"-" gorm:"-"`
+	GiftCardLimit money.Amount   `json:"-" gorm:"-"`
+	GiftCardSales []money.Amount `json:"-" gorm:"-"`
+	SnapToken     string         `json:"snap_token" gorm:"-"`
+	RedirectURL   string         `json:"redirect_url" gorm:"-"`
+	// TabVersion is the version of the tab the order was priced from
+	TabVersion int `json:"-" gorm:"-"`
+}
+
+// AmountDue is what is left to pay after redeemed loyalty points and gift card.
+func (o *Order) AmountDue() money.Amount {
+	return o.TotalPrice.Sub(o.PointsAmount).Sub(o.GiftCardAmount)
 }
 
+const (
+	FulfilmentQueued    = "queued"
+	FulfilmentPreparing = "preparing"
+	FulfilmentReady     = "ready"
+	FulfilmentServed    = "served"
+)
+
+const (
+	OrderItemProduct  = "product"
+	OrderItemGiftCard = "gift_card"
+)
+
 type OrderItem struct {
-	OrderItemID  uuid.UUID `json:"order_item_id" gorm:"column:orderitem_id;type:uuid;primaryKey"`
-	OrderID      uuid.UUID `json:"order_id"`
-	ProductID    uuid.UUID `json:"product_id"`
-	Quantity     int       `json:"quantity"`
-	PricePerItem float64   `json:"price_per_item"`
-	TotalPrice   float64   `json:"total_price"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	OrderItemID    uuid.UUID    `json:"order_item_id" gorm:"column:orderitem_id;type:uuid;primaryKey"`
+	OrderID        uuid.UUID    `json:"order_id"`
+	ItemType       string       `json:"item_type" gorm:"column:item_type"`
+	ProductID      *uuid.UUID   `json:"product_id"`
+	GiftCardID     *uuid.UUID   `json:"gift_card_id,omitempty" gorm:"column:gift_card_id"`
+	GiftCard       *GiftCard    `json:"gift_card,omitempty" gorm:"foreignKey:GiftCardID"`
+	Quantity       int          `json:"quantity"`
+	PricePerItem   money.Amount `json:"price_per_item"`
+	TotalPrice     money.Amount `json:"total_price"`
+	DiscountAmount money.Amount `json:"discount_amount" gorm:"column:discount_amount"`
+	TaxClassID     *uuid.UUID   `json:"tax_class_id" gorm:"column:tax_class_id"`
+	TaxRate        float64      `json:"tax_rate" gorm:"column:tax_rate"`
+	TaxAmount      money.Amount `json:"tax_amount" gorm:"column:tax_amount"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	// Modifiers are the options chosen for the line. PricePerItem includes
+	// their price deltas.
+	Modifiers []OrderItemModifier `json:"modifiers" gorm:"foreignKey:OrderItemID"`
 }
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OrderEventCreated    = "order.created"
+	OrderEventStatus     = "order.status"
+	OrderEventFulfilment = "order.fulfilment"
+)
+
+// OrderEvent is pushed to the order displays when an order is created or its
+// status or fulfilment status changes. Order is only sent with created events.
+type OrderEvent struct {
+	Type             string    `json:"type"`
+	OrderID          uuid.UUID `json:"order_id"`
+	Status           string    `json:"status"`
+	FulfilmentStatus string    `json:"fulfilment_status"`
+	Order            *Order    `json:"order,omitempty"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
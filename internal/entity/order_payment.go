@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PaymentCash     = "cash"
+	PaymentMidtrans = "midtrans"
+	PaymentGiftCard = "gift_card"
+	PaymentPoints   = "points"
+	// PaymentCredit charges the order to the customer's credit account
+	PaymentCredit = "credit"
+	// PaymentSplit is the payment_method of an order paid with more than one tender
+	PaymentSplit = "split"
+)
+
+const (
+	PaymentPending   = "pending"
+	PaymentSettled   = "settled"
+	PaymentCancelled = "cancelled"
+	PaymentRefunded  = "refunded"
+)
+
+// OrderPayment is one tender of an order. Amount is the part of the order it
+// pays. For cash, TenderedAmount is what the customer handed over and
+// ChangeAmount what was given back.
+type OrderPayment struct {
+	OrderPaymentID uuid.UUID    `json:"order_payment_id" gorm:"type:uuid;primaryKey"`
+	OrderID        uuid.UUID    `json:"order_id" gorm:"column:order_id"`
+	Method         string       `json:"method" gorm:"column:method"`
+	Amount         money.Amount `json:"amount" gorm:"column:amount"`
+	TenderedAmount money.Amount `json:"tendered_amount" gorm:"column:tendered_amount"`
+	ChangeAmount   money.Amount `json:"change_amount" gorm:"column:change_amount"`
+	Status         string       `json:"status" gorm:"column:status"`
+	SettledAt      *time.Time   `json:"settled_at" gorm:"column:settled_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	// GiftCardCode is the card a requested gift card tender pays with
+	GiftCardCode string `json:"-" gorm:"-"`
+}
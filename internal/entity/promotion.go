@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PromotionOrderPercentage    = "order_percentage"
+	PromotionOrderFixed         = "order_fixed"
+	PromotionProductPercentage  = "product_percentage"
+	PromotionProductFixed       = "product_fixed"
+	PromotionCategoryPercentage = "category_percentage"
+	PromotionCategoryFixed      = "category_fixed"
+	PromotionBuyXGetY           = "buy_x_get_y"
+)
+
+// Promotion is a discount rule. Percentage is used by the *_percentage types,
+// Amount by the *_fixed types (per unit for product and category rules), and
+// MinSpend is an extra condition on the order subtotal. Promotions with
+// RequiresCode set are coupon campaigns and only apply through a CouponCode.
+type Promotion struct {
+	PromotionID      uuid.UUID    `json:"promotion_id" gorm:"type:uuid;primaryKey"`
+	Name             string       `json:"name" gorm:"column:name"`
+	Type             string       `json:"type" gorm:"column:type"`
+	Percentage       float64      `json:"percentage" gorm:"column:percentage"`
+	Amount           money.Amount `json:"amount" gorm:"column:amount"`
+	ProductID        *uuid.UUID   `json:"product_id" gorm:"column:product_id"`
+	Category         string       `json:"category" gorm:"column:category"`
+	BuyQuantity      int          `json:"buy_quantity" gorm:"column:buy_quantity"`
+	GetQuantity      int          `json:"get_quantity" gorm:"column:get_quantity"`
+	MinSpend         money.Amount `json:"min_spend" gorm:"column:min_spend"`
+	StartsAt         time.Time    `json:"starts_at" gorm:"column:starts_at"`
+	EndsAt           *time.Time   `json:"ends_at" gorm:"column:ends_at"`
+	UsageLimit       *int         `json:"usage_limit" gorm:"column:usage_limit"`
+	UsageCount       int          `json:"usage_count" gorm:"column:usage_count"`
+	Stackable        bool         `json:"stackable" gorm:"column:stackable"`
+	RequiresCode     bool         `json:"requires_code" gorm:"column:requires_code"`
+	PerCustomerLimit *int         `json:"per_customer_limit" gorm:"column:per_customer_limit"`
+	Priority         int          `json:"priority" gorm:"column:priority"`
+	Active           bool         `json:"active" gorm:"column:active"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// IsAvailableAt reports whether the promotion can still be applied at the given time.
+func (p Promotion) IsAvailableAt(at time.Time) bool {
+	if !p.Active || at.Before(p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return false
+	}
+	return p.UsageLimit == nil || p.UsageCount < *p.UsageLimit
+}
+
+// IsOrderLevel reports whether the promotion discounts the whole order
+// rather than specific lines.
+func (p Promotion) IsOrderLevel() bool {
+	return p.Type == PromotionOrderPercentage || p.Type == PromotionOrderFixed
+}
+
+type OrderPromotion struct {
+	OrderPromotionID uuid.UUID    `json:"order_promotion_id" gorm:"type:uuid;primaryKey"`
+	OrderID          uuid.UUID    `json:"order_id" gorm:"column:order_id"`
+	PromotionID      uuid.UUID    `json:"promotion_id" gorm:"column:promotion_id"`
+	PromotionName    string       `json:"promotion_name" gorm:"-"`
+	DiscountAmount   money.Amount `json:"discount_amount" gorm:"column:discount_amount"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
@@ -3,34 +3,123 @@ package entity
 import (
 	"time"
 
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
 	"github.com/google/uuid"
 )
 
+const (
+	ReceiptPaperA4   = "a4"
+	ReceiptPaper80mm = "80mm"
+	// ReceiptPaper58mm is only printed by thermal printers
+	ReceiptPaper58mm = "58mm"
+)
+
 type Receipt struct {
-	ReceiptID     uuid.UUID     `json:"receipt_id" gorm:"type:uuid;primaryKey"`
-	OrderID       uuid.UUID     `json:"order_id" gorm:"column:order_id"`
-	UserID        uuid.UUID     `json:"user_id" gorm:"column:user_id"`
-	Subtotal      float64       `json:"subtotal" gorm:"column:subtotal"`
-	TaxAmount     float64       `json:"tax_amount" gorm:"column:tax_amount"`
-	TotalAmount   float64       `json:"total_amount" gorm:"column:total_amount"`
-	PaymentMethod string        `json:"payment_method" gorm:"column:payment_method"`
-	PaymentStatus string        `json:"payment_status" gorm:"column:payment_status"`
-	ReceiptNumber string        `json:"receipt_number" gorm:"column:receipt_number;unique"`
-	CashierName   string        `json:"cashier_name" gorm:"column:cashier_name"`
-	StoreName     string        `json:"store_name" gorm:"column:store_name"`
-	StoreAddress  string        `json:"store_address" gorm:"column:store_address"`
-	StorePhone    string        `json:"store_phone" gorm:"column:store_phone"`
-	ReceiptItems  []ReceiptItem `json:"receipt_items" gorm:"foreignKey:ReceiptID"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
+	ReceiptID      uuid.UUID    `json:"receipt_id" gorm:"type:uuid;primaryKey"`
+	OrderID        uuid.UUID    `json:"order_id" gorm:"column:order_id"`
+	UserID         uuid.UUID    `json:"user_id" gorm:"column:user_id"`
+	Subtotal       money.Amount `json:"subtotal" gorm:"column:subtotal"`
+	DiscountAmount money.Amount `json:"discount_amount" gorm:"column:discount_amount"`
+	TaxAmount      money.Amount `json:"tax_amount" gorm:"column:tax_amount"`
+	// ServiceChargeAmount and TipAmount are included in TotalAmount
+	ServiceChargeAmount money.Amount  `json:"service_charge_amount" gorm:"column:service_charge_amount"`
+	TipAmount           money.Amount  `json:"tip_amount" gorm:"column:tip_amount"`
+	TotalAmount         money.Amount  `json:"total_amount" gorm:"column:total_amount"`
+	PaymentMethod       string        `json:"payment_method" gorm:"column:payment_method"`
+	PaymentStatus       string        `json:"payment_status" gorm:"column:payment_status"`
+	ReceiptNumber       string        `json:"receipt_number" gorm:"column:receipt_number;unique"`
+	CashierName         string        `json:"cashier_name" gorm:"column:cashier_name"`
+	StoreName           string        `json:"store_name" gorm:"column:store_name"`
+	StoreAddress        string        `json:"store_address" gorm:"column:store_address"`
+	StorePhone          string        `json:"store_phone" gorm:"column:store_phone"`
+	ReceiptItems        []ReceiptItem `json:"receipt_items" gorm:"foreignKey:ReceiptID"`
+	// Payments are the tenders of the order, read from order_payments
+	Payments []OrderPayment `json:"payments" gorm:"foreignKey:OrderID;references:OrderID"`
+	// A voided receipt is kept and printed with a VOID marker
+	VoidedAt   *time.Time `json:"voided_at" gorm:"column:voided_at"`
+	VoidReason string     `json:"void_reason,omitempty" gorm:"column:void_reason"`
+	VoidedBy   *uuid.UUID `json:"voided_by,omitempty" gorm:"column:voided_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	// PrintNumber is set while the receipt is printed. 0 is the original and
+	// anything higher is a reprint.
+	PrintNumber int `json:"-" gorm:"-"`
+	// VerifyURL is the signed link printed as the QR code of the receipt
+	VerifyURL string `json:"-" gorm:"-"`
 }
 
 type ReceiptItem struct {
-	ReceiptItemID uuid.UUID `json:"receipt_item_id" gorm:"type:uuid;primaryKey"`
-	ReceiptID     uuid.UUID `json:"receipt_id" gorm:"column:receipt_id"`
-	ProductName   string    `json:"product_name" gorm:"column:product_name"`
-	Quantity      int       `json:"quantity" gorm:"column:quantity"`
-	UnitPrice     float64   `json:"unit_price" gorm:"column:unit_price"`
-	TotalPrice    float64   `json:"total_price" gorm:"column:total_price"`
-	CreatedAt     time.Time `json:"created_at"`
+	ReceiptItemID  uuid.UUID    `json:"receipt_item_id" gorm:"type:uuid;primaryKey"`
+	ReceiptID      uuid.UUID    `json:"receipt_id" gorm:"column:receipt_id"`
+	ProductName    string       `json:"product_name" gorm:"column:product_name"`
+	Quantity       int          `json:"quantity" gorm:"column:quantity"`
+	UnitPrice      money.Amount `json:"unit_price" gorm:"column:unit_price"`
+	TotalPrice     money.Amount `json:"total_price" gorm:"column:total_price"`
+	DiscountAmount money.Amount `json:"discount_amount" gorm:"column:discount_amount"`
+	CreatedAt      time.Time    `json:"created_at"`
+	// Modifiers are printed under the item line
+	Modifiers []ReceiptItemModifier `json:"modifiers" gorm:"foreignKey:ReceiptItemID"`
+}
+
+// ReceiptVerification is what the public verify endpoint tells about a
+// receipt. It leaves out the customer, the cashier and the items.
+type ReceiptVerification struct {
+	ReceiptNumber string       `json:"receipt_number"`
+	StoreName     string       `json:"store_name"`
+	TotalAmount   money.Amount `json:"total_amount"`
+	PaymentStatus string       `json:"payment_status"`
+	ItemCount     int          `json:"item_count"`
+	IssuedAt      time.Time    `json:"issued_at"`
+	Voided        bool         `json:"voided"`
+}
+
+// ReceiptPrint records one print of a receipt, in the format it was printed.
+type ReceiptPrint struct {
+	PrintID     uuid.UUID  `json:"print_id" gorm:"type:uuid;primaryKey"`
+	ReceiptID   uuid.UUID  `json:"receipt_id" gorm:"column:receipt_id"`
+	PrintNumber int        `json:"print_number" gorm:"column:print_number"`
+	Format      string     `json:"format" gorm:"column:format"`
+	PrintedBy   *uuid.UUID `json:"printed_by" gorm:"column:printed_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ReceiptAuditReport lists the voids and reprints between two days, per
+// cashier. Voids are counted against the cashier on the receipt and reprints
+// against the user who printed them.
+type ReceiptAuditReport struct {
+	From         time.Time             `json:"from"`
+	To           time.Time             `json:"to"`
+	VoidCount    int                   `json:"void_count"`
+	VoidAmount   money.Amount          `json:"void_amount"`
+	ReprintCount int                   `json:"reprint_count"`
+	Cashiers     []CashierReceiptAudit `json:"cashiers"`
+}
+
+type CashierReceiptAudit struct {
+	CashierName  string                `json:"cashier_name"`
+	VoidCount    int                   `json:"void_count"`
+	VoidAmount   money.Amount          `json:"void_amount"`
+	ReprintCount int                   `json:"reprint_count"`
+	Voids        []ReceiptVoidEntry    `json:"voids"`
+	Reprints     []ReceiptReprintEntry `json:"reprints"`
+}
+
+type ReceiptVoidEntry struct {
+	ReceiptID     uuid.UUID    `json:"receipt_id"`
+	ReceiptNumber string       `json:"receipt_number"`
+	CashierName   string       `json:"-"`
+	TotalAmount   money.Amount `json:"total_amount"`
+	VoidReason    string       `json:"void_reason"`
+	VoidedByName  string       `json:"voided_by_name"`
+	VoidedAt      time.Time    `json:"voided_at"`
+}
+
+type ReceiptReprintEntry struct {
+	ReceiptID     uuid.UUID `json:"receipt_id"`
+	ReceiptNumber string    `json:"receipt_number"`
+	PrintNumber   int       `json:"print_number"`
+	Format        string    `json:"format"`
+	PrintedByName string    `json:"-"`
+	PrintedAt     time.Time `json:"printed_at"`
 }
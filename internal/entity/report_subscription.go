@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report types that can be subscribed to. A daily subscription is sent every
+// day for the day before, a monthly one on the 1st for the month before.
+const (
+	ReportTypeDaily   = "daily"
+	ReportTypeMonthly = "monthly"
+)
+
+const (
+	ReportDeliveryPending = "pending"
+	ReportDeliverySent    = "sent"
+	ReportDeliveryFailed  = "failed"
+)
+
+// ReportSubscription emails a sales report to Recipients, a comma separated
+// list of addresses, at SendHour (0-23) once the period has closed. The
+// report is attached in Format, csv or xlsx.
+type ReportSubscription struct {
+	SubscriptionID uuid.UUID  `json:"subscription_id" gorm:"type:uuid;primaryKey"`
+	ReportType     string     `json:"report_type" gorm:"column:report_type"`
+	SendHour       int        `json:"send_hour" gorm:"column:send_hour"`
+	Recipients     string     `json:"recipients" gorm:"column:recipients"`
+	Format         string     `json:"format" gorm:"column:format"`
+	Active         bool       `json:"active" gorm:"column:active"`
+	CreatedBy      *uuid.UUID `json:"created_by" gorm:"column:created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// RecipientList splits Recipients into addresses.
+func (s ReportSubscription) RecipientList() []string {
+	var list []string
+	for _, recipient := range strings.Split(s.Recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			list = append(list, recipient)
+		}
+	}
+	return list
+}
+
+// ReportDelivery is the report of one period of a subscription. A failed
+// send is tried again at NextAttemptAt until it is sent or gives up as
+// failed; LastError keeps the reason of the last failure.
+type ReportDelivery struct {
+	DeliveryID     uuid.UUID  `json:"delivery_id" gorm:"type:uuid;primaryKey"`
+	SubscriptionID uuid.UUID  `json:"subscription_id" gorm:"column:subscription_id"`
+	PeriodStart    time.Time  `json:"period_start" gorm:"column:period_start"`
+	PeriodEnd      time.Time  `json:"period_end" gorm:"column:period_end"`
+	Status         string     `json:"status" gorm:"column:status"`
+	Attempts       int        `json:"attempts" gorm:"column:attempts"`
+	LastError      string     `json:"last_error" gorm:"column:last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" gorm:"column:next_attempt_at"`
+	SentAt         *time.Time `json:"sent_at" gorm:"column:sent_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -3,6 +3,8 @@ package entity
 import (
 	"time"
 
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
 	"github.com/google/uuid"
 )
 
@@ -11,33 +13,73 @@ type SalesReport struct {
 	ReportDate              time.Time           `json:"report_date"`
 	PeriodStartDate         time.Time           `json:"period_start_date"`
 	PeriodEndDate           time.Time           `json:"period_end_date"`
-	TotalSales              float64             `json:"total_sales"`
+	GrossSales              money.Amount        `json:"gross_sales"`
+	TotalDiscount           money.Amount        `json:"total_discount"`
+	TotalSales              money.Amount        `json:"total_sales"`
 	TotalTransactions       int64               `json:"total_transactions"`
-	TotalTax                float64             `json:"total_tax"`
-	AverageTransactionValue float64             `json:"average_transaction_value"`
-	CashAmount              float64             `json:"cash_amount"`
-	MidtransAmount          float64             `json:"midtrans_amount"`
+	TotalTax                money.Amount        `json:"total_tax"`
+	AverageTransactionValue money.Amount        `json:"average_transaction_value"`
+	CashAmount              money.Amount        `json:"cash_amount"`
+	MidtransAmount          money.Amount        `json:"midtrans_amount"`
+	GiftCardSales           money.Amount        `json:"gift_card_sales"`
+	GiftCardRedeemed        money.Amount        `json:"gift_card_redeemed"`
+	GiftCardLiability       money.Amount        `json:"gift_card_liability"`
+	ServiceChargeAmount     money.Amount        `json:"service_charge_amount"`
+	TipAmount               money.Amount        `json:"tip_amount"`
 	TotalCustomers          int64               `json:"total_customers"`
 	PaymentMethodBreakdown  []PaymentMethodStat `json:"payment_method_breakdown"`
 	TopProducts             []TopProductStat    `json:"top_products"`
+	TipsByCashier           []CashierTipStat    `json:"tips_by_cashier"`
 	CreatedAt               time.Time           `json:"created_at"`
 }
 
 type PaymentMethodStat struct {
-	PaymentMethod string  `json:"payment_method"`
-	TotalAmount   float64 `json:"total_amount"`
-	Count         int64   `json:"count"`
-	Percentage    float64 `json:"percentage"`
+	PaymentMethod string       `json:"payment_method"`
+	TotalAmount   money.Amount `json:"total_amount"`
+	Count         int64        `json:"count"`
+	Percentage    float64      `json:"percentage"`
 }
 
 type TopProductStat struct {
-	ProductID    uuid.UUID `json:"product_id"`
-	ProductName  string    `json:"product_name"`
-	QuantitySold int       `json:"quantity_sold"`
-	TotalRevenue float64   `json:"total_revenue"`
+	ProductID    uuid.UUID    `json:"product_id"`
+	ProductName  string       `json:"product_name"`
+	QuantitySold int          `json:"quantity_sold"`
+	TotalRevenue money.Amount `json:"total_revenue"`
+}
+
+// CashierTipStat is what a cashier is owed in tips for the period.
+type CashierTipStat struct {
+	CashierID   uuid.UUID    `json:"cashier_id"`
+	CashierName string       `json:"cashier_name"`
+	TipAmount   money.Amount `json:"tip_amount"`
+	OrderCount  int64        `json:"order_count"`
 }
 
 type SalesReportRequest struct {
 	StartDate time.Time `json:"start_date"`
 	EndDate   time.Time `json:"end_date"`
 }
+
+// Sales report formats. JSON is the API response, CSV and XLSX are downloads
+// for spreadsheets.
+const (
+	ReportFormatJSON = "json"
+	ReportFormatCSV  = "csv"
+	ReportFormatXLSX = "xlsx"
+)
+
+// SalesOrderLine is one line of a paid order, as listed in the order line
+// export of a sales report.
+type SalesOrderLine struct {
+	OrderID        uuid.UUID
+	OrderedAt      time.Time
+	PaymentMethod  string
+	CashierName    string
+	ItemType       string
+	ItemName       string
+	Quantity       int
+	PricePerItem   money.Amount
+	DiscountAmount money.Amount
+	TaxAmount      money.Amount
+	TotalPrice     money.Amount
+}
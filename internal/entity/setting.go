@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+const (
+	SettingStoreName     = "store_name"
+	SettingStoreAddress  = "store_address"
+	SettingStorePhone    = "store_phone"
+	SettingReceiptPrefix = "receipt_prefix"
+	// SettingReceiptNumberFormat builds receipt numbers from {prefix}, {yyyy},
+	// {yy}, {mm}, {dd} and {seq:n}, the number of the day padded to n digits
+	SettingReceiptNumberFormat = "receipt_number_format"
+	// SettingTaxRate applies to products that have no tax class
+	SettingTaxRate = "tax_rate"
+	// SettingPricesIncludeTax marks product prices as already containing tax
+	SettingPricesIncludeTax = "prices_include_tax"
+	// SettingLoyaltySpendPerPoint is the rupiah a customer spends to earn one point
+	SettingLoyaltySpendPerPoint = "loyalty_spend_per_point"
+	// SettingLoyaltyPointValue is the rupiah one point is worth when redeemed
+	SettingLoyaltyPointValue = "loyalty_point_value"
+	// SettingMembershipWindowDays is the rolling window of spend that decides membership tiers
+	SettingMembershipWindowDays = "membership_window_days"
+	// SettingGiftCardValidityDays is how long a sold gift card can be used, 0 for no expiry
+	SettingGiftCardValidityDays = "gift_card_validity_days"
+	// SettingServiceChargeRate is added to orders on the product total after discounts, 0 for none
+	SettingServiceChargeRate = "service_charge_rate"
+	// SettingReceiptHeader and SettingReceiptFooter are text/template lines
+	// printed above and below the receipt body
+	SettingReceiptHeader = "receipt_header"
+	SettingReceiptFooter = "receipt_footer"
+	// SettingReceiptPaper is the paper size of PDF receipts, a4 or 80mm
+	SettingReceiptPaper = "receipt_paper"
+	// SettingReceiptVerifyURL is the public verify endpoint the QR code of a
+	// receipt points to. No QR code is printed until it is set.
+	SettingReceiptVerifyURL = "receipt_verify_url"
+	// SettingTimezone is the IANA time zone of the store. Business days of
+	// reports and receipt numbers start at midnight in this zone.
+	SettingTimezone = "timezone"
+)
+
+// DefaultStoreSettings are used when a key has not been stored yet.
+var DefaultStoreSettings = map[string]string{
+	SettingStoreName:            "Cuaniaga Store",
+	SettingStoreAddress:         "Jl. Raya No. 123",
+	SettingStorePhone:           "+62 812 3456 7890",
+	SettingTaxRate:              "0.10",
+	SettingReceiptPrefix:        "RCP",
+	SettingReceiptNumberFormat:  "{prefix}{yyyy}{mm}{dd}{seq:4}",
+	SettingPricesIncludeTax:     "false",
+	SettingLoyaltySpendPerPoint: "10000",
+	SettingLoyaltyPointValue:    "100",
+	SettingMembershipWindowDays: "365",
+	SettingGiftCardValidityDays: "365",
+	SettingServiceChargeRate:    "0",
+	SettingReceiptHeader:        "{{.StoreName}}\n{{.StoreAddress}}\n{{if .StorePhone}}Telp. {{.StorePhone}}{{end}}",
+	SettingReceiptFooter:        "Terima kasih atas kunjungan Anda",
+	SettingReceiptPaper:         ReceiptPaper80mm,
+	SettingReceiptVerifyURL:     "",
+	SettingTimezone:             "Asia/Jakarta",
+}
+
+type StoreSetting struct {
+	Key       string    `json:"key" gorm:"column:setting_key;primaryKey"`
+	Value     string    `json:"value" gorm:"column:setting_value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type StoreSettings struct {
+	StoreName            string       `json:"store_name"`
+	StoreAddress         string       `json:"store_address"`
+	StorePhone           string       `json:"store_phone"`
+	TaxRate              float64      `json:"tax_rate"`
+	PricesIncludeTax     bool         `json:"prices_include_tax"`
+	ReceiptPrefix        string       `json:"receipt_prefix"`
+	ReceiptNumberFormat  string       `json:"receipt_number_format"`
+	LoyaltySpendPerPoint money.Amount `json:"loyalty_spend_per_point"`
+	LoyaltyPointValue    money.Amount `json:"loyalty_point_value"`
+	MembershipWindowDays int          `json:"membership_window_days"`
+	GiftCardValidityDays int          `json:"gift_card_validity_days"`
+	ServiceChargeRate    float64      `json:"service_charge_rate"`
+	ReceiptHeader        string       `json:"receipt_header"`
+	ReceiptFooter        string       `json:"receipt_footer"`
+	ReceiptPaper         string       `json:"receipt_paper"`
+	ReceiptVerifyURL     string       `json:"receipt_verify_url"`
+	Timezone             string       `json:"timezone"`
+}
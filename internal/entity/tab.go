@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TabOpen      = "open"
+	TabClosed    = "closed"
+	TabMerged    = "merged"
+	TabCancelled = "cancelled"
+)
+
+const (
+	TicketHeld = "held"
+	TicketSent = "sent"
+	TicketDone = "done"
+)
+
+// DiningTable is a table on the floor plan.
+type DiningTable struct {
+	TableID   uuid.UUID `json:"table_id" gorm:"type:uuid;primaryKey"`
+	Name      string    `json:"name" gorm:"column:name"`
+	Area      string    `json:"area" gorm:"column:area"`
+	Seats     int       `json:"seats" gorm:"column:seats"`
+	IsActive  bool      `json:"is_active" gorm:"column:is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FloorPlanTable is a table with the tabs open on it. A table with an open
+// tab is occupied.
+type FloorPlanTable struct {
+	DiningTable
+	Status   string `json:"status"`
+	OpenTabs []Tab  `json:"open_tabs"`
+}
+
+// Tab is an open bill on a table. Items are added in rounds and the tab is
+// closed into one order when it is paid. Version goes up on every change to
+// the tab.
+type Tab struct {
+	TabID      uuid.UUID  `json:"tab_id" gorm:"type:uuid;primaryKey"`
+	TableID    uuid.UUID  `json:"table_id" gorm:"column:table_id"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"column:user_id"`
+	OpenedBy   *uuid.UUID `json:"opened_by" gorm:"column:opened_by"`
+	GuestCount int        `json:"guest_count" gorm:"column:guest_count"`
+	Status     string     `json:"status" gorm:"column:status"`
+	Rounds     int        `json:"rounds" gorm:"column:rounds"`
+	Version    int        `json:"version" gorm:"column:version"`
+	OrderID    *uuid.UUID `json:"order_id" gorm:"column:order_id"`
+	MergedInto *uuid.UUID `json:"merged_into" gorm:"column:merged_into"`
+	Note       string     `json:"note" gorm:"column:note"`
+	OpenedAt   time.Time  `json:"opened_at" gorm:"column:opened_at"`
+	ClosedAt   *time.Time `json:"closed_at" gorm:"column:closed_at"`
+	Items      []TabItem  `json:"items" gorm:"foreignKey:TabID"`
+}
+
+// TabItem is a product ordered on a tab. It keeps its round and kitchen
+// ticket when it is moved to another tab by a split or merge.
+type TabItem struct {
+	TabItemID uuid.UUID         `json:"tab_item_id" gorm:"type:uuid;primaryKey"`
+	TabID     uuid.UUID         `json:"tab_id" gorm:"column:tab_id"`
+	TicketID  uuid.UUID         `json:"ticket_id" gorm:"column:ticket_id"`
+	Round     int               `json:"round" gorm:"column:round"`
+	ProductID uuid.UUID         `json:"product_id" gorm:"column:product_id"`
+	Quantity  int               `json:"quantity" gorm:"column:quantity"`
+	Note      string            `json:"note" gorm:"column:note"`
+	CreatedAt time.Time         `json:"created_at"`
+	Modifiers []TabItemModifier `json:"modifiers" gorm:"foreignKey:TabItemID"`
+}
+
+// KitchenTicket is what the kitchen prepares for one round of a tab. A held
+// ticket waits until it is sent to the kitchen.
+type KitchenTicket struct {
+	TicketID  uuid.UUID  `json:"ticket_id" gorm:"type:uuid;primaryKey"`
+	TabID     uuid.UUID  `json:"tab_id" gorm:"column:tab_id"`
+	TableID   uuid.UUID  `json:"table_id" gorm:"column:table_id"`
+	Round     int        `json:"round" gorm:"column:round"`
+	Status    string     `json:"status" gorm:"column:status"`
+	Note      string     `json:"note" gorm:"column:note"`
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `json:"sent_at" gorm:"column:sent_at"`
+	DoneAt    *time.Time `json:"done_at" gorm:"column:done_at"`
+	Items     []TabItem  `json:"items" gorm:"foreignKey:TicketID"`
+}
+
+// TabItemSplit moves Quantity of a tab item to another tab.
+type TabItemSplit struct {
+	TabItemID uuid.UUID `json:"tab_item_id"`
+	Quantity  int       `json:"quantity"`
+}
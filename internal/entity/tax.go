@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaxClass struct {
+	TaxClassID  uuid.UUID `json:"tax_class_id" gorm:"type:uuid;primaryKey"`
+	Name        string    `json:"name" gorm:"column:name;unique"`
+	Description string    `json:"description" gorm:"column:description"`
+	Rates       []TaxRate `json:"rates,omitempty" gorm:"foreignKey:TaxClassID"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TaxRate struct {
+	TaxRateID     uuid.UUID  `json:"tax_rate_id" gorm:"type:uuid;primaryKey"`
+	TaxClassID    uuid.UUID  `json:"tax_class_id" gorm:"column:tax_class_id"`
+	Rate          float64    `json:"rate" gorm:"column:rate"`
+	EffectiveFrom time.Time  `json:"effective_from" gorm:"column:effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to" gorm:"column:effective_to"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
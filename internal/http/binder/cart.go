@@ -1,11 +1,17 @@
 package binder
 
-import "github.com/google/uuid"
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
 
 type CartAddItemRequest struct {
 	UserID    uuid.UUID `json:"user_id"`
 	ProductID uuid.UUID `json:"product_id"`
 	Quantity  int       `json:"quantity"`
+	// ModifierOptionIDs are the options chosen, such as size or sugar level
+	ModifierOptionIDs []uuid.UUID `json:"modifier_option_ids"`
 }
 
 type CartUpdateItemRequest struct {
@@ -13,7 +19,14 @@ type CartUpdateItemRequest struct {
 }
 
 type CartCheckoutRequest struct {
-	UserID        uuid.UUID `json:"user_id"`
-	PaymentMethod string    `json:"payment_method"`
-	PaidAmount    float64   `json:"paid_amount"`
+	UserID        uuid.UUID    `json:"user_id"`
+	PaymentMethod string       `json:"payment_method"`
+	PaidAmount    money.Amount `json:"paid_amount"`
+	CouponCode    string       `json:"coupon_code"`
+	RedeemPoints  int          `json:"redeem_points"`
+	// GiftCardAmount limits what is taken from the gift card, 0 takes what is needed
+	GiftCardCode   string           `json:"gift_card_code"`
+	GiftCardAmount money.Amount     `json:"gift_card_amount"`
+	Payments       []PaymentRequest `json:"payments"`
+	TipAmount      money.Amount     `json:"tip_amount"`
 }
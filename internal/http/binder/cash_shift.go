@@ -0,0 +1,21 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+type ShiftOpenRequest struct {
+	OpeningFloat money.Amount `json:"opening_float"`
+	Note         string       `json:"note"`
+}
+
+type CashMovementRequest struct {
+	Type   string       `json:"type" validate:"required"`
+	Amount money.Amount `json:"amount"`
+	Reason string       `json:"reason" validate:"required"`
+}
+
+type ShiftCloseRequest struct {
+	CountedCash money.Amount `json:"counted_cash"`
+	Note        string       `json:"note"`
+}
@@ -0,0 +1,11 @@
+package binder
+
+// CouponCodeCreateRequest creates either one code with the given value or,
+// when Count is set, that many random codes starting with Prefix.
+type CouponCodeCreateRequest struct {
+	Code           string `json:"code"`
+	Count          int    `json:"count"`
+	Prefix         string `json:"prefix"`
+	MaxRedemptions *int   `json:"max_redemptions"`
+	ExpiresAt      string `json:"expires_at"`
+}
@@ -0,0 +1,26 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type CreditAccountRequest struct {
+	CreditLimit      money.Amount `json:"credit_limit"`
+	PaymentTermsDays *int         `json:"payment_terms_days"`
+	Status           string       `json:"status"`
+}
+
+type CreditAllocationRequest struct {
+	InvoiceID uuid.UUID    `json:"invoice_id"`
+	Amount    money.Amount `json:"amount"`
+}
+
+type CreditPaymentRequest struct {
+	Amount      money.Amount              `json:"amount"`
+	Method      string                    `json:"method" validate:"required"`
+	Reference   string                    `json:"reference"`
+	Note        string                    `json:"note"`
+	Allocations []CreditAllocationRequest `json:"allocations"`
+}
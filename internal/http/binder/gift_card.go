@@ -0,0 +1,20 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+type GiftCardIssueRequest struct {
+	Value     money.Amount `json:"value"`
+	Code      string       `json:"code"`
+	ExpiresAt string       `json:"expires_at"`
+}
+
+type GiftCardTopUpRequest struct {
+	Amount money.Amount `json:"amount"`
+	Note   string       `json:"note"`
+}
+
+type GiftCardVoidRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
@@ -0,0 +1,11 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+type MembershipTierRequest struct {
+	Name         string       `json:"name" validate:"required"`
+	MinSpend     money.Amount `json:"min_spend"`
+	DiscountRate float64      `json:"discount_rate" validate:"min=0,max=1"`
+}
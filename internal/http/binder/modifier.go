@@ -0,0 +1,27 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type ModifierOptionRequest struct {
+	Name       string       `json:"name" validate:"required"`
+	PriceDelta money.Amount `json:"price_delta"`
+	IsActive   *bool        `json:"is_active"`
+	SortOrder  int          `json:"sort_order"`
+}
+
+type ModifierGroupRequest struct {
+	Name       string `json:"name" validate:"required"`
+	IsRequired bool   `json:"is_required"`
+	MinSelect  int    `json:"min_select"`
+	MaxSelect  int    `json:"max_select"`
+	// Options are only read when the group is created
+	Options []ModifierOptionRequest `json:"options"`
+}
+
+type ProductModifierGroupsRequest struct {
+	ModifierGroupIDs []uuid.UUID `json:"modifier_group_ids"`
+}
@@ -1,23 +1,51 @@
 package binder
 
-import "github.com/google/uuid"
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
 
 type OrderItemRequest struct {
 	ProductID uuid.UUID `json:"product_id" validate:"required"`
 	Quantity  int       `json:"quantity" validate:"required,min=1"`
 }
 
+// PaymentRequest is one tender of a split payment. Amount may be left empty
+// on one tender to pay the rest; PaidAmount is the cash handed over.
+type PaymentRequest struct {
+	Method       string       `json:"method"`
+	Amount       money.Amount `json:"amount"`
+	PaidAmount   money.Amount `json:"paid_amount"`
+	GiftCardCode string       `json:"gift_card_code"`
+}
+
 type OrderCreateRequest struct {
-	UserID        uuid.UUID `json:"user_id" validate:"required"` // <— wajib ada ini
-	PaymentMethod string    `json:"payment_method"`
-	PaidAmount    float64   `json:"paid_amount"`
-	Items         []struct {
-		ProductID uuid.UUID `json:"product_id"`
-		Quantity  int       `json:"quantity"`
+	UserID        uuid.UUID    `json:"user_id" validate:"required"` // <— wajib ada ini
+	PaymentMethod string       `json:"payment_method"`
+	PaidAmount    money.Amount `json:"paid_amount"`
+	CouponCode    string       `json:"coupon_code"`
+	RedeemPoints  int          `json:"redeem_points"`
+	// GiftCardAmount limits what is taken from the gift card, 0 takes what is needed
+	GiftCardCode   string           `json:"gift_card_code"`
+	GiftCardAmount money.Amount     `json:"gift_card_amount"`
+	Payments       []PaymentRequest `json:"payments"`
+	TipAmount      money.Amount     `json:"tip_amount"`
+	Items          []struct {
+		ProductID         uuid.UUID   `json:"product_id"`
+		Quantity          int         `json:"quantity"`
+		ModifierOptionIDs []uuid.UUID `json:"modifier_option_ids"`
 	} `json:"items"`
+	GiftCards []struct {
+		Value money.Amount `json:"value"`
+	} `json:"gift_cards"`
 }
 
 type OrderUpdateStatusRequest struct {
 	OrderID uuid.UUID `param:"order_id" json:"order_id" validate:"required"`
-	Status  string    `json:"status" validate:"required,oneof=pending paid shipped delivered cancelled"`
+	Status  string    `json:"status" validate:"required,oneof=pending paid shipped delivered cancelled refunded"`
+}
+
+type OrderBumpRequest struct {
+	FulfilmentStatus string `json:"fulfilment_status" validate:"required,oneof=preparing ready served"`
 }
@@ -2,6 +2,9 @@ package binder
 
 import (
 	"mime/multipart"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
 	"github.com/google/uuid"
 )
 
@@ -9,8 +12,10 @@ type ProductCreateRequest struct {
 	Name        string                `form:"name" json:"name" validate:"required"`
 	Description string                `form:"description" json:"description"`
 	Photo       *multipart.FileHeader `form:"photo" json:"-" validate:"required"`
-	Price       float64               `form:"price" json:"price" validate:"required,min=0"`
+	Price       money.Amount          `form:"price" json:"price" validate:"required,min=0"`
 	Stock       int                   `form:"stock" json:"stock" validate:"required,min=0"`
+	Category    string                `form:"category" json:"category"`
+	TaxClassID  string                `form:"tax_class_id" json:"tax_class_id"`
 }
 
 type ProductUpdateRequest struct {
@@ -18,8 +23,10 @@ type ProductUpdateRequest struct {
 	Name        string                `form:"name" json:"name" validate:"required"`
 	Description string                `form:"description" json:"description"`
 	Photo       *multipart.FileHeader `form:"photo" json:"-"`
-	Price       float64               `form:"price" json:"price" validate:"required,min=0"`
+	Price       money.Amount          `form:"price" json:"price" validate:"required,min=0"`
 	Stock       int                   `form:"stock" json:"stock" validate:"required,min=0"`
+	Category    string                `form:"category" json:"category"`
+	TaxClassID  string                `form:"tax_class_id" json:"tax_class_id"`
 }
 
 type ProductDeleteRequest struct {
@@ -30,4 +37,3 @@ type ProductUpdateStockRequest struct {
 	ProductID uuid.UUID `json:"product_id" validate:"required"`
 	Quantity  int       `json:"quantity" validate:"required"`
 }
-
@@ -0,0 +1,25 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+type PromotionRequest struct {
+	Name             string       `json:"name" validate:"required"`
+	Type             string       `json:"type" validate:"required"`
+	Percentage       float64      `json:"percentage" validate:"min=0,max=1"`
+	Amount           money.Amount `json:"amount"`
+	ProductID        string       `json:"product_id"`
+	Category         string       `json:"category"`
+	BuyQuantity      int          `json:"buy_quantity"`
+	GetQuantity      int          `json:"get_quantity"`
+	MinSpend         money.Amount `json:"min_spend"`
+	StartsAt         string       `json:"starts_at"`
+	EndsAt           string       `json:"ends_at"`
+	UsageLimit       *int         `json:"usage_limit"`
+	Stackable        bool         `json:"stackable"`
+	RequiresCode     bool         `json:"requires_code"`
+	PerCustomerLimit *int         `json:"per_customer_limit"`
+	Priority         int          `json:"priority"`
+	Active           *bool        `json:"active"`
+}
@@ -7,3 +7,7 @@ type ReceiptGenerateRequest struct {
 	UserID      uuid.UUID `json:"user_id"`
 	CashierName string    `json:"cashier_name"`
 }
+
+type ReceiptVoidRequest struct {
+	Reason string `json:"reason"`
+}
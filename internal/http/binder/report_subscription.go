@@ -0,0 +1,9 @@
+package binder
+
+type ReportSubscriptionRequest struct {
+	ReportType string   `json:"report_type" validate:"required"`
+	SendHour   *int     `json:"send_hour"`
+	Recipients []string `json:"recipients" validate:"required"`
+	Format     string   `json:"format"`
+	Active     *bool    `json:"active"`
+}
@@ -0,0 +1,25 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+type SettingUpdateRequest struct {
+	StoreName            *string       `json:"store_name"`
+	StoreAddress         *string       `json:"store_address"`
+	StorePhone           *string       `json:"store_phone"`
+	TaxRate              *float64      `json:"tax_rate"`
+	PricesIncludeTax     *bool         `json:"prices_include_tax"`
+	ReceiptPrefix        *string       `json:"receipt_prefix"`
+	ReceiptNumberFormat  *string       `json:"receipt_number_format"`
+	LoyaltySpendPerPoint *money.Amount `json:"loyalty_spend_per_point"`
+	LoyaltyPointValue    *money.Amount `json:"loyalty_point_value"`
+	MembershipWindowDays *int          `json:"membership_window_days"`
+	GiftCardValidityDays *int          `json:"gift_card_validity_days"`
+	ServiceChargeRate    *float64      `json:"service_charge_rate"`
+	ReceiptHeader        *string       `json:"receipt_header"`
+	ReceiptFooter        *string       `json:"receipt_footer"`
+	ReceiptPaper         *string       `json:"receipt_paper"`
+	ReceiptVerifyURL     *string       `json:"receipt_verify_url"`
+	Timezone             *string       `json:"timezone"`
+}
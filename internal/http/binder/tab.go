@@ -0,0 +1,61 @@
+package binder
+
+import (
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+type TableRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Area     string `json:"area"`
+	Seats    int    `json:"seats"`
+	IsActive *bool  `json:"is_active"`
+}
+
+type TabOpenRequest struct {
+	// UserID is the customer of the tab, the staff member opening it when empty
+	UserID     uuid.UUID `json:"user_id"`
+	GuestCount int       `json:"guest_count"`
+	Note       string    `json:"note"`
+}
+
+type TabRoundItemRequest struct {
+	ProductID         uuid.UUID   `json:"product_id"`
+	Quantity          int         `json:"quantity"`
+	Note              string      `json:"note"`
+	ModifierOptionIDs []uuid.UUID `json:"modifier_option_ids"`
+}
+
+type TabRoundRequest struct {
+	Items []TabRoundItemRequest `json:"items"`
+	// Hold keeps the kitchen ticket until it is sent
+	Hold bool   `json:"hold"`
+	Note string `json:"note"`
+}
+
+type TabSplitItemRequest struct {
+	TabItemID uuid.UUID `json:"tab_item_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+type TabSplitRequest struct {
+	Items []TabSplitItemRequest `json:"items"`
+}
+
+type TabMergeRequest struct {
+	SourceTabID uuid.UUID `json:"source_tab_id"`
+}
+
+// TabPayRequest carries the payment of a tab. The customer and the items are
+// taken from the tab.
+type TabPayRequest struct {
+	PaymentMethod  string           `json:"payment_method"`
+	PaidAmount     money.Amount     `json:"paid_amount"`
+	CouponCode     string           `json:"coupon_code"`
+	RedeemPoints   int              `json:"redeem_points"`
+	GiftCardCode   string           `json:"gift_card_code"`
+	GiftCardAmount money.Amount     `json:"gift_card_amount"`
+	Payments       []PaymentRequest `json:"payments"`
+	TipAmount      money.Amount     `json:"tip_amount"`
+}
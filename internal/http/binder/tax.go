@@ -0,0 +1,11 @@
+package binder
+
+type TaxClassCreateRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+type TaxRateCreateRequest struct {
+	Rate          float64 `json:"rate" validate:"min=0,max=1"`
+	EffectiveFrom string  `json:"effective_from"`
+}
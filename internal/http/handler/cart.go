@@ -2,9 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
 	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
 	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
 	"Kevinmajesta/OrderManagementAPI/pkg/response"
 
 	"github.com/google/uuid"
@@ -25,7 +28,7 @@ func (h *CartHandler) AddItem(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
 	}
 
-	cart, err := h.cartService.AddItem(req.UserID, req.ProductID, req.Quantity)
+	cart, err := h.cartService.AddItem(req.UserID, req.ProductID, req.Quantity, req.ModifierOptionIDs)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
 	}
@@ -86,13 +89,81 @@ func (h *CartHandler) GetCart(c echo.Context) error {
 	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "cart fetched", cart))
 }
 
+func (h *CartHandler) Quote(c echo.Context) error {
+	userIDParam := c.QueryParam("user_id")
+	if userIDParam == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "user_id is required"))
+	}
+
+	userID, err := uuid.Parse(userIDParam)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid user_id"))
+	}
+
+	redeemPoints := 0
+	if points := c.QueryParam("redeem_points"); points != "" {
+		redeemPoints, err = strconv.Atoi(points)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid redeem_points"))
+		}
+	}
+
+	var giftCardLimit money.Amount
+	if amount := c.QueryParam("gift_card_amount"); amount != "" {
+		giftCardLimit, err = money.Parse(amount)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid gift_card_amount"))
+		}
+	}
+
+	var tipAmount money.Amount
+	if amount := c.QueryParam("tip_amount"); amount != "" {
+		tipAmount, err = money.Parse(amount)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tip_amount"))
+		}
+	}
+
+	quote, err := h.cartService.Quote(&entity.Order{
+		UserID:         userID,
+		CouponCode:     c.QueryParam("coupon_code"),
+		PointsRedeemed: redeemPoints,
+		GiftCardCode:   c.QueryParam("gift_card_code"),
+		GiftCardLimit:  giftCardLimit,
+		TipAmount:      tipAmount,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "cart quote fetched", quote))
+}
+
 func (h *CartHandler) Checkout(c echo.Context) error {
 	var req binder.CartCheckoutRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
 	}
+	// Points, gift cards and credit are taken from the account of user_id
+	userID, err := customerID(c, req.UserID)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, response.ErrorResponse(http.StatusForbidden, err.Error()))
+	}
+
+	checkout := &entity.Order{
+		UserID:         userID,
+		PaymentMethod:  req.PaymentMethod,
+		PaidAmount:     req.PaidAmount,
+		CouponCode:     req.CouponCode,
+		PointsRedeemed: req.RedeemPoints,
+		GiftCardCode:   req.GiftCardCode,
+		GiftCardLimit:  req.GiftCardAmount,
+		Payments:       orderPayments(req.Payments),
+		TipAmount:      req.TipAmount,
+		CashierID:      jwtCashierID(c),
+	}
 
-	order, err := h.cartService.Checkout(req.UserID, req.PaymentMethod, req.PaidAmount)
+	order, err := h.cartService.Checkout(checkout)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
 	}
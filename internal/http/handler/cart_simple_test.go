@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// checkedOutOrders keeps the orders passed to Checkout
+type checkedOutOrders struct {
+	service.CartService
+	orders []*entity.Order
+}
+
+func (s *checkedOutOrders) Checkout(order *entity.Order) (*entity.Order, error) {
+	s.orders = append(s.orders, order)
+	return order, nil
+}
+
+// TestCheckoutTipWithoutCashier tests that the tip of a customer checkout is
+// not credited to the customer. Orders without a cashier are the uuid.Nil
+// group of the tips by cashier report.
+func TestCheckoutTipWithoutCashier(t *testing.T) {
+	customerID := uuid.New()
+	body := `{"user_id": "` + customerID.String() + `", "payment_method": "cash", "paid_amount": "60000", "tip_amount": "5000"}`
+
+	orders := &checkedOutOrders{}
+	c, rec := loggedInRequest(body, customerID, "user")
+	if err := NewCartHandler(orders).Checkout(c); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("Expected checkout to succeed, got %d %v", rec.Code, err)
+	}
+
+	order := orders.orders[0]
+	if order.TipAmount != money.New(5000) {
+		t.Errorf("Expected tip 5000.00, got %s", order.TipAmount)
+	}
+	if order.CashierID != nil {
+		t.Errorf("Expected the tip to have no cashier, got %s", order.CashierID)
+	}
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+	"Kevinmajesta/OrderManagementAPI/pkg/token"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ShiftHandler struct {
+	shiftService service.ShiftService
+}
+
+func NewShiftHandler(shiftService service.ShiftService) *ShiftHandler {
+	return &ShiftHandler{shiftService: shiftService}
+}
+
+func (h *ShiftHandler) OpenShift(c echo.Context) error {
+	cashierID, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	var req binder.ShiftOpenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	shift, err := h.shiftService.OpenShift(cashierID, req.OpeningFloat, req.Note)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "shift opened", shift))
+}
+
+func (h *ShiftHandler) GetCurrentShift(c echo.Context) error {
+	cashierID, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	report, err := h.shiftService.GetCurrentShift(cashierID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "shift fetched", report))
+}
+
+func (h *ShiftHandler) AddCashMovement(c echo.Context) error {
+	cashierID, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	var req binder.CashMovementRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	movement, err := h.shiftService.AddCashMovement(cashierID, req.Type, req.Amount, req.Reason)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "cash movement recorded", movement))
+}
+
+func (h *ShiftHandler) CloseShift(c echo.Context) error {
+	cashierID, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	var req binder.ShiftCloseRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	report, err := h.shiftService.CloseShift(cashierID, req.CountedCash, req.Note)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "shift closed", report))
+}
+
+func (h *ShiftHandler) GetShiftReport(c echo.Context) error {
+	shiftID, err := uuid.Parse(c.Param("shift_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid shift_id"))
+	}
+
+	report, err := h.shiftService.GetShiftReport(shiftID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "shift report fetched", report))
+}
+
+// GetZReport returns the day's Z-report, as plain text for printing when
+// format=text is given.
+func (h *ShiftHandler) GetZReport(c echo.Context) error {
+	// Without a date the service reports today in the store's time zone
+	var date time.Time
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid date format, use YYYY-MM-DD"))
+		}
+	}
+
+	report, err := h.shiftService.GetZReport(date)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	if c.QueryParam("format") == "text" {
+		return c.String(http.StatusOK, service.FormatZReport(report))
+	}
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "z-report generated", report))
+}
+
+// jwtUserID is the user behind the JWT of the request.
+func jwtUserID(c echo.Context) (uuid.UUID, error) {
+	claims, err := jwtClaims(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid token claims")
+	}
+	return id, nil
+}
+
+// jwtCashierID is the staff member taking an order. Customers checking out
+// themselves have no cashier, so it is nil for them.
+func jwtCashierID(c echo.Context) *uuid.UUID {
+	claims, err := jwtClaims(c)
+	if err != nil || claims.Role != "admin" {
+		return nil
+	}
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// customerID is the customer a request acts for. Admins act for the customer
+// they name, everyone else only for themselves: an empty requested is the
+// caller and any other user is refused.
+func customerID(c echo.Context, requested uuid.UUID) (uuid.UUID, error) {
+	claims, err := jwtClaims(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if claims.Role == "admin" {
+		return requested, nil
+	}
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid token claims")
+	}
+	if requested != uuid.Nil && requested != id {
+		return uuid.Nil, errors.New("you can only act for your own account")
+	}
+	return id, nil
+}
+
+// queryCustomerID is the user_id query parameter for admins. Everyone else
+// always gets their own user and the parameter is ignored.
+func queryCustomerID(c echo.Context) (uuid.UUID, error) {
+	claims, err := jwtClaims(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if claims.Role != "admin" {
+		return jwtUserID(c)
+	}
+	userIDParam := c.QueryParam("user_id")
+	if userIDParam == "" {
+		return uuid.Nil, errors.New("user_id is required")
+	}
+	userID, err := uuid.Parse(userIDParam)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user_id")
+	}
+	return userID, nil
+}
+
+func jwtClaims(c echo.Context) (*token.JwtCustomClaims, error) {
+	user, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return nil, errors.New("you must login first")
+	}
+	claims, ok := user.Claims.(*token.JwtCustomClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+	return claims, nil
+}
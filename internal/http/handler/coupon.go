@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type CouponHandler struct {
+	couponService service.CouponService
+}
+
+func NewCouponHandler(couponService service.CouponService) *CouponHandler {
+	return &CouponHandler{couponService: couponService}
+}
+
+func (h *CouponHandler) CreateCodes(c echo.Context) error {
+	promotionID, err := uuid.Parse(c.Param("promotion_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid promotion_id"))
+	}
+
+	var req binder.CouponCodeCreateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid expires_at format, use RFC3339"))
+		}
+		expiresAt = &parsed
+	}
+
+	if req.Count > 0 {
+		codes, err := h.couponService.GenerateCodes(promotionID, req.Count, req.Prefix, req.MaxRedemptions, expiresAt)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+		}
+		return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "coupon codes generated", codes))
+	}
+
+	code, err := h.couponService.CreateCode(promotionID, req.Code, req.MaxRedemptions, expiresAt)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "coupon code created", code))
+}
+
+func (h *CouponHandler) FindCodes(c echo.Context) error {
+	promotionID, err := uuid.Parse(c.Param("promotion_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid promotion_id"))
+	}
+
+	codes, err := h.couponService.FindCodesByPromotionID(promotionID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "coupon codes fetched", codes))
+}
+
+func (h *CouponHandler) GetRedemptionReport(c echo.Context) error {
+	promotionID, err := uuid.Parse(c.Param("promotion_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid promotion_id"))
+	}
+
+	report, err := h.couponService.GetRedemptionReport(promotionID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "coupon redemption report fetched", report))
+}
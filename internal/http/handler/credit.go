@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultPaymentTermsDays applies when an account is approved without terms.
+const defaultPaymentTermsDays = 30
+
+type CreditHandler struct {
+	creditService service.CreditService
+}
+
+func NewCreditHandler(creditService service.CreditService) *CreditHandler {
+	return &CreditHandler{creditService: creditService}
+}
+
+func (h *CreditHandler) FindAllAccounts(c echo.Context) error {
+	accounts, err := h.creditService.FindAllAccounts()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "credit accounts fetched", accounts))
+}
+
+func (h *CreditHandler) ApproveAccount(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid user_id"))
+	}
+
+	var req binder.CreditAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	termsDays := defaultPaymentTermsDays
+	if req.PaymentTermsDays != nil {
+		termsDays = *req.PaymentTermsDays
+	}
+
+	account, err := h.creditService.ApproveAccount(userID, req.CreditLimit, termsDays, req.Status)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "credit account saved", account))
+}
+
+func (h *CreditHandler) RecordPayment(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid user_id"))
+	}
+
+	var req binder.CreditPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	payment := &entity.CreditPayment{
+		UserID:    userID,
+		Amount:    req.Amount,
+		Method:    req.Method,
+		Reference: req.Reference,
+		Note:      req.Note,
+	}
+	for _, allocation := range req.Allocations {
+		payment.Allocations = append(payment.Allocations, entity.CreditAllocation{
+			InvoiceID: allocation.InvoiceID,
+			Amount:    allocation.Amount,
+		})
+	}
+	if receivedBy, err := jwtUserID(c); err == nil {
+		payment.ReceivedBy = &receivedBy
+	}
+
+	payment, err = h.creditService.RecordPayment(payment)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "credit payment recorded", payment))
+}
+
+// GetStatement serves both the admin path and GET /credit/statement, which
+// is the caller's own statement. Only admins can ask for another customer's
+// with ?user_id= there.
+func (h *CreditHandler) GetStatement(c echo.Context) error {
+	var userID uuid.UUID
+	var err error
+	if userIDParam := c.Param("user_id"); userIDParam != "" {
+		if userID, err = uuid.Parse(userIDParam); err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid user_id"))
+		}
+	} else if userID, err = queryCustomerID(c); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	statement, err := h.creditService.GetStatement(userID, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "credit statement fetched", statement))
+}
+
+func (h *CreditHandler) EmailStatement(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid user_id"))
+	}
+
+	if err := h.creditService.EmailStatement(userID); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "credit statement sent", nil))
+}
+
+func (h *CreditHandler) EmailAllStatements(c echo.Context) error {
+	sent, err := h.creditService.EmailAllStatements()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "credit statements sent", map[string]int{"sent": sent}))
+}
+
+func (h *CreditHandler) GetAgingReport(c echo.Context) error {
+	report, err := h.creditService.GetAgingReport(time.Now())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "credit aging report generated", report))
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// requestedStatements keeps the users whose statement was asked for
+type requestedStatements struct {
+	service.CreditService
+	users []uuid.UUID
+}
+
+func (s *requestedStatements) GetStatement(userID uuid.UUID, at time.Time) (*entity.CreditStatement, error) {
+	s.users = append(s.users, userID)
+	return &entity.CreditStatement{}, nil
+}
+
+// TestGetStatementOwner tests that a customer only gets their own statement
+func TestGetStatementOwner(t *testing.T) {
+	customerID := uuid.New()
+	statements := &requestedStatements{}
+
+	c, rec := loggedInRequest("", customerID, "user")
+	c.QueryParams().Set("user_id", uuid.New().String())
+	if err := NewCreditHandler(statements).GetStatement(c); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected the statement to be fetched, got %d %v", rec.Code, err)
+	}
+	if statements.users[0] != customerID {
+		t.Errorf("Expected the customer's own statement, got %s", statements.users[0])
+	}
+}
+
+// TestCreditTenderForOtherUser tests that a customer cannot charge the credit account of another user
+func TestCreditTenderForOtherUser(t *testing.T) {
+	body := `{"user_id": "` + uuid.New().String() + `", "payments": [{"method": "credit"}]}`
+
+	orders := &createdOrders{}
+	c, rec := loggedInRequest(body, uuid.New(), "user")
+	if err := NewOrderHandler(orders, nil).CreateOrder(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusForbidden || len(orders.orders) != 0 {
+		t.Errorf("Expected the order to be refused, got %d with %d orders", rec.Code, len(orders.orders))
+	}
+
+	checkouts := &checkedOutOrders{}
+	c, rec = loggedInRequest(body, uuid.New(), "user")
+	if err := NewCartHandler(checkouts).Checkout(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusForbidden || len(checkouts.orders) != 0 {
+		t.Errorf("Expected the checkout to be refused, got %d with %d orders", rec.Code, len(checkouts.orders))
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type GiftCardHandler struct {
+	giftCardService service.GiftCardService
+}
+
+func NewGiftCardHandler(giftCardService service.GiftCardService) *GiftCardHandler {
+	return &GiftCardHandler{giftCardService: giftCardService}
+}
+
+func (h *GiftCardHandler) IssueGiftCard(c echo.Context) error {
+	var req binder.GiftCardIssueRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid expires_at format, use RFC3339"))
+		}
+		expiresAt = &parsed
+	}
+
+	giftCard, err := h.giftCardService.IssueGiftCard(req.Value, req.Code, expiresAt)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "gift card issued", giftCard))
+}
+
+func (h *GiftCardHandler) FindAllGiftCards(c echo.Context) error {
+	giftCards, err := h.giftCardService.FindAllGiftCards()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "gift cards fetched", giftCards))
+}
+
+func (h *GiftCardHandler) FindGiftCardByID(c echo.Context) error {
+	giftCardID, err := uuid.Parse(c.Param("gift_card_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid gift_card_id"))
+	}
+
+	giftCard, err := h.giftCardService.FindGiftCardByID(giftCardID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "gift card fetched", giftCard))
+}
+
+func (h *GiftCardHandler) CheckBalance(c echo.Context) error {
+	code := c.QueryParam("code")
+	if code == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "code is required"))
+	}
+
+	balance, err := h.giftCardService.CheckBalance(code)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "gift card balance fetched", balance))
+}
+
+func (h *GiftCardHandler) TopUpGiftCard(c echo.Context) error {
+	giftCardID, err := uuid.Parse(c.Param("gift_card_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid gift_card_id"))
+	}
+
+	var req binder.GiftCardTopUpRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	giftCard, err := h.giftCardService.TopUpGiftCard(giftCardID, req.Amount, req.Note)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "gift card topped up", giftCard))
+}
+
+func (h *GiftCardHandler) VoidGiftCard(c echo.Context) error {
+	giftCardID, err := uuid.Parse(c.Param("gift_card_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid gift_card_id"))
+	}
+
+	var req binder.GiftCardVoidRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	giftCard, err := h.giftCardService.VoidGiftCard(giftCardID, req.Reason)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "gift card voided", giftCard))
+}
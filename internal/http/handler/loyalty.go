@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LoyaltyHandler struct {
+	loyaltyService service.LoyaltyService
+}
+
+func NewLoyaltyHandler(loyaltyService service.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{loyaltyService: loyaltyService}
+}
+
+// GetBalance is the balance of the caller, or of the user_id asked for by an
+// admin. GetHistory works the same.
+func (h *LoyaltyHandler) GetBalance(c echo.Context) error {
+	userID, err := queryCustomerID(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	balance, err := h.loyaltyService.GetBalance(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "loyalty balance fetched", balance))
+}
+
+func (h *LoyaltyHandler) GetHistory(c echo.Context) error {
+	userID, err := queryCustomerID(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	history, err := h.loyaltyService.GetHistory(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "loyalty history fetched", history))
+}
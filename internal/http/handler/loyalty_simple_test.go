@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// requestedBalances keeps the users whose balance was asked for
+type requestedBalances struct {
+	service.LoyaltyService
+	users []uuid.UUID
+}
+
+func (s *requestedBalances) GetBalance(userID uuid.UUID) (*entity.LoyaltyBalance, error) {
+	s.users = append(s.users, userID)
+	return &entity.LoyaltyBalance{}, nil
+}
+
+// TestLoyaltyBalanceOwner tests that customers only see their own points
+func TestLoyaltyBalanceOwner(t *testing.T) {
+	customerID := uuid.New()
+	otherID := uuid.New()
+	balances := &requestedBalances{}
+	h := NewLoyaltyHandler(balances)
+
+	c, rec := loggedInRequest("", customerID, "user")
+	c.QueryParams().Set("user_id", otherID.String())
+	if err := h.GetBalance(c); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected the balance to be fetched, got %d %v", rec.Code, err)
+	}
+	if balances.users[0] != customerID {
+		t.Errorf("Expected a customer to get their own balance, got %s", balances.users[0])
+	}
+
+	c, rec = loggedInRequest("", uuid.New(), "admin")
+	c.QueryParams().Set("user_id", otherID.String())
+	if err := h.GetBalance(c); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected the balance to be fetched, got %d %v", rec.Code, err)
+	}
+	if balances.users[1] != otherID {
+		t.Errorf("Expected an admin to get the balance asked for, got %s", balances.users[1])
+	}
+}
+
+// TestCreateOrderRedeemForOtherUser tests that a customer cannot spend the points of another user
+func TestCreateOrderRedeemForOtherUser(t *testing.T) {
+	body := `{"user_id": "` + uuid.New().String() + `", "payment_method": "cash", "paid_amount": "50000", "redeem_points": 100}`
+
+	orders := &createdOrders{}
+	c, rec := loggedInRequest(body, uuid.New(), "user")
+	if err := NewOrderHandler(orders, nil).CreateOrder(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusForbidden || len(orders.orders) != 0 {
+		t.Errorf("Expected the order to be refused, got %d with %d orders", rec.Code, len(orders.orders))
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type MembershipHandler struct {
+	membershipService service.MembershipService
+}
+
+func NewMembershipHandler(membershipService service.MembershipService) *MembershipHandler {
+	return &MembershipHandler{membershipService: membershipService}
+}
+
+func (h *MembershipHandler) CreateTier(c echo.Context) error {
+	var req binder.MembershipTierRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	tier := &entity.MembershipTier{
+		Name:         req.Name,
+		MinSpend:     req.MinSpend,
+		DiscountRate: req.DiscountRate,
+	}
+
+	created, err := h.membershipService.CreateTier(tier)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "membership tier created", created))
+}
+
+func (h *MembershipHandler) FindAllTiers(c echo.Context) error {
+	tiers, err := h.membershipService.FindAllTiers()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "membership tiers fetched", tiers))
+}
+
+func (h *MembershipHandler) UpdateTier(c echo.Context) error {
+	tierID, err := uuid.Parse(c.Param("tier_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tier_id"))
+	}
+
+	var req binder.MembershipTierRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	tier := &entity.MembershipTier{
+		TierID:       tierID,
+		Name:         req.Name,
+		MinSpend:     req.MinSpend,
+		DiscountRate: req.DiscountRate,
+	}
+
+	updated, err := h.membershipService.UpdateTier(tier)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "membership tier updated", updated))
+}
+
+func (h *MembershipHandler) DeleteTier(c echo.Context) error {
+	tierID, err := uuid.Parse(c.Param("tier_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tier_id"))
+	}
+
+	if err := h.membershipService.DeleteTier(tierID); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "membership tier deleted", nil))
+}
+
+// RecalculateTiers runs the periodic recalculation right away, for example
+// after the tiers were changed.
+func (h *MembershipHandler) RecalculateTiers(c echo.Context) error {
+	if err := h.membershipService.RecalculateTiers(time.Now()); err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "membership tiers recalculated", nil))
+}
+
+func (h *MembershipHandler) GetMembership(c echo.Context) error {
+	userIDParam := c.QueryParam("user_id")
+	if userIDParam == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "user_id is required"))
+	}
+
+	userID, err := uuid.Parse(userIDParam)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid user_id"))
+	}
+
+	membership, err := h.membershipService.GetMembership(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "membership fetched", membership))
+}
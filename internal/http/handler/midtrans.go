@@ -2,6 +2,7 @@ package handler
 
 import (
 	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/midtrans"
 	"Kevinmajesta/OrderManagementAPI/pkg/response"
 	"net/http"
 
@@ -10,10 +11,12 @@ import (
 
 type MidtransHandler struct {
 	orderService service.OrderService
+	serverKey    string
 }
 
-func NewMidtransHandler(orderService service.OrderService) *MidtransHandler {
-	return &MidtransHandler{orderService: orderService}
+// NewMidtransHandler checks notifications against the Midtrans serverKey.
+func NewMidtransHandler(orderService service.OrderService, serverKey string) *MidtransHandler {
+	return &MidtransHandler{orderService: orderService, serverKey: serverKey}
 }
 
 func (h *MidtransHandler) HandleNotification(c echo.Context) error {
@@ -28,6 +31,15 @@ func (h *MidtransHandler) HandleNotification(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "order_id not found"))
 	}
 
+	// A paid order activates gift cards, earns points and issues a receipt, so
+	// only notifications signed with the server key are applied
+	statusCode, _ := notification["status_code"].(string)
+	grossAmount, _ := notification["gross_amount"].(string)
+	signature, _ := notification["signature_key"].(string)
+	if !midtrans.VerifySignature(h.serverKey, orderID, statusCode, grossAmount, signature) {
+		return c.JSON(http.StatusForbidden, response.ErrorResponse(http.StatusForbidden, "invalid signature"))
+	}
+
 	transactionStatus, ok := notification["transaction_status"].(string)
 	if !ok {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "transaction_status not found"))
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// notifiedOrders keeps the status updates made from notifications
+type notifiedOrders struct {
+	service.OrderService
+	statuses []string
+}
+
+func (s *notifiedOrders) UpdateOrderStatusByOrderID(orderID string, status string) error {
+	s.statuses = append(s.statuses, status)
+	return nil
+}
+
+// TestHandleNotificationForged tests that an unsigned settlement does not mark the order paid
+func TestHandleNotificationForged(t *testing.T) {
+	body := `{"order_id": "` + uuid.New().String() + `", "status_code": "200", "gross_amount": "55000.00", ` +
+		`"transaction_status": "settlement", "signature_key": "forged"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	orders := &notifiedOrders{}
+	if err := NewMidtransHandler(orders, "server-key").HandleNotification(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusForbidden || len(orders.statuses) != 0 {
+		t.Errorf("Expected the notification to be refused, got %d with updates %v", rec.Code, orders.statuses)
+	}
+}
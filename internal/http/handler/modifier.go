@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ModifierHandler struct {
+	modifierService service.ModifierService
+}
+
+func NewModifierHandler(modifierService service.ModifierService) *ModifierHandler {
+	return &ModifierHandler{modifierService: modifierService}
+}
+
+func (h *ModifierHandler) FindAllGroups(c echo.Context) error {
+	groups, err := h.modifierService.FindAllGroups()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "modifier groups fetched", groups))
+}
+
+func (h *ModifierHandler) CreateGroup(c echo.Context) error {
+	var req binder.ModifierGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	group := &entity.ModifierGroup{
+		Name:       req.Name,
+		IsRequired: req.IsRequired,
+		MinSelect:  req.MinSelect,
+		MaxSelect:  req.MaxSelect,
+	}
+	for _, option := range req.Options {
+		group.Options = append(group.Options, entity.ModifierOption{
+			Name:       option.Name,
+			PriceDelta: option.PriceDelta,
+			SortOrder:  option.SortOrder,
+		})
+	}
+
+	if err := h.modifierService.CreateGroup(group); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "modifier group created", group))
+}
+
+func (h *ModifierHandler) UpdateGroup(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("modifier_group_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid modifier_group_id"))
+	}
+
+	var req binder.ModifierGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	group, err := h.modifierService.UpdateGroup(&entity.ModifierGroup{
+		ModifierGroupID: groupID,
+		Name:            req.Name,
+		IsRequired:      req.IsRequired,
+		MinSelect:       req.MinSelect,
+		MaxSelect:       req.MaxSelect,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "modifier group updated", group))
+}
+
+func (h *ModifierHandler) AddOption(c echo.Context) error {
+	groupID, err := uuid.Parse(c.Param("modifier_group_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid modifier_group_id"))
+	}
+
+	var req binder.ModifierOptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	option := &entity.ModifierOption{
+		ModifierGroupID: groupID,
+		Name:            req.Name,
+		PriceDelta:      req.PriceDelta,
+		SortOrder:       req.SortOrder,
+	}
+	if err := h.modifierService.AddOption(option); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "modifier option created", option))
+}
+
+func (h *ModifierHandler) UpdateOption(c echo.Context) error {
+	optionID, err := uuid.Parse(c.Param("modifier_option_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid modifier_option_id"))
+	}
+
+	var req binder.ModifierOptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	option, err := h.modifierService.UpdateOption(&entity.ModifierOption{
+		ModifierOptionID: optionID,
+		Name:             req.Name,
+		PriceDelta:       req.PriceDelta,
+		IsActive:         req.IsActive == nil || *req.IsActive,
+		SortOrder:        req.SortOrder,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "modifier option updated", option))
+}
+
+func (h *ModifierHandler) GetProductModifiers(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid product_id"))
+	}
+
+	groups, err := h.modifierService.GetProductModifiers(productID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "product modifiers fetched", groups))
+}
+
+func (h *ModifierHandler) SetProductGroups(c echo.Context) error {
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid product_id"))
+	}
+
+	var req binder.ProductModifierGroupsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	groups, err := h.modifierService.SetProductGroups(productID, req.ModifierGroupIDs)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "product modifier groups updated", groups))
+}
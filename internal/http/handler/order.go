@@ -5,18 +5,25 @@ import (
 	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
 	"Kevinmajesta/OrderManagementAPI/internal/service"
 	"Kevinmajesta/OrderManagementAPI/pkg/response"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
+// orderFeedHeartbeat keeps idle feed connections open through proxies
+const orderFeedHeartbeat = 15 * time.Second
+
 type OrderHandler struct {
-	orderService service.OrderService
+	orderService      service.OrderService
+	orderEventService service.OrderEventService
 }
 
-func NewOrderHandler(orderService service.OrderService) *OrderHandler {
-	return &OrderHandler{orderService: orderService}
+func NewOrderHandler(orderService service.OrderService, orderEventService service.OrderEventService) *OrderHandler {
+	return &OrderHandler{orderService: orderService, orderEventService: orderEventService}
 }
 
 func (h *OrderHandler) CreateOrder(c echo.Context) error {
@@ -25,20 +32,39 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
 	}
+	// Points, gift cards and credit are taken from the account of user_id
+	userID, err := customerID(c, req.UserID)
+	if err != nil {
+		return c.JSON(http.StatusForbidden, response.ErrorResponse(http.StatusForbidden, err.Error()))
+	}
 
 	var orderItems []entity.OrderItem
 	for _, item := range req.Items {
-		orderItems = append(orderItems, entity.OrderItem{
-			ProductID: item.ProductID,
+		orderItem := entity.OrderItem{
+			ProductID: &item.ProductID,
 			Quantity:  item.Quantity,
-		})
+		}
+		for _, optionID := range item.ModifierOptionIDs {
+			orderItem.Modifiers = append(orderItem.Modifiers, entity.OrderItemModifier{ModifierOptionID: &optionID})
+		}
+		orderItems = append(orderItems, orderItem)
 	}
 
 	order := &entity.Order{
-		UserID:        req.UserID,
-		PaymentMethod: req.PaymentMethod,
-		PaidAmount:    req.PaidAmount,
-		OrderItems:    orderItems,
+		UserID:         userID,
+		PaymentMethod:  req.PaymentMethod,
+		PaidAmount:     req.PaidAmount,
+		CouponCode:     req.CouponCode,
+		PointsRedeemed: req.RedeemPoints,
+		GiftCardCode:   req.GiftCardCode,
+		GiftCardLimit:  req.GiftCardAmount,
+		Payments:       orderPayments(req.Payments),
+		TipAmount:      req.TipAmount,
+		CashierID:      jwtCashierID(c),
+		OrderItems:     orderItems,
+	}
+	for _, giftCard := range req.GiftCards {
+		order.GiftCardSales = append(order.GiftCardSales, giftCard.Value)
 	}
 
 	if err := h.orderService.CreateOrder(order); err != nil {
@@ -48,6 +74,19 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "Order created successfully", order))
 }
 
+func orderPayments(payments []binder.PaymentRequest) []entity.OrderPayment {
+	var orderPayments []entity.OrderPayment
+	for _, payment := range payments {
+		orderPayments = append(orderPayments, entity.OrderPayment{
+			Method:         payment.Method,
+			Amount:         payment.Amount,
+			TenderedAmount: payment.PaidAmount,
+			GiftCardCode:   payment.GiftCardCode,
+		})
+	}
+	return orderPayments
+}
+
 func (h *OrderHandler) UpdateOrderStatus(c echo.Context) error {
 	orderIDParam := c.Param("order_id")
 	orderID, err := uuid.Parse(orderIDParam)
@@ -63,23 +102,96 @@ func (h *OrderHandler) UpdateOrderStatus(c echo.Context) error {
 	// isi manual order ID dari path param
 	req.OrderID = orderID
 
-	if err := h.orderService.UpdateOrderStatus(req.OrderID, req.Status); err != nil {
+	cashierID, _ := jwtUserID(c)
+	if err := h.orderService.UpdateOrderStatus(req.OrderID, req.Status, cashierID); err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Order status updated", nil))
 }
 
+// GetOrderHistory lists the orders of the caller, or of the user_id asked for
+// by an admin.
 func (h *OrderHandler) GetOrderHistory(c echo.Context) error {
-	userID := c.QueryParam("user_id")
-	if userID == "" {
-		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "user_id is required"))
+	userID, err := queryCustomerID(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
 	}
 
-	orders, err := h.orderService.GetOrderHistory(userID)
+	orders, err := h.orderService.GetOrderHistory(userID.String())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "order history fetched", orders))
 }
+
+func (h *OrderHandler) GetOrderQueue(c echo.Context) error {
+	orders, err := h.orderService.GetOrderQueue()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "order queue fetched", orders))
+}
+
+func (h *OrderHandler) BumpOrder(c echo.Context) error {
+	orderID, err := uuid.Parse(c.Param("order_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid order_id"))
+	}
+
+	var req binder.OrderBumpRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request body"))
+	}
+
+	order, err := h.orderService.BumpOrder(orderID, req.FulfilmentStatus)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "Order fulfilment updated", order))
+}
+
+// OrderFeed streams order events as server-sent events until the client goes
+// away. A display loads GET /orders/queue first and applies the events on top.
+// When the stream ends the display reconnects and loads the queue again.
+func (h *OrderHandler) OrderFeed(c echo.Context) error {
+	events, unsubscribe := h.orderEventService.Subscribe()
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(orderFeedHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
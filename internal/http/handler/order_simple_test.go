@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/token"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// createdOrders keeps the orders passed to CreateOrder
+type createdOrders struct {
+	service.OrderService
+	orders []*entity.Order
+}
+
+func (s *createdOrders) CreateOrder(order *entity.Order) error {
+	s.orders = append(s.orders, order)
+	return nil
+}
+
+// loggedInRequest is a POST request with body made by the user of the JWT
+func loggedInRequest(body string, userID uuid.UUID, role string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set("user", &jwt.Token{Claims: &token.JwtCustomClaims{ID: userID.String(), Role: role}})
+	return c, rec
+}
+
+// TestCreateOrderCashier tests that only staff are recorded as the cashier of an order
+func TestCreateOrderCashier(t *testing.T) {
+	customerID := uuid.New()
+	staffID := uuid.New()
+	body := `{"user_id": "` + customerID.String() + `", "payment_method": "cash", "paid_amount": "50000"}`
+
+	orders := &createdOrders{}
+	h := NewOrderHandler(orders, nil)
+
+	c, rec := loggedInRequest(body, customerID, "user")
+	if err := h.CreateOrder(c); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("Expected customer order to be created, got %d %v", rec.Code, err)
+	}
+	if orders.orders[0].CashierID != nil {
+		t.Errorf("Expected no cashier on a customer order, got %s", orders.orders[0].CashierID)
+	}
+
+	c, rec = loggedInRequest(body, staffID, "admin")
+	if err := h.CreateOrder(c); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("Expected staff order to be created, got %d %v", rec.Code, err)
+	}
+	if cashierID := orders.orders[1].CashierID; cashierID == nil || *cashierID != staffID {
+		t.Errorf("Expected cashier %s on a staff order, got %v", staffID, cashierID)
+	}
+}
@@ -33,6 +33,11 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "There is an input error"))
 	}
 
+	taxClassID, err := parseTaxClassID(input.TaxClassID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid tax_class_id"))
+	}
+
 	file, err := c.FormFile("photo")
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Failed to retrieve photo"))
@@ -72,6 +77,8 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 		PhotoURL:    photoPath,
 		Price:       input.Price,
 		Stock:       input.Stock,
+		Category:    input.Category,
+		TaxClassID:  taxClassID,
 	}
 
 	product, err := h.productService.CreateProduct(newProduct)
@@ -93,6 +100,11 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Product ID cannot be empty"))
 	}
 
+	taxClassID, err := parseTaxClassID(input.TaxClassID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Invalid tax_class_id"))
+	}
+
 	// Ambil data produk berdasarkan ID
 	oldProduct, err := h.productService.FindProductByID(input.ProductID.String())
 	if err != nil || oldProduct == nil {
@@ -148,7 +160,7 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 	if input.Description == "" {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Product description cannot be empty"))
 	}
-	if input.Price <= 0 {
+	if !input.Price.IsPositive() {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "Price must be greater than 0"))
 	}
 	if input.Stock < 0 {
@@ -163,6 +175,8 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 		input.Price,
 		input.Stock,
 	)
+	updatedProduct.Category = input.Category
+	updatedProduct.TaxClassID = taxClassID
 
 	result, err := h.productService.UpdateProduct(updatedProduct)
 	if err != nil {
@@ -203,3 +217,15 @@ func (h *ProductHandler) FindAllProduct(c echo.Context) error {
 	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "success show data products", products))
 
 }
+
+// parseTaxClassID treats an empty form value as "no tax class".
+func parseTaxClassID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	taxClassID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &taxClassID, nil
+}
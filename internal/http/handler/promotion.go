@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PromotionHandler struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionHandler(promotionService service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promotionService: promotionService}
+}
+
+func (h *PromotionHandler) CreatePromotion(c echo.Context) error {
+	var req binder.PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	promotion, err := promotionFromRequest(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	created, err := h.promotionService.CreatePromotion(promotion)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "promotion created", created))
+}
+
+func (h *PromotionHandler) FindAllPromotions(c echo.Context) error {
+	promotions, err := h.promotionService.FindAllPromotions()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "promotions fetched", promotions))
+}
+
+func (h *PromotionHandler) UpdatePromotion(c echo.Context) error {
+	promotionID, err := uuid.Parse(c.Param("promotion_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid promotion_id"))
+	}
+
+	var req binder.PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	promotion, err := promotionFromRequest(req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+	promotion.PromotionID = promotionID
+
+	updated, err := h.promotionService.UpdatePromotion(promotion)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "promotion updated", updated))
+}
+
+func (h *PromotionHandler) DeletePromotion(c echo.Context) error {
+	promotionID, err := uuid.Parse(c.Param("promotion_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid promotion_id"))
+	}
+
+	if err := h.promotionService.DeletePromotion(promotionID); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "promotion deleted", nil))
+}
+
+func promotionFromRequest(req binder.PromotionRequest) (*entity.Promotion, error) {
+	promotion := &entity.Promotion{
+		Name:             req.Name,
+		Type:             req.Type,
+		Percentage:       req.Percentage,
+		Amount:           req.Amount,
+		Category:         req.Category,
+		BuyQuantity:      req.BuyQuantity,
+		GetQuantity:      req.GetQuantity,
+		MinSpend:         req.MinSpend,
+		UsageLimit:       req.UsageLimit,
+		Stackable:        req.Stackable,
+		RequiresCode:     req.RequiresCode,
+		PerCustomerLimit: req.PerCustomerLimit,
+		Priority:         req.Priority,
+		Active:           req.Active == nil || *req.Active,
+	}
+
+	if req.ProductID != "" {
+		productID, err := uuid.Parse(req.ProductID)
+		if err != nil {
+			return nil, errors.New("invalid product_id")
+		}
+		promotion.ProductID = &productID
+	}
+
+	if req.StartsAt == "" {
+		promotion.StartsAt = time.Now()
+	} else {
+		startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+		if err != nil {
+			return nil, errors.New("invalid starts_at format, use RFC3339")
+		}
+		promotion.StartsAt = startsAt
+	}
+
+	if req.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+		if err != nil {
+			return nil, errors.New("invalid ends_at format, use RFC3339")
+		}
+		promotion.EndsAt = &endsAt
+	}
+
+	return promotion, nil
+}
@@ -1,14 +1,20 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
 	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
 	"Kevinmajesta/OrderManagementAPI/internal/service"
 	"Kevinmajesta/OrderManagementAPI/pkg/response"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type ReceiptHandler struct {
@@ -33,8 +39,88 @@ func (h *ReceiptHandler) GenerateReceipt(c echo.Context) error {
 	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "receipt generated", receipt))
 }
 
+// VoidReceipt voids a receipt. Only a manager can void, and the reason is
+// kept for the audit report.
+func (h *ReceiptHandler) VoidReceipt(c echo.Context) error {
+	receiptID, err := uuid.Parse(c.Param("receipt_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid receipt_id"))
+	}
+
+	var req binder.ReceiptVoidRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	managerID, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	receipt, err := h.receiptService.VoidReceipt(receiptID, managerID, req.Reason)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "receipt voided", receipt))
+}
+
+// GetReceiptAuditReport reports voids and reprints per cashier between the
+// from and to days, both today when not given.
+func (h *ReceiptHandler) GetReceiptAuditReport(c echo.Context) error {
+	// Missing days are today in the store's time zone
+	var from, to time.Time
+	var err error
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid from format, use YYYY-MM-DD"))
+		}
+	}
+	if toStr := c.QueryParam("to"); toStr != "" {
+		if to, err = time.Parse("2006-01-02", toStr); err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid to format, use YYYY-MM-DD"))
+		}
+	}
+
+	report, err := h.receiptService.GetReceiptAuditReport(from, to)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "receipt audit report generated", report))
+}
+
+// VerifyReceipt checks the signed link in the QR code of a receipt. It needs
+// no login, so it only answers with a summary of the receipt.
+func (h *ReceiptHandler) VerifyReceipt(c echo.Context) error {
+	number, total, issuedAt, signature := c.QueryParam("n"), c.QueryParam("t"), c.QueryParam("ts"), c.QueryParam("s")
+	if number == "" || total == "" || issuedAt == "" || signature == "" {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "n, t, ts and s are required"))
+	}
+
+	verification, err := h.receiptService.VerifyReceipt(number, total, issuedAt, signature)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "receipt verified", verification))
+}
+
+// GetReceiptByID also serves /receipts/{id}.pdf, .escpos and .txt, as the
+// router cannot have a second route that differs from this one only by the
+// suffix.
 func (h *ReceiptHandler) GetReceiptByID(c echo.Context) error {
 	receiptIDParam := c.Param("receipt_id")
+	if id, ok := strings.CutSuffix(receiptIDParam, ".pdf"); ok {
+		return h.getReceiptPDF(c, id)
+	}
+	if id, ok := strings.CutSuffix(receiptIDParam, ".escpos"); ok {
+		return h.getReceiptThermal(c, id, false)
+	}
+	if id, ok := strings.CutSuffix(receiptIDParam, ".txt"); ok {
+		return h.getReceiptThermal(c, id, true)
+	}
+
 	receiptID, err := uuid.Parse(receiptIDParam)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid receipt_id"))
@@ -48,6 +134,78 @@ func (h *ReceiptHandler) GetReceiptByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "receipt fetched", receipt))
 }
 
+// getReceiptPDF prints the receipt as a PDF, on the paper given by the paper
+// query parameter or the receipt_paper setting.
+func (h *ReceiptHandler) getReceiptPDF(c echo.Context, receiptIDParam string) error {
+	receiptID, err := uuid.Parse(receiptIDParam)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid receipt_id"))
+	}
+
+	paper := c.QueryParam("paper")
+	if paper != "" && paper != entity.ReceiptPaperA4 && paper != entity.ReceiptPaper80mm {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "paper must be a4 or 80mm"))
+	}
+
+	printedBy, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	receipt, data, err := h.receiptService.RenderReceiptPDF(receiptID, paper, printedBy)
+	if err != nil {
+		return receiptRenderError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", receipt.ReceiptNumber+".pdf"))
+	return c.Blob(http.StatusOK, "application/pdf", data)
+}
+
+// getReceiptThermal prints the receipt for a thermal printer as ESC/POS
+// bytes, or as the plain text preview when preview is set. The paper query
+// parameter is 58mm or 80mm.
+func (h *ReceiptHandler) getReceiptThermal(c echo.Context, receiptIDParam string, preview bool) error {
+	receiptID, err := uuid.Parse(receiptIDParam)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid receipt_id"))
+	}
+
+	paper := c.QueryParam("paper")
+	if paper != "" && paper != entity.ReceiptPaper58mm && paper != entity.ReceiptPaper80mm {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "paper must be 58mm or 80mm"))
+	}
+
+	printedBy, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	if preview {
+		_, text, err := h.receiptService.RenderReceiptText(receiptID, paper, printedBy)
+		if err != nil {
+			return receiptRenderError(c, err)
+		}
+		return c.String(http.StatusOK, text)
+	}
+
+	receipt, data, err := h.receiptService.RenderReceiptESCPOS(receiptID, paper, printedBy)
+	if err != nil {
+		return receiptRenderError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", receipt.ReceiptNumber+".bin"))
+	return c.Blob(http.StatusOK, echo.MIMEOctetStream, data)
+}
+
+// receiptRenderError answers a failed print: 404 when the receipt does not
+// exist and 500 when it could not be rendered.
+func receiptRenderError(c echo.Context, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, "receipt not found"))
+	}
+	return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+}
+
 func (h *ReceiptHandler) GetReceiptByOrderID(c echo.Context) error {
 	orderIDParam := c.QueryParam("order_id")
 	if orderIDParam == "" {
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// failingRenders fails every render with err
+type failingRenders struct {
+	service.ReceiptService
+	err error
+}
+
+func (s *failingRenders) RenderReceiptPDF(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, []byte, error) {
+	return nil, nil, s.err
+}
+
+func (s *failingRenders) RenderReceiptText(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, string, error) {
+	return nil, "", s.err
+}
+
+// TestReceiptRenderError tests that only a missing receipt is answered as not found
+func TestReceiptRenderError(t *testing.T) {
+	tests := []struct {
+		name     string
+		suffix   string
+		err      error
+		wantCode int
+	}{
+		{name: "missing receipt pdf", suffix: ".pdf", err: gorm.ErrRecordNotFound, wantCode: http.StatusNotFound},
+		{name: "broken pdf", suffix: ".pdf", err: errors.New("template: receipt_header: bad field"), wantCode: http.StatusInternalServerError},
+		{name: "missing receipt preview", suffix: ".txt", err: gorm.ErrRecordNotFound, wantCode: http.StatusNotFound},
+		{name: "broken preview", suffix: ".txt", err: errors.New("template: receipt_footer: bad field"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := loggedInRequest("", uuid.New(), "admin")
+			c.SetParamNames("receipt_id")
+			c.SetParamValues(uuid.New().String() + tt.suffix)
+
+			if err := NewReceiptHandler(&failingRenders{err: tt.err}).GetReceiptByID(c); err != nil {
+				t.Fatalf("Expected the error to be answered, got %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultReportSendHour is when a report is sent if no send_hour is given.
+const defaultReportSendHour = 7
+
+type ReportSubscriptionHandler struct {
+	reportSubscriptionService service.ReportSubscriptionService
+}
+
+func NewReportSubscriptionHandler(reportSubscriptionService service.ReportSubscriptionService) *ReportSubscriptionHandler {
+	return &ReportSubscriptionHandler{reportSubscriptionService: reportSubscriptionService}
+}
+
+func (h *ReportSubscriptionHandler) CreateSubscription(c echo.Context) error {
+	var req binder.ReportSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	createdBy, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	subscription := reportSubscriptionFromRequest(req)
+	subscription.CreatedBy = &createdBy
+
+	created, err := h.reportSubscriptionService.CreateSubscription(subscription)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "report subscription created", created))
+}
+
+func (h *ReportSubscriptionHandler) FindAllSubscriptions(c echo.Context) error {
+	subscriptions, err := h.reportSubscriptionService.FindAllSubscriptions()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "report subscriptions fetched", subscriptions))
+}
+
+func (h *ReportSubscriptionHandler) UpdateSubscription(c echo.Context) error {
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid subscription_id"))
+	}
+
+	var req binder.ReportSubscriptionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	subscription := reportSubscriptionFromRequest(req)
+	subscription.SubscriptionID = subscriptionID
+
+	updated, err := h.reportSubscriptionService.UpdateSubscription(subscription)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "report subscription updated", updated))
+}
+
+func (h *ReportSubscriptionHandler) DeleteSubscription(c echo.Context) error {
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid subscription_id"))
+	}
+
+	if err := h.reportSubscriptionService.DeleteSubscription(subscriptionID); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "report subscription deleted", nil))
+}
+
+// GetDeliveries lists the latest reports sent, retried or failed for a
+// subscription.
+func (h *ReportSubscriptionHandler) GetDeliveries(c echo.Context) error {
+	subscriptionID, err := uuid.Parse(c.Param("subscription_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid subscription_id"))
+	}
+
+	deliveries, err := h.reportSubscriptionService.GetDeliveries(subscriptionID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "report deliveries fetched", deliveries))
+}
+
+func reportSubscriptionFromRequest(req binder.ReportSubscriptionRequest) *entity.ReportSubscription {
+	subscription := &entity.ReportSubscription{
+		ReportType: req.ReportType,
+		SendHour:   defaultReportSendHour,
+		Recipients: strings.Join(req.Recipients, ","),
+		Format:     req.Format,
+		Active:     req.Active == nil || *req.Active,
+	}
+	if req.SendHour != nil {
+		subscription.SendHour = *req.SendHour
+	}
+	return subscription
+}
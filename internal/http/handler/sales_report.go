@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
 	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
 	"Kevinmajesta/OrderManagementAPI/internal/service"
 	"Kevinmajesta/OrderManagementAPI/pkg/response"
@@ -21,6 +23,11 @@ func NewSalesReportHandler(salesReportService service.SalesReportService) *Sales
 }
 
 func (h *SalesReportHandler) GetSalesReportByDateRange(c echo.Context) error {
+	format, ok := salesReportFormat(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "format must be json, csv or xlsx"))
+	}
+
 	var req binder.SalesReportDateRangeRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
@@ -45,18 +52,23 @@ func (h *SalesReportHandler) GetSalesReportByDateRange(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
 	}
 
-	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "sales report generated", report))
+	return h.respondSalesReport(c, format, report, "sales-report-"+req.StartDate+"-to-"+req.EndDate, "sales report generated")
 }
 
 func (h *SalesReportHandler) GetDailySalesReport(c echo.Context) error {
-	dateStr := c.QueryParam("date")
-	if dateStr == "" {
-		dateStr = time.Now().Format("2006-01-02")
+	format, ok := salesReportFormat(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "format must be json, csv or xlsx"))
 	}
 
-	date, err := time.Parse("2006-01-02", dateStr)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid date format, use YYYY-MM-DD"))
+	// Without a date the service reports today in the store's time zone
+	var date time.Time
+	if dateStr := c.QueryParam("date"); dateStr != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid date format, use YYYY-MM-DD"))
+		}
 	}
 
 	report, err := h.salesReportService.GetDailySalesReport(date)
@@ -64,17 +76,20 @@ func (h *SalesReportHandler) GetDailySalesReport(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
 	}
 
-	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "daily sales report generated", report))
+	return h.respondSalesReport(c, format, report, "sales-report-"+report.PeriodStartDate.Format("2006-01-02"), "daily sales report generated")
 }
 
 func (h *SalesReportHandler) GetMonthlySalesReport(c echo.Context) error {
+	format, ok := salesReportFormat(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "format must be json, csv or xlsx"))
+	}
+
 	yearStr := c.QueryParam("year")
 	monthStr := c.QueryParam("month")
 
-	now := time.Now()
-	year := now.Year()
-	month := int(now.Month())
-
+	// A missing year or month is the current one in the store's time zone
+	year, month := 0, 0
 	if yearStr != "" {
 		y, err := strconv.Atoi(yearStr)
 		if err == nil {
@@ -94,5 +109,35 @@ func (h *SalesReportHandler) GetMonthlySalesReport(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
 	}
 
-	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "monthly sales report generated", report))
+	return h.respondSalesReport(c, format, report, "sales-report-"+report.PeriodStartDate.Format("2006-01"), "monthly sales report generated")
+}
+
+// salesReportFormat reads the format query parameter. It defaults to JSON.
+func salesReportFormat(c echo.Context) (string, bool) {
+	switch format := c.QueryParam("format"); format {
+	case "", entity.ReportFormatJSON:
+		return entity.ReportFormatJSON, true
+	case entity.ReportFormatCSV, entity.ReportFormatXLSX:
+		return format, true
+	default:
+		return "", false
+	}
+}
+
+// respondSalesReport sends the report as the JSON response, or streams it as
+// a CSV or XLSX download named filename. Once the download has started the
+// status can no longer change, so a failure part way is only logged.
+func (h *SalesReportHandler) respondSalesReport(c echo.Context, format string, report *entity.SalesReport, filename, message string) error {
+	if format == entity.ReportFormatJSON {
+		return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, message, report))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, service.ReportContentType(format))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+	c.Response().WriteHeader(http.StatusOK)
+
+	if err := h.salesReportService.ExportSalesReport(report, format, c.Response()); err != nil {
+		c.Logger().Errorf("export sales report: %v", err)
+	}
+	return nil
 }
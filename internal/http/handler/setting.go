@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SettingHandler struct {
+	settingService service.SettingService
+}
+
+func NewSettingHandler(settingService service.SettingService) *SettingHandler {
+	return &SettingHandler{settingService: settingService}
+}
+
+func (h *SettingHandler) GetSettings(c echo.Context) error {
+	settings, err := h.settingService.GetSettings()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "settings fetched", settings))
+}
+
+func (h *SettingHandler) UpdateSettings(c echo.Context) error {
+	var req binder.SettingUpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	values := make(map[string]string)
+	if req.StoreName != nil {
+		values[entity.SettingStoreName] = *req.StoreName
+	}
+	if req.StoreAddress != nil {
+		values[entity.SettingStoreAddress] = *req.StoreAddress
+	}
+	if req.StorePhone != nil {
+		values[entity.SettingStorePhone] = *req.StorePhone
+	}
+	if req.TaxRate != nil {
+		values[entity.SettingTaxRate] = strconv.FormatFloat(*req.TaxRate, 'f', -1, 64)
+	}
+	if req.PricesIncludeTax != nil {
+		values[entity.SettingPricesIncludeTax] = strconv.FormatBool(*req.PricesIncludeTax)
+	}
+	if req.ReceiptPrefix != nil {
+		values[entity.SettingReceiptPrefix] = *req.ReceiptPrefix
+	}
+	if req.ReceiptNumberFormat != nil {
+		values[entity.SettingReceiptNumberFormat] = *req.ReceiptNumberFormat
+	}
+	if req.LoyaltySpendPerPoint != nil {
+		values[entity.SettingLoyaltySpendPerPoint] = req.LoyaltySpendPerPoint.String()
+	}
+	if req.LoyaltyPointValue != nil {
+		values[entity.SettingLoyaltyPointValue] = req.LoyaltyPointValue.String()
+	}
+	if req.MembershipWindowDays != nil {
+		values[entity.SettingMembershipWindowDays] = strconv.Itoa(*req.MembershipWindowDays)
+	}
+	if req.GiftCardValidityDays != nil {
+		values[entity.SettingGiftCardValidityDays] = strconv.Itoa(*req.GiftCardValidityDays)
+	}
+	if req.ServiceChargeRate != nil {
+		values[entity.SettingServiceChargeRate] = strconv.FormatFloat(*req.ServiceChargeRate, 'f', -1, 64)
+	}
+	if req.ReceiptHeader != nil {
+		values[entity.SettingReceiptHeader] = *req.ReceiptHeader
+	}
+	if req.ReceiptFooter != nil {
+		values[entity.SettingReceiptFooter] = *req.ReceiptFooter
+	}
+	if req.ReceiptPaper != nil {
+		values[entity.SettingReceiptPaper] = *req.ReceiptPaper
+	}
+	if req.ReceiptVerifyURL != nil {
+		values[entity.SettingReceiptVerifyURL] = *req.ReceiptVerifyURL
+	}
+	if req.Timezone != nil {
+		values[entity.SettingTimezone] = *req.Timezone
+	}
+
+	settings, err := h.settingService.UpdateSettings(values)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "settings updated", settings))
+}
@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"net/http"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type TabHandler struct {
+	tabService service.TabService
+}
+
+func NewTabHandler(tabService service.TabService) *TabHandler {
+	return &TabHandler{tabService: tabService}
+}
+
+func (h *TabHandler) CreateTable(c echo.Context) error {
+	var req binder.TableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	table := &entity.DiningTable{
+		Name:  req.Name,
+		Area:  req.Area,
+		Seats: req.Seats,
+	}
+	if err := h.tabService.CreateTable(table); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "table created", table))
+}
+
+func (h *TabHandler) UpdateTable(c echo.Context) error {
+	tableID, err := uuid.Parse(c.Param("table_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid table_id"))
+	}
+
+	var req binder.TableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	table := &entity.DiningTable{
+		TableID:  tableID,
+		Name:     req.Name,
+		Area:     req.Area,
+		Seats:    req.Seats,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	}
+	updated, err := h.tabService.UpdateTable(table)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "table updated", updated))
+}
+
+func (h *TabHandler) GetFloorPlan(c echo.Context) error {
+	plan, err := h.tabService.GetFloorPlan()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "floor plan fetched", plan))
+}
+
+func (h *TabHandler) OpenTab(c echo.Context) error {
+	staffID, err := jwtUserID(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.ErrorResponse(http.StatusUnauthorized, err.Error()))
+	}
+
+	tableID, err := uuid.Parse(c.Param("table_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid table_id"))
+	}
+
+	var req binder.TabOpenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+	if req.UserID == uuid.Nil {
+		req.UserID = staffID
+	}
+	if req.GuestCount == 0 {
+		req.GuestCount = 1
+	}
+
+	tab, err := h.tabService.OpenTab(tableID, req.UserID, &staffID, req.GuestCount, req.Note)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "tab opened", tab))
+}
+
+func (h *TabHandler) GetTab(c echo.Context) error {
+	tabID, err := uuid.Parse(c.Param("tab_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tab_id"))
+	}
+
+	tab, err := h.tabService.GetTab(tabID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, response.ErrorResponse(http.StatusNotFound, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "tab fetched", tab))
+}
+
+func (h *TabHandler) AddRound(c echo.Context) error {
+	tabID, err := uuid.Parse(c.Param("tab_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tab_id"))
+	}
+
+	var req binder.TabRoundRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	items := make([]entity.TabItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		tabItem := entity.TabItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Note:      item.Note,
+		}
+		for _, optionID := range item.ModifierOptionIDs {
+			tabItem.Modifiers = append(tabItem.Modifiers, entity.TabItemModifier{ModifierOptionID: optionID})
+		}
+		items = append(items, tabItem)
+	}
+
+	ticket, err := h.tabService.AddRound(tabID, items, req.Hold, req.Note)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "round added", ticket))
+}
+
+func (h *TabHandler) SplitTab(c echo.Context) error {
+	tabID, err := uuid.Parse(c.Param("tab_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tab_id"))
+	}
+
+	var req binder.TabSplitRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	splits := make([]entity.TabItemSplit, 0, len(req.Items))
+	for _, item := range req.Items {
+		splits = append(splits, entity.TabItemSplit{TabItemID: item.TabItemID, Quantity: item.Quantity})
+	}
+
+	tab, err := h.tabService.SplitTab(tabID, splits)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "tab split", tab))
+}
+
+func (h *TabHandler) MergeTabs(c echo.Context) error {
+	tabID, err := uuid.Parse(c.Param("tab_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tab_id"))
+	}
+
+	var req binder.TabMergeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	tab, err := h.tabService.MergeTabs(tabID, req.SourceTabID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "tabs merged", tab))
+}
+
+func (h *TabHandler) CancelTab(c echo.Context) error {
+	tabID, err := uuid.Parse(c.Param("tab_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tab_id"))
+	}
+
+	if err := h.tabService.CancelTab(tabID); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "tab cancelled", nil))
+}
+
+// QuoteTab prints the bill of a tab, with an optional coupon_code and
+// tip_amount.
+func (h *TabHandler) QuoteTab(c echo.Context) error {
+	tabID, err := uuid.Parse(c.Param("tab_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tab_id"))
+	}
+
+	var tipAmount money.Amount
+	if amount := c.QueryParam("tip_amount"); amount != "" {
+		tipAmount, err = money.Parse(amount)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tip_amount"))
+		}
+	}
+
+	quote, err := h.tabService.QuoteTab(tabID, &entity.Order{
+		CouponCode: c.QueryParam("coupon_code"),
+		TipAmount:  tipAmount,
+	})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "tab quote fetched", quote))
+}
+
+func (h *TabHandler) PayTab(c echo.Context) error {
+	tabID, err := uuid.Parse(c.Param("tab_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tab_id"))
+	}
+
+	var req binder.TabPayRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	payment := &entity.Order{
+		PaymentMethod:  req.PaymentMethod,
+		PaidAmount:     req.PaidAmount,
+		CouponCode:     req.CouponCode,
+		PointsRedeemed: req.RedeemPoints,
+		GiftCardCode:   req.GiftCardCode,
+		GiftCardLimit:  req.GiftCardAmount,
+		Payments:       orderPayments(req.Payments),
+		TipAmount:      req.TipAmount,
+		CashierID:      jwtCashierID(c),
+	}
+
+	order, err := h.tabService.PayTab(tabID, payment)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "tab paid", order))
+}
+
+// FindKitchenTickets lists tickets by status, the ones not done yet when no
+// status is given.
+func (h *TabHandler) FindKitchenTickets(c echo.Context) error {
+	tickets, err := h.tabService.FindKitchenTickets(c.QueryParam("status"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "kitchen tickets fetched", tickets))
+}
+
+func (h *TabHandler) SendKitchenTicket(c echo.Context) error {
+	ticketID, err := uuid.Parse(c.Param("ticket_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid ticket_id"))
+	}
+
+	ticket, err := h.tabService.SendKitchenTicket(ticketID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "kitchen ticket sent", ticket))
+}
+
+func (h *TabHandler) CompleteKitchenTicket(c echo.Context) error {
+	ticketID, err := uuid.Parse(c.Param("ticket_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid ticket_id"))
+	}
+
+	ticket, err := h.tabService.CompleteKitchenTicket(ticketID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "kitchen ticket done", ticket))
+}
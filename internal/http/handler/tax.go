@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/http/binder"
+	"Kevinmajesta/OrderManagementAPI/internal/service"
+	"Kevinmajesta/OrderManagementAPI/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type TaxHandler struct {
+	taxService service.TaxService
+}
+
+func NewTaxHandler(taxService service.TaxService) *TaxHandler {
+	return &TaxHandler{taxService: taxService}
+}
+
+func (h *TaxHandler) CreateTaxClass(c echo.Context) error {
+	var req binder.TaxClassCreateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	taxClass, err := h.taxService.CreateTaxClass(req.Name, req.Description)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "tax class created", taxClass))
+}
+
+func (h *TaxHandler) FindAllTaxClasses(c echo.Context) error {
+	taxClasses, err := h.taxService.FindAllTaxClasses()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "tax classes fetched", taxClasses))
+}
+
+func (h *TaxHandler) AddTaxRate(c echo.Context) error {
+	taxClassID, err := uuid.Parse(c.Param("tax_class_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tax_class_id"))
+	}
+
+	var req binder.TaxRateCreateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid request"))
+	}
+
+	var effectiveFrom time.Time
+	if req.EffectiveFrom != "" {
+		effectiveFrom, err = time.Parse(time.RFC3339, req.EffectiveFrom)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid effective_from format, use RFC3339"))
+		}
+	}
+
+	taxRate, err := h.taxService.AddTaxRate(taxClassID, req.Rate, effectiveFrom)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, response.SuccessResponse(http.StatusCreated, "tax rate added", taxRate))
+}
+
+func (h *TaxHandler) GetTaxRates(c echo.Context) error {
+	taxClassID, err := uuid.Parse(c.Param("tax_class_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.ErrorResponse(http.StatusBadRequest, "invalid tax_class_id"))
+	}
+
+	taxRates, err := h.taxService.GetTaxRates(taxClassID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, response.ErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, response.SuccessResponse(http.StatusOK, "tax rates fetched", taxRates))
+}
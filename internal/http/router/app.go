@@ -20,7 +20,8 @@ var (
 
 func PublicRoutes(userHandler handler.UserHandler,
 	adminHandler handler.AdminHandler,
-	midtransHandler *handler.MidtransHandler) []*route.Route {
+	midtransHandler *handler.MidtransHandler,
+	receiptHandler *handler.ReceiptHandler) []*route.Route {
 	return []*route.Route{
 		{
 			Method:  http.MethodPost,
@@ -62,12 +63,23 @@ func PublicRoutes(userHandler handler.UserHandler,
 			Path:    "/midtrans/notification",
 			Handler: midtransHandler.HandleNotification,
 		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/receipts/verify",
+			Handler: receiptHandler.VerifyReceipt,
+		},
 	}
 }
 
 func PrivateRoutes(userHandler handler.UserHandler,
 	adminHandler handler.AdminHandler, productHandler handler.ProductHandler,
-	orderHandler handler.OrderHandler, cartHandler *handler.CartHandler, receiptHandler *handler.ReceiptHandler, salesReportHandler *handler.SalesReportHandler) []*route.Route {
+	orderHandler handler.OrderHandler, cartHandler *handler.CartHandler, receiptHandler *handler.ReceiptHandler, salesReportHandler *handler.SalesReportHandler,
+	settingHandler *handler.SettingHandler, taxHandler *handler.TaxHandler,
+	promotionHandler *handler.PromotionHandler, couponHandler *handler.CouponHandler,
+	loyaltyHandler *handler.LoyaltyHandler, membershipHandler *handler.MembershipHandler,
+	giftCardHandler *handler.GiftCardHandler, shiftHandler *handler.ShiftHandler,
+	creditHandler *handler.CreditHandler, tabHandler *handler.TabHandler,
+	modifierHandler *handler.ModifierHandler, reportSubscriptionHandler *handler.ReportSubscriptionHandler) []*route.Route {
 	return []*route.Route{
 
 		{
@@ -161,6 +173,12 @@ func PrivateRoutes(userHandler handler.UserHandler,
 			Handler: cartHandler.GetCart,
 			Roles:   allRoles,
 		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/cart/quote",
+			Handler: cartHandler.Quote,
+			Roles:   allRoles,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    "/cart/checkout",
@@ -179,6 +197,24 @@ func PrivateRoutes(userHandler handler.UserHandler,
 			Handler: orderHandler.GetOrderHistory,
 			Roles:   allRoles,
 		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/orders/queue",
+			Handler: orderHandler.GetOrderQueue,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/orders/feed",
+			Handler: orderHandler.OrderFeed,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPatch,
+			Path:    "/orders/:order_id/fulfilment",
+			Handler: orderHandler.BumpOrder,
+			Roles:   onlyAdmin,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    "/receipts",
@@ -197,6 +233,18 @@ func PrivateRoutes(userHandler handler.UserHandler,
 			Handler: receiptHandler.GetReceiptsByUserID,
 			Roles:   allRoles,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/receipts/:receipt_id/void",
+			Handler: receiptHandler.VoidReceipt,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/reports/receipt-audit",
+			Handler: receiptHandler.GetReceiptAuditReport,
+			Roles:   onlyAdmin,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    "/reports/sales/date-range",
@@ -215,5 +263,407 @@ func PrivateRoutes(userHandler handler.UserHandler,
 			Handler: salesReportHandler.GetMonthlySalesReport,
 			Roles:   onlyAdmin,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/reports/subscriptions",
+			Handler: reportSubscriptionHandler.CreateSubscription,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/reports/subscriptions",
+			Handler: reportSubscriptionHandler.FindAllSubscriptions,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/reports/subscriptions/:subscription_id",
+			Handler: reportSubscriptionHandler.UpdateSubscription,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/reports/subscriptions/:subscription_id",
+			Handler: reportSubscriptionHandler.DeleteSubscription,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/reports/subscriptions/:subscription_id/deliveries",
+			Handler: reportSubscriptionHandler.GetDeliveries,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/settings",
+			Handler: settingHandler.GetSettings,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/settings",
+			Handler: settingHandler.UpdateSettings,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tax-classes",
+			Handler: taxHandler.CreateTaxClass,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/tax-classes",
+			Handler: taxHandler.FindAllTaxClasses,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tax-classes/:tax_class_id/rates",
+			Handler: taxHandler.AddTaxRate,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/tax-classes/:tax_class_id/rates",
+			Handler: taxHandler.GetTaxRates,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/promotions",
+			Handler: promotionHandler.CreatePromotion,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/promotions",
+			Handler: promotionHandler.FindAllPromotions,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/promotions/:promotion_id",
+			Handler: promotionHandler.UpdatePromotion,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/promotions/:promotion_id",
+			Handler: promotionHandler.DeletePromotion,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/promotions/:promotion_id/codes",
+			Handler: couponHandler.CreateCodes,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/promotions/:promotion_id/codes",
+			Handler: couponHandler.FindCodes,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/promotions/:promotion_id/redemptions",
+			Handler: couponHandler.GetRedemptionReport,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/loyalty/balance",
+			Handler: loyaltyHandler.GetBalance,
+			Roles:   allRoles,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/loyalty/history",
+			Handler: loyaltyHandler.GetHistory,
+			Roles:   allRoles,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/membership-tiers",
+			Handler: membershipHandler.CreateTier,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/membership-tiers",
+			Handler: membershipHandler.FindAllTiers,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/membership-tiers/recalculate",
+			Handler: membershipHandler.RecalculateTiers,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/membership-tiers/:tier_id",
+			Handler: membershipHandler.UpdateTier,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/membership-tiers/:tier_id",
+			Handler: membershipHandler.DeleteTier,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/membership",
+			Handler: membershipHandler.GetMembership,
+			Roles:   allRoles,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/gift-cards",
+			Handler: giftCardHandler.IssueGiftCard,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/gift-cards",
+			Handler: giftCardHandler.FindAllGiftCards,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/gift-cards/balance",
+			Handler: giftCardHandler.CheckBalance,
+			Roles:   allRoles,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/gift-cards/:gift_card_id",
+			Handler: giftCardHandler.FindGiftCardByID,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/gift-cards/:gift_card_id/top-up",
+			Handler: giftCardHandler.TopUpGiftCard,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/gift-cards/:gift_card_id/void",
+			Handler: giftCardHandler.VoidGiftCard,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/shifts",
+			Handler: shiftHandler.OpenShift,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/shifts/current",
+			Handler: shiftHandler.GetCurrentShift,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/shifts/current/movements",
+			Handler: shiftHandler.AddCashMovement,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/shifts/current/close",
+			Handler: shiftHandler.CloseShift,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/shifts/:shift_id",
+			Handler: shiftHandler.GetShiftReport,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/reports/z-report",
+			Handler: shiftHandler.GetZReport,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/credit-accounts",
+			Handler: creditHandler.FindAllAccounts,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/credit-accounts/statements/email",
+			Handler: creditHandler.EmailAllStatements,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/credit-accounts/:user_id",
+			Handler: creditHandler.ApproveAccount,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/credit-accounts/:user_id/statement",
+			Handler: creditHandler.GetStatement,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/credit-accounts/:user_id/statement/email",
+			Handler: creditHandler.EmailStatement,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/credit-accounts/:user_id/payments",
+			Handler: creditHandler.RecordPayment,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/credit/statement",
+			Handler: creditHandler.GetStatement,
+			Roles:   allRoles,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/reports/credit-aging",
+			Handler: creditHandler.GetAgingReport,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tables",
+			Handler: tabHandler.CreateTable,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/tables/:table_id",
+			Handler: tabHandler.UpdateTable,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/tables",
+			Handler: tabHandler.GetFloorPlan,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tables/:table_id/tabs",
+			Handler: tabHandler.OpenTab,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/tabs/:tab_id",
+			Handler: tabHandler.GetTab,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    "/tabs/:tab_id",
+			Handler: tabHandler.CancelTab,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tabs/:tab_id/rounds",
+			Handler: tabHandler.AddRound,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tabs/:tab_id/split",
+			Handler: tabHandler.SplitTab,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tabs/:tab_id/merge",
+			Handler: tabHandler.MergeTabs,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/tabs/:tab_id/quote",
+			Handler: tabHandler.QuoteTab,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/tabs/:tab_id/pay",
+			Handler: tabHandler.PayTab,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/kitchen-tickets",
+			Handler: tabHandler.FindKitchenTickets,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/kitchen-tickets/:ticket_id/send",
+			Handler: tabHandler.SendKitchenTicket,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/kitchen-tickets/:ticket_id/done",
+			Handler: tabHandler.CompleteKitchenTicket,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/modifier-groups",
+			Handler: modifierHandler.FindAllGroups,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/modifier-groups",
+			Handler: modifierHandler.CreateGroup,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/modifier-groups/:modifier_group_id",
+			Handler: modifierHandler.UpdateGroup,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/modifier-groups/:modifier_group_id/options",
+			Handler: modifierHandler.AddOption,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/modifier-options/:modifier_option_id",
+			Handler: modifierHandler.UpdateOption,
+			Roles:   onlyAdmin,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/products/:product_id/modifiers",
+			Handler: modifierHandler.GetProductModifiers,
+			Roles:   allRoles,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    "/products/:product_id/modifiers",
+			Handler: modifierHandler.SetProductGroups,
+			Roles:   onlyAdmin,
+		},
 	}
 }
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	email "Kevinmajesta/OrderManagementAPI/pkg/email"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -47,6 +48,20 @@ func (mr *MockEmailSenderServiceMockRecorder) SendEmail(to, subject, body interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockEmailSenderService)(nil).SendEmail), to, subject, body)
 }
 
+// SendHTMLEmail mocks base method.
+func (m *MockEmailSenderService) SendHTMLEmail(to []string, subject, body string, attachments []email.Attachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHTMLEmail", to, subject, body, attachments)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHTMLEmail indicates an expected call of SendHTMLEmail.
+func (mr *MockEmailSenderServiceMockRecorder) SendHTMLEmail(to, subject, body, attachments interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHTMLEmail", reflect.TypeOf((*MockEmailSenderService)(nil).SendHTMLEmail), to, subject, body, attachments)
+}
+
 // SendResetPasswordEmail mocks base method.
 func (m *MockEmailSenderService) SendResetPasswordEmail(to, name, resetCode string) error {
 	m.ctrl.T.Helper()
@@ -13,7 +13,7 @@ type CartRepository interface {
 	GetActiveCartByUserID(userID uuid.UUID) (*entity.Cart, error)
 	CreateCart(userID uuid.UUID) (*entity.Cart, error)
 	GetCartWithItems(cartID uuid.UUID) (*entity.Cart, error)
-	GetCartItem(cartID uuid.UUID, productID uuid.UUID) (*entity.CartItem, error)
+	GetCartItems(cartID uuid.UUID, productID uuid.UUID) ([]entity.CartItem, error)
 	GetCartItemByID(cartItemID uuid.UUID) (*entity.CartItem, error)
 	CreateCartItem(item *entity.CartItem) error
 	UpdateCartItemQuantity(cartItemID uuid.UUID, qty int) error
@@ -32,7 +32,7 @@ func NewCartRepository(db *gorm.DB) CartRepository {
 
 func (r *cartRepository) GetActiveCartByUserID(userID uuid.UUID) (*entity.Cart, error) {
 	var cart entity.Cart
-	err := r.db.Preload("Items").Where("user_id = ? AND status = ?", userID, "active").First(&cart).Error
+	err := r.db.Preload("Items.Modifiers.Option").Where("user_id = ? AND status = ?", userID, "active").First(&cart).Error
 	if err != nil {
 		return nil, err
 	}
@@ -53,20 +53,19 @@ func (r *cartRepository) CreateCart(userID uuid.UUID) (*entity.Cart, error) {
 
 func (r *cartRepository) GetCartWithItems(cartID uuid.UUID) (*entity.Cart, error) {
 	var cart entity.Cart
-	err := r.db.Preload("Items").Where("cart_id = ?", cartID).First(&cart).Error
+	err := r.db.Preload("Items.Modifiers.Option").Where("cart_id = ?", cartID).First(&cart).Error
 	if err != nil {
 		return nil, err
 	}
 	return &cart, nil
 }
 
-func (r *cartRepository) GetCartItem(cartID uuid.UUID, productID uuid.UUID) (*entity.CartItem, error) {
-	var item entity.CartItem
-	err := r.db.Where("cart_id = ? AND product_id = ?", cartID, productID).First(&item).Error
-	if err != nil {
-		return nil, err
-	}
-	return &item, nil
+// GetCartItems lists the items of a product in a cart, one per set of
+// modifiers chosen.
+func (r *cartRepository) GetCartItems(cartID uuid.UUID, productID uuid.UUID) ([]entity.CartItem, error) {
+	var items []entity.CartItem
+	err := r.db.Preload("Modifiers").Where("cart_id = ? AND product_id = ?", cartID, productID).Find(&items).Error
+	return items, err
 }
 
 func (r *cartRepository) GetCartItemByID(cartItemID uuid.UUID) (*entity.CartItem, error) {
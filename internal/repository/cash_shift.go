@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShiftRepository interface {
+	CreateShift(shift *entity.CashShift) error
+	FindOpenShift(cashierID uuid.UUID) (*entity.CashShift, error)
+	FindShiftByID(shiftID uuid.UUID) (*entity.CashShift, error)
+	FindShiftsOpenedBetween(startDate, endDate time.Time) ([]entity.CashShift, error)
+	GetShiftTenders(shiftID uuid.UUID) ([]entity.PaymentMethodStat, error)
+	CountShiftOrders(shiftID uuid.UUID) (int64, error)
+	FindCashierName(cashierID uuid.UUID) (string, error)
+}
+
+type shiftRepository struct {
+	db *gorm.DB
+}
+
+func NewShiftRepository(db *gorm.DB) ShiftRepository {
+	return &shiftRepository{db: db}
+}
+
+func (r *shiftRepository) CreateShift(shift *entity.CashShift) error {
+	if shift == nil {
+		return errors.New("shift is nil")
+	}
+	return r.db.Create(shift).Error
+}
+
+func (r *shiftRepository) FindOpenShift(cashierID uuid.UUID) (*entity.CashShift, error) {
+	var shift entity.CashShift
+	err := r.db.Preload("Movements", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("cashier_id = ? AND status = ?", cashierID, entity.ShiftOpen).First(&shift).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+func (r *shiftRepository) FindShiftByID(shiftID uuid.UUID) (*entity.CashShift, error) {
+	var shift entity.CashShift
+	err := r.db.Preload("Movements", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("shift_id = ?", shiftID).First(&shift).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+func (r *shiftRepository) FindShiftsOpenedBetween(startDate, endDate time.Time) ([]entity.CashShift, error) {
+	var shifts []entity.CashShift
+	err := r.db.Preload("Movements", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("opened_at >= ? AND opened_at < ?", startDate, endDate).
+		Order("opened_at ASC").
+		Find(&shifts).Error
+	return shifts, err
+}
+
+// GetShiftTenders totals the settled tenders of the orders taken during the
+// shift. Count is the number of orders that used the method.
+func (r *shiftRepository) GetShiftTenders(shiftID uuid.UUID) ([]entity.PaymentMethodStat, error) {
+	var stats []entity.PaymentMethodStat
+	err := r.db.Model(&entity.OrderPayment{}).
+		Joins("JOIN orders ON order_payments.order_id = orders.order_id").
+		Where("orders.shift_id = ? AND order_payments.status = ?", shiftID, entity.PaymentSettled).
+		Select("order_payments.method as payment_method, COALESCE(SUM(order_payments.amount), 0) as total_amount, COUNT(DISTINCT order_payments.order_id) as count").
+		Group("order_payments.method").
+		Order("payment_method").
+		Scan(&stats).Error
+	return stats, err
+}
+
+func (r *shiftRepository) CountShiftOrders(shiftID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Order{}).Where("shift_id = ?", shiftID).Count(&count).Error
+	return count, err
+}
+
+func (r *shiftRepository) FindCashierName(cashierID uuid.UUID) (string, error) {
+	var user entity.User
+	if err := r.db.Select("fullname").Where("user_id = ?", cashierID).First(&user).Error; err != nil {
+		return "", err
+	}
+	return user.Fullname, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CouponRepository interface {
+	CreateCouponCodes(codes []entity.CouponCode) error
+	FindExistingCodes(codes []string) ([]string, error)
+	FindCouponCodeByCode(code string) (*entity.CouponCode, error)
+	FindCouponCodesByPromotionID(promotionID uuid.UUID) ([]entity.CouponCode, error)
+	CountRedemptionsByUser(promotionID, userID uuid.UUID) (int64, error)
+	GetRedemptionReport(promotionID uuid.UUID) (*entity.CouponRedemptionReport, error)
+}
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponRepository(db *gorm.DB) CouponRepository {
+	return &couponRepository{db: db}
+}
+
+func (r *couponRepository) CreateCouponCodes(codes []entity.CouponCode) error {
+	return r.db.CreateInBatches(codes, 500).Error
+}
+
+func (r *couponRepository) FindExistingCodes(codes []string) ([]string, error) {
+	var existing []string
+	err := r.db.Model(&entity.CouponCode{}).Where("code IN ?", codes).Pluck("code", &existing).Error
+	return existing, err
+}
+
+func (r *couponRepository) FindCouponCodeByCode(code string) (*entity.CouponCode, error) {
+	var couponCode entity.CouponCode
+	err := r.db.Where("code = ?", code).First(&couponCode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &couponCode, nil
+}
+
+func (r *couponRepository) FindCouponCodesByPromotionID(promotionID uuid.UUID) ([]entity.CouponCode, error) {
+	var codes []entity.CouponCode
+	err := r.db.Where("promotion_id = ?", promotionID).Order("created_at ASC, code ASC").Find(&codes).Error
+	return codes, err
+}
+
+func (r *couponRepository) CountRedemptionsByUser(promotionID, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.CouponRedemption{}).
+		Where("promotion_id = ? AND user_id = ?", promotionID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *couponRepository) GetRedemptionReport(promotionID uuid.UUID) (*entity.CouponRedemptionReport, error) {
+	report := &entity.CouponRedemptionReport{
+		PromotionID: promotionID,
+		Codes:       make([]entity.CouponCodeStat, 0),
+	}
+
+	if err := r.db.Model(&entity.CouponCode{}).
+		Where("promotion_id = ?", promotionID).
+		Select("COUNT(*), COUNT(*) FILTER (WHERE redemption_count > 0)").
+		Row().
+		Scan(&report.TotalCodes, &report.RedeemedCodes); err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Model(&entity.CouponRedemption{}).
+		Where("promotion_id = ?", promotionID).
+		Select("COUNT(*), COUNT(DISTINCT user_id), COALESCE(SUM(discount_amount), 0)").
+		Row().
+		Scan(&report.TotalRedemptions, &report.UniqueCustomers, &report.TotalDiscount); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Model(&entity.CouponRedemption{}).
+		Joins("JOIN coupon_codes ON coupon_redemptions.coupon_code_id = coupon_codes.coupon_code_id").
+		Where("coupon_redemptions.promotion_id = ?", promotionID).
+		Select("coupon_codes.code, COUNT(*), COALESCE(SUM(coupon_redemptions.discount_amount), 0), MAX(coupon_redemptions.created_at)").
+		Group("coupon_codes.code").
+		Order("COUNT(*) DESC, coupon_codes.code ASC").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code string
+		var redemptions int64
+		var discount money.Amount
+		var lastRedeemedAt time.Time
+		if err := rows.Scan(&code, &redemptions, &discount, &lastRedeemedAt); err != nil {
+			return nil, err
+		}
+
+		report.Codes = append(report.Codes, entity.CouponCodeStat{
+			Code:           code,
+			Redemptions:    redemptions,
+			TotalDiscount:  discount,
+			LastRedeemedAt: &lastRedeemedAt,
+		})
+	}
+
+	return report, nil
+}
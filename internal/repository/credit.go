@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CreditRepository interface {
+	SaveAccount(account *entity.CreditAccount) error
+	FindAccount(userID uuid.UUID) (*entity.CreditAccount, error)
+	FindAllAccounts() ([]entity.CreditAccount, error)
+	FindOpenInvoices(userID uuid.UUID) ([]entity.CreditInvoice, error)
+	FindAllOpenInvoices() ([]entity.CreditInvoice, error)
+	FindPaymentsSince(userID uuid.UUID, since time.Time) ([]entity.CreditPayment, error)
+	FindUsersByIDs(userIDs []uuid.UUID) ([]entity.User, error)
+}
+
+type creditRepository struct {
+	db *gorm.DB
+}
+
+func NewCreditRepository(db *gorm.DB) CreditRepository {
+	return &creditRepository{db: db}
+}
+
+func (r *creditRepository) SaveAccount(account *entity.CreditAccount) error {
+	if account == nil {
+		return errors.New("credit account is nil")
+	}
+	return r.db.Save(account).Error
+}
+
+func (r *creditRepository) FindAccount(userID uuid.UUID) (*entity.CreditAccount, error) {
+	var account entity.CreditAccount
+	if err := r.db.Where("user_id = ?", userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *creditRepository) FindAllAccounts() ([]entity.CreditAccount, error) {
+	var accounts []entity.CreditAccount
+	err := r.db.Order("balance DESC").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *creditRepository) FindOpenInvoices(userID uuid.UUID) ([]entity.CreditInvoice, error) {
+	var invoices []entity.CreditInvoice
+	err := r.db.Where("user_id = ? AND status = ?", userID, entity.InvoiceOpen).
+		Order("issued_at ASC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+func (r *creditRepository) FindAllOpenInvoices() ([]entity.CreditInvoice, error) {
+	var invoices []entity.CreditInvoice
+	err := r.db.Where("status = ?", entity.InvoiceOpen).
+		Order("user_id, issued_at ASC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+func (r *creditRepository) FindPaymentsSince(userID uuid.UUID, since time.Time) ([]entity.CreditPayment, error) {
+	var payments []entity.CreditPayment
+	err := r.db.Preload("Allocations").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Order("created_at ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+func (r *creditRepository) FindUsersByIDs(userIDs []uuid.UUID) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.Where("user_id IN ?", userIDs).Find(&users).Error
+	return users, err
+}
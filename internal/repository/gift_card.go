@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type GiftCardRepository interface {
+	CreateGiftCard(giftCard *entity.GiftCard) error
+	FindAllGiftCards() ([]entity.GiftCard, error)
+	FindGiftCardByID(giftCardID uuid.UUID) (*entity.GiftCard, error)
+	FindGiftCardByCode(code string) (*entity.GiftCard, error)
+}
+
+type giftCardRepository struct {
+	db *gorm.DB
+}
+
+func NewGiftCardRepository(db *gorm.DB) GiftCardRepository {
+	return &giftCardRepository{db: db}
+}
+
+func (r *giftCardRepository) CreateGiftCard(giftCard *entity.GiftCard) error {
+	if giftCard == nil {
+		return errors.New("gift card is nil")
+	}
+	return r.db.Create(giftCard).Error
+}
+
+func (r *giftCardRepository) FindAllGiftCards() ([]entity.GiftCard, error) {
+	var giftCards []entity.GiftCard
+	err := r.db.Order("created_at DESC").Find(&giftCards).Error
+	return giftCards, err
+}
+
+func (r *giftCardRepository) FindGiftCardByID(giftCardID uuid.UUID) (*entity.GiftCard, error) {
+	var giftCard entity.GiftCard
+	err := r.db.Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("gift_card_id = ?", giftCardID).First(&giftCard).Error
+	if err != nil {
+		return nil, err
+	}
+	return &giftCard, nil
+}
+
+func (r *giftCardRepository) FindGiftCardByCode(code string) (*entity.GiftCard, error) {
+	var giftCard entity.GiftCard
+	err := r.db.Where("code = ?", code).First(&giftCard).Error
+	if err != nil {
+		return nil, err
+	}
+	return &giftCard, nil
+}
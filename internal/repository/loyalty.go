@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoyaltyRepository interface {
+	FindAccountByUserID(userID uuid.UUID) (*entity.LoyaltyAccount, error)
+	FindTransactionsByUserID(userID uuid.UUID) ([]entity.LoyaltyTransaction, error)
+}
+
+type loyaltyRepository struct {
+	db *gorm.DB
+}
+
+func NewLoyaltyRepository(db *gorm.DB) LoyaltyRepository {
+	return &loyaltyRepository{db: db}
+}
+
+func (r *loyaltyRepository) FindAccountByUserID(userID uuid.UUID) (*entity.LoyaltyAccount, error) {
+	var account entity.LoyaltyAccount
+	err := r.db.Where("user_id = ?", userID).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *loyaltyRepository) FindTransactionsByUserID(userID uuid.UUID) ([]entity.LoyaltyTransaction, error) {
+	var transactions []entity.LoyaltyTransaction
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&transactions).Error
+	return transactions, err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MembershipRepository interface {
+	CreateTier(tier *entity.MembershipTier) error
+	UpdateTier(tier *entity.MembershipTier) error
+	DeleteTier(tierID uuid.UUID) error
+	FindAllTiers() ([]entity.MembershipTier, error)
+	FindTierByID(tierID uuid.UUID) (*entity.MembershipTier, error)
+	FindMembershipByUserID(userID uuid.UUID) (*entity.UserMembership, error)
+	FindAllMemberships() ([]entity.UserMembership, error)
+	SumSpendByUser(since time.Time) (map[uuid.UUID]money.Amount, error)
+	SaveMembership(membership *entity.UserMembership) error
+	FindUsersByIDs(userIDs []uuid.UUID) ([]entity.User, error)
+}
+
+type membershipRepository struct {
+	db *gorm.DB
+}
+
+func NewMembershipRepository(db *gorm.DB) MembershipRepository {
+	return &membershipRepository{db: db}
+}
+
+func (r *membershipRepository) CreateTier(tier *entity.MembershipTier) error {
+	if tier == nil {
+		return errors.New("tier is nil")
+	}
+	return r.db.Create(tier).Error
+}
+
+func (r *membershipRepository) UpdateTier(tier *entity.MembershipTier) error {
+	if tier == nil {
+		return errors.New("tier is nil")
+	}
+	return r.db.Model(&entity.MembershipTier{}).
+		Where("tier_id = ?", tier.TierID).
+		Select("name", "min_spend", "discount_rate", "updated_at").
+		Updates(tier).Error
+}
+
+func (r *membershipRepository) DeleteTier(tierID uuid.UUID) error {
+	return r.db.Where("tier_id = ?", tierID).Delete(&entity.MembershipTier{}).Error
+}
+
+func (r *membershipRepository) FindAllTiers() ([]entity.MembershipTier, error) {
+	var tiers []entity.MembershipTier
+	err := r.db.Order("min_spend ASC").Find(&tiers).Error
+	return tiers, err
+}
+
+func (r *membershipRepository) FindTierByID(tierID uuid.UUID) (*entity.MembershipTier, error) {
+	var tier entity.MembershipTier
+	err := r.db.Where("tier_id = ?", tierID).First(&tier).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+func (r *membershipRepository) FindMembershipByUserID(userID uuid.UUID) (*entity.UserMembership, error) {
+	var membership entity.UserMembership
+	err := r.db.Preload("Tier").Where("user_id = ?", userID).First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+func (r *membershipRepository) FindAllMemberships() ([]entity.UserMembership, error) {
+	var memberships []entity.UserMembership
+	err := r.db.Find(&memberships).Error
+	return memberships, err
+}
+
+// SumSpendByUser adds up what each customer spent on paid orders since the
+// given time. Cancelled, refunded and unpaid orders do not count, and neither
+// do tips or gift cards bought, which only count once they are spent.
+func (r *membershipRepository) SumSpendByUser(since time.Time) (map[uuid.UUID]money.Amount, error) {
+	rows, err := r.db.Model(&entity.Order{}).
+		Where("status IN ? AND created_at >= ?", []string{"paid", "shipped", "delivered"}, since).
+		Select("user_id, COALESCE(SUM(total_price - tip_amount - (SELECT COALESCE(SUM(order_items.total_price), 0) FROM order_items WHERE order_items.order_id = orders.order_id AND order_items.item_type = ?)), 0)", entity.OrderItemGiftCard).
+		Group("user_id").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spend := make(map[uuid.UUID]money.Amount)
+	for rows.Next() {
+		var userID uuid.UUID
+		var total money.Amount
+		if err := rows.Scan(&userID, &total); err != nil {
+			return nil, err
+		}
+		spend[userID] = total
+	}
+	return spend, rows.Err()
+}
+
+func (r *membershipRepository) SaveMembership(membership *entity.UserMembership) error {
+	return r.db.Omit("Tier").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tier_id", "qualifying_spend", "evaluated_at"}),
+	}).Create(membership).Error
+}
+
+func (r *membershipRepository) FindUsersByIDs(userIDs []uuid.UUID) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.Where("user_id IN ?", userIDs).Find(&users).Error
+	return users, err
+}
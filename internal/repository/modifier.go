@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ModifierRepository interface {
+	CreateGroup(group *entity.ModifierGroup) error
+	SaveGroup(group *entity.ModifierGroup) error
+	FindGroupByID(groupID uuid.UUID) (*entity.ModifierGroup, error)
+	FindAllGroups() ([]entity.ModifierGroup, error)
+	CreateOption(option *entity.ModifierOption) error
+	SaveOption(option *entity.ModifierOption) error
+	FindOptionByID(optionID uuid.UUID) (*entity.ModifierOption, error)
+	SetProductGroups(productID uuid.UUID, links []entity.ProductModifierGroup) error
+	FindProductGroups(productID uuid.UUID) ([]entity.ModifierGroup, error)
+}
+
+type modifierRepository struct {
+	db *gorm.DB
+}
+
+func NewModifierRepository(db *gorm.DB) ModifierRepository {
+	return &modifierRepository{db: db}
+}
+
+func (r *modifierRepository) CreateGroup(group *entity.ModifierGroup) error {
+	if group == nil {
+		return errors.New("modifier group is nil")
+	}
+	return r.db.Create(group).Error
+}
+
+func (r *modifierRepository) SaveGroup(group *entity.ModifierGroup) error {
+	if group == nil {
+		return errors.New("modifier group is nil")
+	}
+	return r.db.Omit("Options").Save(group).Error
+}
+
+func (r *modifierRepository) FindGroupByID(groupID uuid.UUID) (*entity.ModifierGroup, error) {
+	var group entity.ModifierGroup
+	err := r.db.Preload("Options", orderOptions).Where("modifier_group_id = ?", groupID).First(&group).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *modifierRepository) FindAllGroups() ([]entity.ModifierGroup, error) {
+	var groups []entity.ModifierGroup
+	err := r.db.Preload("Options", orderOptions).Order("name").Find(&groups).Error
+	return groups, err
+}
+
+func (r *modifierRepository) CreateOption(option *entity.ModifierOption) error {
+	if option == nil {
+		return errors.New("modifier option is nil")
+	}
+	return r.db.Create(option).Error
+}
+
+func (r *modifierRepository) SaveOption(option *entity.ModifierOption) error {
+	if option == nil {
+		return errors.New("modifier option is nil")
+	}
+	return r.db.Save(option).Error
+}
+
+func (r *modifierRepository) FindOptionByID(optionID uuid.UUID) (*entity.ModifierOption, error) {
+	var option entity.ModifierOption
+	if err := r.db.Where("modifier_option_id = ?", optionID).First(&option).Error; err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+// SetProductGroups replaces the groups attached to a product.
+func (r *modifierRepository) SetProductGroups(productID uuid.UUID, links []entity.ProductModifierGroup) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&entity.ProductModifierGroup{}).Error; err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+// FindProductGroups lists the groups of a product in their sort order, with
+// every option including inactive ones.
+func (r *modifierRepository) FindProductGroups(productID uuid.UUID) ([]entity.ModifierGroup, error) {
+	var groups []entity.ModifierGroup
+	err := r.db.Preload("Options", orderOptions).
+		Joins("JOIN product_modifier_groups ON product_modifier_groups.modifier_group_id = modifier_groups.modifier_group_id").
+		Where("product_modifier_groups.product_id = ?", productID).
+		Order("product_modifier_groups.sort_order, modifier_groups.name").
+		Find(&groups).Error
+	return groups, err
+}
+
+func orderOptions(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, name")
+}
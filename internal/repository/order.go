@@ -14,6 +14,7 @@ type OrderRepository interface {
 	GetProductByID(productID string) (*entity.Products, error)
 	UpdateOrderStatus(orderID uuid.UUID, status string) error
 	GetOrderHistoryByUserID(userID string) ([]entity.Order, error)
+	FindOrderQueue() ([]entity.Order, error)
 }
 
 type orderRepository struct {
@@ -48,8 +49,21 @@ func (r *orderRepository) UpdateOrderStatus(orderID uuid.UUID, status string) er
 		Update("status", status).Error
 }
 
+// GetOrderHistoryByUserID leaves out the gift cards of the lines, as their
+// codes can be spent by anyone who reads them.
 func (r *orderRepository) GetOrderHistoryByUserID(userID string) ([]entity.Order, error) {
 	var orders []entity.Order
-	err := r.db.Preload("OrderItems").Where("user_id = ?", userID).Order("created_at DESC").Find(&orders).Error
+	err := r.db.Preload("OrderItems").Preload("OrderItems.Modifiers").Preload("Promotions").Preload("Payments").Where("user_id = ?", userID).Order("created_at DESC").Find(&orders).Error
+	return orders, err
+}
+
+// FindOrderQueue lists the pending and paid orders that are not served yet,
+// oldest first.
+func (r *orderRepository) FindOrderQueue() ([]entity.Order, error) {
+	var orders []entity.Order
+	err := r.db.Preload("OrderItems", "item_type = ?", entity.OrderItemProduct).Preload("OrderItems.Modifiers").
+		Where("fulfilment_status <> ? AND status IN ?", entity.FulfilmentServed, []string{"pending", "paid"}).
+		Order("created_at ASC").
+		Find(&orders).Error
 	return orders, err
 }
@@ -45,7 +45,7 @@ func (r *productRepository) UpdateProduct(product *entity.Products) (*entity.Pro
 	if product.Name != "" {
 		fields["name"] = product.Name
 	}
-	if product.Price != 0 {
+	if !product.Price.IsZero() {
 		fields["price"] = product.Price
 	}
 	if product.PhotoURL != "" {
@@ -57,6 +57,12 @@ func (r *productRepository) UpdateProduct(product *entity.Products) (*entity.Pro
 	if product.Stock != 0 {
 		fields["stock"] = product.Stock
 	}
+	if product.Category != "" {
+		fields["category"] = product.Category
+	}
+	if product.TaxClassID != nil {
+		fields["tax_class_id"] = product.TaxClassID
+	}
 
 	if err := r.db.Model(product).Where("product_id = ?", product.ProductID).Updates(fields).Error; err != nil {
 		return product, err
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PromotionRepository interface {
+	CreatePromotion(promotion *entity.Promotion) error
+	UpdatePromotion(promotion *entity.Promotion) error
+	DeletePromotion(promotionID uuid.UUID) error
+	FindAllPromotions() ([]entity.Promotion, error)
+	FindPromotionByID(promotionID uuid.UUID) (*entity.Promotion, error)
+	FindAvailablePromotions(at time.Time) ([]entity.Promotion, error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) CreatePromotion(promotion *entity.Promotion) error {
+	if promotion == nil {
+		return errors.New("promotion is nil")
+	}
+	return r.db.Create(promotion).Error
+}
+
+// UpdatePromotion saves every editable column, including zero values such
+// as active=false and a cleared ends_at. usage_count is left untouched.
+func (r *promotionRepository) UpdatePromotion(promotion *entity.Promotion) error {
+	if promotion == nil {
+		return errors.New("promotion is nil")
+	}
+	return r.db.Model(&entity.Promotion{}).
+		Where("promotion_id = ?", promotion.PromotionID).
+		Select("name", "type", "percentage", "amount", "product_id", "category", "buy_quantity", "get_quantity",
+			"min_spend", "starts_at", "ends_at", "usage_limit", "stackable", "requires_code", "per_customer_limit", "priority", "active", "updated_at").
+		Updates(promotion).Error
+}
+
+func (r *promotionRepository) DeletePromotion(promotionID uuid.UUID) error {
+	return r.db.Where("promotion_id = ?", promotionID).Delete(&entity.Promotion{}).Error
+}
+
+func (r *promotionRepository) FindAllPromotions() ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	err := r.db.Order("starts_at DESC").Find(&promotions).Error
+	return promotions, err
+}
+
+func (r *promotionRepository) FindPromotionByID(promotionID uuid.UUID) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	err := r.db.Where("promotion_id = ?", promotionID).First(&promotion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// FindAvailablePromotions returns the automatic promotions that can apply at
+// the given time. Coupon campaigns are left out.
+func (r *promotionRepository) FindAvailablePromotions(at time.Time) ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	err := r.db.Where("requires_code = ? AND active = ? AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", false, true, at, at).
+		Where("usage_limit IS NULL OR usage_count < usage_limit").
+		Order("priority DESC").
+		Find(&promotions).Error
+	return promotions, err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
 
@@ -14,7 +15,9 @@ type ReceiptRepository interface {
 	GetReceiptByID(receiptID uuid.UUID) (*entity.Receipt, error)
 	GetReceiptByOrderID(orderID uuid.UUID) (*entity.Receipt, error)
 	GetReceiptsByUserID(userID uuid.UUID) ([]entity.Receipt, error)
-	GetLastReceiptNumber() (string, error)
+	GetReceiptByNumber(receiptNumber string) (*entity.Receipt, error)
+	FindVoids(from, to time.Time) ([]entity.ReceiptVoidEntry, error)
+	FindReprints(from, to time.Time) ([]entity.ReceiptReprintEntry, error)
 }
 
 type receiptRepository struct {
@@ -34,7 +37,7 @@ func (r *receiptRepository) CreateReceipt(receipt *entity.Receipt) error {
 
 func (r *receiptRepository) GetReceiptByID(receiptID uuid.UUID) (*entity.Receipt, error) {
 	var receipt entity.Receipt
-	err := r.db.Preload("ReceiptItems").Where("receipt_id = ?", receiptID).First(&receipt).Error
+	err := r.db.Preload("ReceiptItems.Modifiers").Preload("Payments").Where("receipt_id = ?", receiptID).First(&receipt).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +46,7 @@ func (r *receiptRepository) GetReceiptByID(receiptID uuid.UUID) (*entity.Receipt
 
 func (r *receiptRepository) GetReceiptByOrderID(orderID uuid.UUID) (*entity.Receipt, error) {
 	var receipt entity.Receipt
-	err := r.db.Preload("ReceiptItems").Where("order_id = ?", orderID).First(&receipt).Error
+	err := r.db.Preload("ReceiptItems.Modifiers").Preload("Payments").Where("order_id = ?", orderID).Order("created_at DESC").First(&receipt).Error
 	if err != nil {
 		return nil, err
 	}
@@ -52,21 +55,44 @@ func (r *receiptRepository) GetReceiptByOrderID(orderID uuid.UUID) (*entity.Rece
 
 func (r *receiptRepository) GetReceiptsByUserID(userID uuid.UUID) ([]entity.Receipt, error) {
 	var receipts []entity.Receipt
-	err := r.db.Preload("ReceiptItems").Where("user_id = ?", userID).Order("created_at DESC").Find(&receipts).Error
+	err := r.db.Preload("ReceiptItems.Modifiers").Preload("Payments").Where("user_id = ?", userID).Order("created_at DESC").Find(&receipts).Error
 	if err != nil {
 		return nil, err
 	}
 	return receipts, nil
 }
 
-func (r *receiptRepository) GetLastReceiptNumber() (string, error) {
+func (r *receiptRepository) GetReceiptByNumber(receiptNumber string) (*entity.Receipt, error) {
 	var receipt entity.Receipt
-	err := r.db.Order("created_at DESC").First(&receipt).Error
+	err := r.db.Preload("ReceiptItems").Where("receipt_number = ?", receiptNumber).First(&receipt).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil
-		}
-		return "", err
+		return nil, err
 	}
-	return receipt.ReceiptNumber, nil
+	return &receipt, nil
+}
+
+// FindVoids lists the receipts voided in [from, to), oldest first.
+func (r *receiptRepository) FindVoids(from, to time.Time) ([]entity.ReceiptVoidEntry, error) {
+	var voids []entity.ReceiptVoidEntry
+	err := r.db.Model(&entity.Receipt{}).
+		Joins("LEFT JOIN users ON receipts.voided_by = users.user_id").
+		Where("receipts.voided_at >= ? AND receipts.voided_at < ?", from, to).
+		Select("receipts.receipt_id, receipts.receipt_number, COALESCE(receipts.cashier_name, '') as cashier_name, receipts.total_amount, receipts.void_reason, COALESCE(users.fullname, '') as voided_by_name, receipts.voided_at").
+		Order("receipts.voided_at ASC").
+		Scan(&voids).Error
+	return voids, err
+}
+
+// FindReprints lists the reprints made in [from, to), oldest first. The
+// original print of a receipt is left out.
+func (r *receiptRepository) FindReprints(from, to time.Time) ([]entity.ReceiptReprintEntry, error) {
+	var reprints []entity.ReceiptReprintEntry
+	err := r.db.Model(&entity.ReceiptPrint{}).
+		Joins("JOIN receipts ON receipt_prints.receipt_id = receipts.receipt_id").
+		Joins("LEFT JOIN users ON receipt_prints.printed_by = users.user_id").
+		Where("receipt_prints.print_number > 0 AND receipt_prints.created_at >= ? AND receipt_prints.created_at < ?", from, to).
+		Select("receipt_prints.receipt_id, receipts.receipt_number, receipt_prints.print_number, receipt_prints.format, COALESCE(users.fullname, '') as printed_by_name, receipt_prints.created_at as printed_at").
+		Order("receipt_prints.created_at ASC").
+		Scan(&reprints).Error
+	return reprints, err
 }
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportSubscriptionRepository interface {
+	CreateSubscription(subscription *entity.ReportSubscription) error
+	UpdateSubscription(subscription *entity.ReportSubscription) error
+	DeleteSubscription(subscriptionID uuid.UUID) error
+	FindAllSubscriptions() ([]entity.ReportSubscription, error)
+	FindSubscriptionByID(subscriptionID uuid.UUID) (*entity.ReportSubscription, error)
+	FindActiveSubscriptions() ([]entity.ReportSubscription, error)
+	CreateDelivery(delivery *entity.ReportDelivery) error
+	FindDueDeliveries(at time.Time) ([]entity.ReportDelivery, error)
+	ClaimDelivery(delivery *entity.ReportDelivery, until time.Time) (bool, error)
+	UpdateDelivery(delivery *entity.ReportDelivery) error
+	FindDeliveries(subscriptionID uuid.UUID, limit int) ([]entity.ReportDelivery, error)
+}
+
+type reportSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewReportSubscriptionRepository(db *gorm.DB) ReportSubscriptionRepository {
+	return &reportSubscriptionRepository{db: db}
+}
+
+func (r *reportSubscriptionRepository) CreateSubscription(subscription *entity.ReportSubscription) error {
+	if subscription == nil {
+		return errors.New("report subscription is nil")
+	}
+	return r.db.Create(subscription).Error
+}
+
+// UpdateSubscription saves every editable column, including active=false.
+func (r *reportSubscriptionRepository) UpdateSubscription(subscription *entity.ReportSubscription) error {
+	if subscription == nil {
+		return errors.New("report subscription is nil")
+	}
+	return r.db.Model(&entity.ReportSubscription{}).
+		Where("subscription_id = ?", subscription.SubscriptionID).
+		Select("report_type", "send_hour", "recipients", "format", "active", "updated_at").
+		Updates(subscription).Error
+}
+
+func (r *reportSubscriptionRepository) DeleteSubscription(subscriptionID uuid.UUID) error {
+	return r.db.Where("subscription_id = ?", subscriptionID).Delete(&entity.ReportSubscription{}).Error
+}
+
+func (r *reportSubscriptionRepository) FindAllSubscriptions() ([]entity.ReportSubscription, error) {
+	var subscriptions []entity.ReportSubscription
+	err := r.db.Order("created_at ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *reportSubscriptionRepository) FindSubscriptionByID(subscriptionID uuid.UUID) (*entity.ReportSubscription, error) {
+	var subscription entity.ReportSubscription
+	err := r.db.Where("subscription_id = ?", subscriptionID).First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *reportSubscriptionRepository) FindActiveSubscriptions() ([]entity.ReportSubscription, error) {
+	var subscriptions []entity.ReportSubscription
+	err := r.db.Where("active = ?", true).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// CreateDelivery adds the delivery unless the subscription already has one
+// for the period, so every process running the scheduler can call it.
+func (r *reportSubscriptionRepository) CreateDelivery(delivery *entity.ReportDelivery) error {
+	if delivery == nil {
+		return errors.New("report delivery is nil")
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "period_start"}},
+		DoNothing: true,
+	}).Create(delivery).Error
+}
+
+// FindDueDeliveries returns the pending deliveries whose next attempt is due.
+func (r *reportSubscriptionRepository) FindDueDeliveries(at time.Time) ([]entity.ReportDelivery, error) {
+	var deliveries []entity.ReportDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", entity.ReportDeliveryPending, at).
+		Order("next_attempt_at ASC").
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery moves the next attempt of a due delivery to until, so no
+// other process picks it up while it is being sent. It reports false when
+// another process claimed it first.
+func (r *reportSubscriptionRepository) ClaimDelivery(delivery *entity.ReportDelivery, until time.Time) (bool, error) {
+	result := r.db.Model(&entity.ReportDelivery{}).
+		Where("delivery_id = ? AND status = ? AND next_attempt_at = ?", delivery.DeliveryID, entity.ReportDeliveryPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	delivery.NextAttemptAt = &until
+	return true, nil
+}
+
+func (r *reportSubscriptionRepository) UpdateDelivery(delivery *entity.ReportDelivery) error {
+	if delivery == nil {
+		return errors.New("report delivery is nil")
+	}
+	return r.db.Model(&entity.ReportDelivery{}).
+		Where("delivery_id = ?", delivery.DeliveryID).
+		Select("status", "attempts", "last_error", "next_attempt_at", "sent_at", "updated_at").
+		Updates(delivery).Error
+}
+
+// FindDeliveries returns the latest deliveries of a subscription, newest
+// period first.
+func (r *reportSubscriptionRepository) FindDeliveries(subscriptionID uuid.UUID, limit int) ([]entity.ReportDelivery, error) {
+	var deliveries []entity.ReportDelivery
+	err := r.db.Where("subscription_id = ?", subscriptionID).
+		Order("period_start DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
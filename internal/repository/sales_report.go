@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -11,10 +12,10 @@ import (
 
 type SalesReportRepository interface {
 	GetSalesReportByDateRange(startDate, endDate time.Time) (map[string]interface{}, error)
-	GetDailySalesReport(date time.Time) (map[string]interface{}, error)
-	GetMonthlySalesReport(year int, month time.Month) (map[string]interface{}, error)
 	GetPaymentMethodBreakdown(startDate, endDate time.Time) ([]entity.PaymentMethodStat, error)
 	GetTopProducts(startDate, endDate time.Time, limit int) ([]entity.TopProductStat, error)
+	GetTipsByCashier(startDate, endDate time.Time) ([]entity.CashierTipStat, error)
+	StreamOrderLines(startDate, endDate time.Time, fn func(entity.SalesOrderLine) error) error
 }
 
 type salesReportRepository struct {
@@ -25,75 +26,99 @@ func NewSalesReportRepository(db *gorm.DB) SalesReportRepository {
 	return &salesReportRepository{db: db}
 }
 
+// GetSalesReportByDateRange totals the paid orders created from startDate up
+// to, but not including, endDate. The other queries use the same period.
 func (r *salesReportRepository) GetSalesReportByDateRange(startDate, endDate time.Time) (map[string]interface{}, error) {
 	var result map[string]interface{}
 
 	// Get basic sales metrics
-	var totalSales float64
+	var totalSales money.Amount
+	var totalTax money.Amount
+	var totalDiscount money.Amount
+	var grossSales money.Amount
 	var totalTransactions int64
 	var totalCustomers int64
-	var cashAmount float64
-	var midtransAmount float64
+	var cashAmount money.Amount
+	var midtransAmount money.Amount
+	var giftCardSales money.Amount
+	var giftCardRedeemed money.Amount
+	var giftCardLiability money.Amount
+	var serviceChargeAmount money.Amount
+	var tipAmount money.Amount
 
+	// Tips are passed on to the cashier, so they are left out of total sales
 	r.db.Model(&entity.Order{}).
-		Where("created_at BETWEEN ? AND ? AND status = ?", startDate, endDate, "paid").
-		Select("SUM(total_price) as total, COUNT(DISTINCT order_id) as count, COUNT(DISTINCT user_id) as users").
+		Where("created_at >= ? AND created_at < ? AND status = ?", startDate, endDate, "paid").
+		Select("COALESCE(SUM(total_price - tip_amount), 0) as total, COALESCE(SUM(tax_amount), 0) as tax, COALESCE(SUM(discount_amount), 0) as discount, COUNT(DISTINCT order_id) as count, COUNT(DISTINCT user_id) as users, COALESCE(SUM(service_charge_amount), 0) as service_charge, COALESCE(SUM(tip_amount), 0) as tips").
 		Row().
-		Scan(&totalSales, &totalTransactions, &totalCustomers)
+		Scan(&totalSales, &totalTax, &totalDiscount, &totalTransactions, &totalCustomers, &serviceChargeAmount, &tipAmount)
 
-	// Get cash vs midtrans breakdown
-	r.db.Model(&entity.Order{}).
-		Where("created_at BETWEEN ? AND ? AND status = ? AND payment_method = ?", startDate, endDate, "paid", "cash").
-		Select("COALESCE(SUM(total_price), 0)").Row().Scan(&cashAmount)
-
-	r.db.Model(&entity.Order{}).
-		Where("created_at BETWEEN ? AND ? AND status = ? AND payment_method = ?", startDate, endDate, "paid", "midtrans").
-		Select("COALESCE(SUM(total_price), 0)").Row().Scan(&midtransAmount)
+	// Gross sales are the product line totals before promotion discounts
+	r.db.Model(&entity.OrderItem{}).
+		Joins("JOIN orders ON order_items.order_id = orders.order_id").
+		Where("orders.created_at >= ? AND orders.created_at < ? AND orders.status = ? AND order_items.item_type = ?", startDate, endDate, "paid", entity.OrderItemProduct).
+		Select("COALESCE(SUM(order_items.total_price), 0)").Row().Scan(&grossSales)
 
-	result = map[string]interface{}{
-		"total_sales":        totalSales,
-		"total_transactions": totalTransactions,
-		"total_customers":    totalCustomers,
-		"cash_amount":        cashAmount,
-		"midtrans_amount":    midtransAmount,
-		"period_start_date":  startDate,
-		"period_end_date":    endDate,
-	}
+	// Gift cards sold are stored value, not revenue, so they are reported apart
+	// and taken out of total sales. They become sales when they are redeemed.
+	r.db.Model(&entity.OrderItem{}).
+		Joins("JOIN orders ON order_items.order_id = orders.order_id").
+		Where("orders.created_at >= ? AND orders.created_at < ? AND orders.status = ? AND order_items.item_type = ?", startDate, endDate, "paid", entity.OrderItemGiftCard).
+		Select("COALESCE(SUM(order_items.total_price), 0)").Row().Scan(&giftCardSales)
+	totalSales = totalSales.Sub(giftCardSales)
 
-	if totalTransactions > 0 {
-		result["average_transaction_value"] = totalSales / float64(totalTransactions)
-		result["total_tax"] = totalSales * 0.10
-	}
+	r.db.Model(&entity.Order{}).
+		Where("created_at >= ? AND created_at < ? AND status = ?", startDate, endDate, "paid").
+		Select("COALESCE(SUM(gift_card_amount), 0)").Row().Scan(&giftCardRedeemed)
 
-	return result, nil
-}
+	// Outstanding liability is the ledger balance of unexpired cards at the end of the period
+	r.db.Model(&entity.GiftCardTransaction{}).
+		Joins("JOIN gift_cards ON gift_card_transactions.gift_card_id = gift_cards.gift_card_id").
+		Where("gift_card_transactions.created_at < ? AND (gift_cards.expires_at IS NULL OR gift_cards.expires_at > ?)", endDate, endDate).
+		Select("COALESCE(SUM(gift_card_transactions.amount), 0)").Row().Scan(&giftCardLiability)
 
-func (r *salesReportRepository) GetDailySalesReport(date time.Time) (map[string]interface{}, error) {
-	startDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endDate := startDate.Add(24 * time.Hour)
+	// Get cash vs midtrans breakdown from the tenders, so split payments count per method
+	r.paidTenders(startDate, endDate).
+		Where("order_payments.method = ?", entity.PaymentCash).
+		Select("COALESCE(SUM(order_payments.amount), 0)").Row().Scan(&cashAmount)
 
-	return r.GetSalesReportByDateRange(startDate, endDate)
-}
+	r.paidTenders(startDate, endDate).
+		Where("order_payments.method = ?", entity.PaymentMidtrans).
+		Select("COALESCE(SUM(order_payments.amount), 0)").Row().Scan(&midtransAmount)
 
-func (r *salesReportRepository) GetMonthlySalesReport(year int, month time.Month) (map[string]interface{}, error) {
-	startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0)
+	result = map[string]interface{}{
+		"gross_sales":               grossSales,
+		"total_discount":            totalDiscount,
+		"total_sales":               totalSales,
+		"total_tax":                 totalTax,
+		"total_transactions":        totalTransactions,
+		"total_customers":           totalCustomers,
+		"cash_amount":               cashAmount,
+		"midtrans_amount":           midtransAmount,
+		"gift_card_sales":           giftCardSales,
+		"gift_card_redeemed":        giftCardRedeemed,
+		"gift_card_liability":       giftCardLiability,
+		"service_charge_amount":     serviceChargeAmount,
+		"tip_amount":                tipAmount,
+		"period_start_date":         startDate,
+		"period_end_date":           endDate,
+		"average_transaction_value": totalSales.Div(totalTransactions),
+	}
 
-	return r.GetSalesReportByDateRange(startDate, endDate)
+	return result, nil
 }
 
 func (r *salesReportRepository) GetPaymentMethodBreakdown(startDate, endDate time.Time) ([]entity.PaymentMethodStat, error) {
 	var stats []entity.PaymentMethodStat
 
-	var totalSales float64
-	r.db.Model(&entity.Order{}).
-		Where("created_at BETWEEN ? AND ? AND status = ?", startDate, endDate, "paid").
-		Select("COALESCE(SUM(total_price), 0)").Row().Scan(&totalSales)
+	var totalSales money.Amount
+	r.paidTenders(startDate, endDate).
+		Select("COALESCE(SUM(order_payments.amount), 0)").Row().Scan(&totalSales)
 
-	rows, err := r.db.Model(&entity.Order{}).
-		Where("created_at BETWEEN ? AND ? AND status = ?", startDate, endDate, "paid").
-		Select("payment_method, COALESCE(SUM(total_price), 0) as total_amount, COUNT(*) as count").
-		Group("payment_method").
+	// Count is the number of orders that used the method
+	rows, err := r.paidTenders(startDate, endDate).
+		Select("order_payments.method, COALESCE(SUM(order_payments.amount), 0) as total_amount, COUNT(DISTINCT order_payments.order_id) as count").
+		Group("order_payments.method").
 		Rows()
 	if err != nil {
 		return nil, err
@@ -102,13 +127,13 @@ func (r *salesReportRepository) GetPaymentMethodBreakdown(startDate, endDate tim
 
 	for rows.Next() {
 		var paymentMethod string
-		var totalAmount float64
+		var totalAmount money.Amount
 		var count int64
 		rows.Scan(&paymentMethod, &totalAmount, &count)
 
 		percentage := 0.0
-		if totalSales > 0 {
-			percentage = (totalAmount / totalSales) * 100
+		if totalSales.IsPositive() {
+			percentage = float64(totalAmount.Sen()) / float64(totalSales.Sen()) * 100
 		}
 
 		stats = append(stats, entity.PaymentMethodStat{
@@ -122,13 +147,20 @@ func (r *salesReportRepository) GetPaymentMethodBreakdown(startDate, endDate tim
 	return stats, nil
 }
 
+// paidTenders are the settled tenders of paid orders in the period.
+func (r *salesReportRepository) paidTenders(startDate, endDate time.Time) *gorm.DB {
+	return r.db.Model(&entity.OrderPayment{}).
+		Joins("JOIN orders ON order_payments.order_id = orders.order_id").
+		Where("orders.created_at >= ? AND orders.created_at < ? AND orders.status = ? AND order_payments.status = ?", startDate, endDate, "paid", entity.PaymentSettled)
+}
+
 func (r *salesReportRepository) GetTopProducts(startDate, endDate time.Time, limit int) ([]entity.TopProductStat, error) {
 	var topProducts []entity.TopProductStat
 
 	rows, err := r.db.Model(&entity.OrderItem{}).
 		Joins("JOIN orders ON order_items.order_id = orders.order_id").
 		Joins("JOIN products ON order_items.product_id = products.product_id").
-		Where("orders.created_at BETWEEN ? AND ? AND orders.status = ?", startDate, endDate, "paid").
+		Where("orders.created_at >= ? AND orders.created_at < ? AND orders.status = ?", startDate, endDate, "paid").
 		Select("order_items.product_id, products.name, SUM(order_items.quantity) as qty, SUM(order_items.total_price) as revenue").
 		Group("order_items.product_id, products.name").
 		Order("SUM(order_items.quantity) DESC").
@@ -143,7 +175,7 @@ func (r *salesReportRepository) GetTopProducts(startDate, endDate time.Time, lim
 		var productID uuid.UUID
 		var productName string
 		var qtySold int
-		var revenue float64
+		var revenue money.Amount
 		rows.Scan(&productID, &productName, &qtySold, &revenue)
 
 		topProducts = append(topProducts, entity.TopProductStat{
@@ -156,3 +188,76 @@ func (r *salesReportRepository) GetTopProducts(startDate, endDate time.Time, lim
 
 	return topProducts, nil
 }
+
+// GetTipsByCashier totals the tips of paid orders per cashier. Orders placed
+// without a cashier, such as customer checkouts, are grouped under uuid.Nil.
+func (r *salesReportRepository) GetTipsByCashier(startDate, endDate time.Time) ([]entity.CashierTipStat, error) {
+	var stats []entity.CashierTipStat
+
+	rows, err := r.db.Model(&entity.Order{}).
+		Joins("LEFT JOIN users ON orders.cashier_id = users.user_id").
+		Where("orders.created_at >= ? AND orders.created_at < ? AND orders.status = ? AND orders.tip_amount > 0", startDate, endDate, "paid").
+		Select("orders.cashier_id, COALESCE(users.fullname, ''), SUM(orders.tip_amount), COUNT(orders.order_id)").
+		Group("orders.cashier_id, users.fullname").
+		Order("SUM(orders.tip_amount) DESC").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cashierID *uuid.UUID
+		var cashierName string
+		var tipAmount money.Amount
+		var orderCount int64
+		rows.Scan(&cashierID, &cashierName, &tipAmount, &orderCount)
+
+		stat := entity.CashierTipStat{
+			CashierName: cashierName,
+			TipAmount:   tipAmount,
+			OrderCount:  orderCount,
+		}
+		if cashierID != nil {
+			stat.CashierID = *cashierID
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+// StreamOrderLines calls fn for every line of the paid orders in the period,
+// oldest order first. Rows are read one at a time so long periods are never
+// loaded into memory; an error from fn stops the scan and is returned.
+func (r *salesReportRepository) StreamOrderLines(startDate, endDate time.Time, fn func(entity.SalesOrderLine) error) error {
+	rows, err := r.db.Model(&entity.OrderItem{}).
+		Joins("JOIN orders ON order_items.order_id = orders.order_id").
+		Joins("LEFT JOIN products ON order_items.product_id = products.product_id").
+		Joins("LEFT JOIN users ON orders.cashier_id = users.user_id").
+		Where("orders.created_at >= ? AND orders.created_at < ? AND orders.status = ?", startDate, endDate, "paid").
+		Select("order_items.order_id, orders.created_at, COALESCE(orders.payment_method, ''), COALESCE(users.fullname, ''), order_items.item_type, " +
+			"COALESCE(products.name, ''), order_items.quantity, order_items.price_per_item, order_items.discount_amount, order_items.tax_amount, order_items.total_price").
+		Order("orders.created_at, order_items.order_id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line entity.SalesOrderLine
+		if err := rows.Scan(&line.OrderID, &line.OrderedAt, &line.PaymentMethod, &line.CashierName, &line.ItemType,
+			&line.ItemName, &line.Quantity, &line.PricePerItem, &line.DiscountAmount, &line.TaxAmount, &line.TotalPrice); err != nil {
+			return err
+		}
+		if line.ItemType == entity.OrderItemGiftCard {
+			line.ItemName = "Gift card"
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettingRepository interface {
+	GetAllSettings() ([]entity.StoreSetting, error)
+	UpsertSettings(values map[string]string) error
+}
+
+type settingRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingRepository(db *gorm.DB) SettingRepository {
+	return &settingRepository{db: db}
+}
+
+func (r *settingRepository) GetAllSettings() ([]entity.StoreSetting, error) {
+	var settings []entity.StoreSetting
+	err := r.db.Find(&settings).Error
+	return settings, err
+}
+
+func (r *settingRepository) UpsertSettings(values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	settings := make([]entity.StoreSetting, 0, len(values))
+	for key, value := range values {
+		settings = append(settings, entity.StoreSetting{Key: key, Value: value, UpdatedAt: time.Now()})
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "setting_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"setting_value", "updated_at"}),
+	}).Create(&settings).Error
+}
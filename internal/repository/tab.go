@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TabRepository interface {
+	CreateTable(table *entity.DiningTable) error
+	SaveTable(table *entity.DiningTable) error
+	FindTableByID(tableID uuid.UUID) (*entity.DiningTable, error)
+	FindAllTables() ([]entity.DiningTable, error)
+	FindOpenTabs() ([]entity.Tab, error)
+	FindTabByID(tabID uuid.UUID) (*entity.Tab, error)
+	FindKitchenTickets(status string) ([]entity.KitchenTicket, error)
+	FindKitchenTicketByID(ticketID uuid.UUID) (*entity.KitchenTicket, error)
+}
+
+type tabRepository struct {
+	db *gorm.DB
+}
+
+func NewTabRepository(db *gorm.DB) TabRepository {
+	return &tabRepository{db: db}
+}
+
+func (r *tabRepository) CreateTable(table *entity.DiningTable) error {
+	if table == nil {
+		return errors.New("table is nil")
+	}
+	return r.db.Create(table).Error
+}
+
+func (r *tabRepository) SaveTable(table *entity.DiningTable) error {
+	if table == nil {
+		return errors.New("table is nil")
+	}
+	return r.db.Save(table).Error
+}
+
+func (r *tabRepository) FindTableByID(tableID uuid.UUID) (*entity.DiningTable, error) {
+	var table entity.DiningTable
+	if err := r.db.Where("table_id = ?", tableID).First(&table).Error; err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *tabRepository) FindAllTables() ([]entity.DiningTable, error) {
+	var tables []entity.DiningTable
+	err := r.db.Order("area, name").Find(&tables).Error
+	return tables, err
+}
+
+func (r *tabRepository) FindOpenTabs() ([]entity.Tab, error) {
+	var tabs []entity.Tab
+	err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("round, created_at")
+	}).Preload("Items.Modifiers.Option").Where("status = ?", entity.TabOpen).
+		Order("opened_at ASC").
+		Find(&tabs).Error
+	return tabs, err
+}
+
+func (r *tabRepository) FindTabByID(tabID uuid.UUID) (*entity.Tab, error) {
+	var tab entity.Tab
+	err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("round, created_at")
+	}).Preload("Items.Modifiers.Option").Where("tab_id = ?", tabID).First(&tab).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tab, nil
+}
+
+// FindKitchenTickets lists tickets oldest first, as the kitchen works them.
+// An empty status lists the tickets that are not done.
+func (r *tabRepository) FindKitchenTickets(status string) ([]entity.KitchenTicket, error) {
+	var tickets []entity.KitchenTicket
+	query := r.db.Preload("Items.Modifiers.Option")
+	if status == "" {
+		query = query.Where("status <> ?", entity.TicketDone)
+	} else {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at ASC").Find(&tickets).Error
+	return tickets, err
+}
+
+func (r *tabRepository) FindKitchenTicketByID(ticketID uuid.UUID) (*entity.KitchenTicket, error) {
+	var ticket entity.KitchenTicket
+	if err := r.db.Preload("Items.Modifiers.Option").Where("ticket_id = ?", ticketID).First(&ticket).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaxRepository interface {
+	CreateTaxClass(taxClass *entity.TaxClass) error
+	FindAllTaxClasses() ([]entity.TaxClass, error)
+	FindTaxClassByID(taxClassID uuid.UUID) (*entity.TaxClass, error)
+	CreateTaxRate(taxRate *entity.TaxRate) error
+	FindTaxRatesByClassID(taxClassID uuid.UUID) ([]entity.TaxRate, error)
+	FindEffectiveTaxRate(taxClassID uuid.UUID, at time.Time) (*entity.TaxRate, error)
+}
+
+type taxRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRepository(db *gorm.DB) TaxRepository {
+	return &taxRepository{db: db}
+}
+
+func (r *taxRepository) CreateTaxClass(taxClass *entity.TaxClass) error {
+	if taxClass == nil {
+		return errors.New("tax class is nil")
+	}
+	return r.db.Create(taxClass).Error
+}
+
+func (r *taxRepository) FindAllTaxClasses() ([]entity.TaxClass, error) {
+	var taxClasses []entity.TaxClass
+	err := r.db.Preload("Rates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("effective_from ASC")
+	}).Order("name ASC").Find(&taxClasses).Error
+	return taxClasses, err
+}
+
+func (r *taxRepository) FindTaxClassByID(taxClassID uuid.UUID) (*entity.TaxClass, error) {
+	var taxClass entity.TaxClass
+	err := r.db.Where("tax_class_id = ?", taxClassID).First(&taxClass).Error
+	if err != nil {
+		return nil, err
+	}
+	return &taxClass, nil
+}
+
+// CreateTaxRate closes the currently open-ended rate of the class at the new
+// rate's effective_from so that only one rate applies at any point in time.
+func (r *taxRepository) CreateTaxRate(taxRate *entity.TaxRate) error {
+	if taxRate == nil {
+		return errors.New("tax rate is nil")
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var later int64
+		if err := tx.Model(&entity.TaxRate{}).
+			Where("tax_class_id = ? AND effective_from >= ?", taxRate.TaxClassID, taxRate.EffectiveFrom).
+			Count(&later).Error; err != nil {
+			return err
+		}
+		if later > 0 {
+			return errors.New("a rate starting on or after effective_from already exists")
+		}
+
+		if err := tx.Model(&entity.TaxRate{}).
+			Where("tax_class_id = ? AND effective_to IS NULL", taxRate.TaxClassID).
+			Update("effective_to", taxRate.EffectiveFrom).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(taxRate).Error
+	})
+}
+
+func (r *taxRepository) FindTaxRatesByClassID(taxClassID uuid.UUID) ([]entity.TaxRate, error) {
+	var taxRates []entity.TaxRate
+	err := r.db.Where("tax_class_id = ?", taxClassID).Order("effective_from ASC").Find(&taxRates).Error
+	return taxRates, err
+}
+
+func (r *taxRepository) FindEffectiveTaxRate(taxClassID uuid.UUID, at time.Time) (*entity.TaxRate, error) {
+	var taxRate entity.TaxRate
+	err := r.db.Where("tax_class_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", taxClassID, at, at).
+		Order("effective_from DESC").
+		First(&taxRate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &taxRate, nil
+}
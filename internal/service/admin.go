@@ -57,13 +57,7 @@ func (s *adminService) LoginAdmin(email string, password string) (string, error)
 
 	expiredTime := time.Now().Local().Add(24 * time.Hour)
 
-	location, err := time.LoadLocation("Asia/Jakarta")
-	if err != nil {
-		panic(err)
-	}
-
 	admin.Phone, _ = s.encryptTool.Decrypt(admin.Phone)
-	expiredTimeInJakarta := expiredTime.In(location)
 
 	claims := token.JwtCustomClaims{
 		ID:    admin.User_ID.String(),
@@ -71,7 +65,7 @@ func (s *adminService) LoginAdmin(email string, password string) (string, error)
 		Role:  "admin",
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "Depublic",
-			ExpiresAt: jwt.NewNumericDate(expiredTimeInJakarta),
+			ExpiresAt: jwt.NewNumericDate(expiredTime),
 		},
 	}
 
@@ -11,28 +11,33 @@ import (
 )
 
 type CartService interface {
-	AddItem(userID uuid.UUID, productID uuid.UUID, qty int) (*entity.Cart, error)
+	AddItem(userID uuid.UUID, productID uuid.UUID, qty int, optionIDs []uuid.UUID) (*entity.Cart, error)
 	UpdateItem(cartItemID uuid.UUID, qty int) (*entity.Cart, error)
 	RemoveItem(cartItemID uuid.UUID) error
 	GetCart(userID uuid.UUID) (*entity.Cart, error)
-	Checkout(userID uuid.UUID, paymentMethod string, paidAmount float64) (*entity.Order, error)
+	Quote(order *entity.Order) (*entity.Order, error)
+	Checkout(order *entity.Order) (*entity.Order, error)
 }
 
 type cartService struct {
-	cartRepository repository.CartRepository
-	orderService   OrderService
-	productRepo    repository.ProductRepository
+	cartRepository  repository.CartRepository
+	orderService    OrderService
+	productRepo     repository.ProductRepository
+	modifierService ModifierService
 }
 
-func NewCartService(cartRepository repository.CartRepository, orderService OrderService, productRepo repository.ProductRepository) *cartService {
+func NewCartService(cartRepository repository.CartRepository, orderService OrderService, productRepo repository.ProductRepository, modifierService ModifierService) *cartService {
 	return &cartService{
-		cartRepository: cartRepository,
-		orderService:   orderService,
-		productRepo:    productRepo,
+		cartRepository:  cartRepository,
+		orderService:    orderService,
+		productRepo:     productRepo,
+		modifierService: modifierService,
 	}
 }
 
-func (s *cartService) AddItem(userID uuid.UUID, productID uuid.UUID, qty int) (*entity.Cart, error) {
+// AddItem adds a product with the chosen modifier options to the cart. The
+// same product with other options is a separate cart item.
+func (s *cartService) AddItem(userID uuid.UUID, productID uuid.UUID, qty int, optionIDs []uuid.UUID) (*entity.Cart, error) {
 	if qty <= 0 {
 		return nil, errors.New("quantity must be greater than 0")
 	}
@@ -41,6 +46,9 @@ func (s *cartService) AddItem(userID uuid.UUID, productID uuid.UUID, qty int) (*
 	if _, err := s.productRepo.FindProductByID(productID.String()); err != nil {
 		return nil, errors.New("product not found")
 	}
+	if _, err := s.modifierService.ResolveModifiers(productID, optionIDs); err != nil {
+		return nil, err
+	}
 
 	cart, err := s.cartRepository.GetActiveCartByUserID(userID)
 	if err != nil {
@@ -54,24 +62,41 @@ func (s *cartService) AddItem(userID uuid.UUID, productID uuid.UUID, qty int) (*
 		}
 	}
 
-	item, err := s.cartRepository.GetCartItem(cart.CartID, productID)
-	if err == nil && item != nil {
+	items, err := s.cartRepository.GetCartItems(cart.CartID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := modifierKey(productID, optionIDs)
+	var item *entity.CartItem
+	for i := range items {
+		if cartItemKey(items[i]) == key {
+			item = &items[i]
+			break
+		}
+	}
+
+	if item != nil {
 		newQty := item.Quantity + qty
 		if err := s.cartRepository.UpdateCartItemQuantity(item.CartItemID, newQty); err != nil {
 			return nil, err
 		}
-	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+	} else {
 		newItem := &entity.CartItem{
 			CartItemID: uuid.New(),
 			CartID:     cart.CartID,
 			ProductID:  productID,
 			Quantity:   qty,
 		}
+		for _, optionID := range optionIDs {
+			newItem.Modifiers = append(newItem.Modifiers, entity.CartItemModifier{
+				CartItemID:       newItem.CartItemID,
+				ModifierOptionID: optionID,
+			})
+		}
 		if err := s.cartRepository.CreateCartItem(newItem); err != nil {
 			return nil, err
 		}
-	} else if err != nil {
-		return nil, err
 	}
 
 	return s.cartRepository.GetCartWithItems(cart.CartID)
@@ -108,8 +133,10 @@ func (s *cartService) GetCart(userID uuid.UUID) (*entity.Cart, error) {
 	return cart, nil
 }
 
-func (s *cartService) Checkout(userID uuid.UUID, paymentMethod string, paidAmount float64) (*entity.Order, error) {
-	cart, err := s.cartRepository.GetActiveCartByUserID(userID)
+// Quote prices the active cart of order.UserID, including promotions, tax,
+// service charge, tip and redeemed points, without placing an order.
+func (s *cartService) Quote(order *entity.Order) (*entity.Order, error) {
+	cart, err := s.cartRepository.GetActiveCartByUserID(order.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -117,21 +144,25 @@ func (s *cartService) Checkout(userID uuid.UUID, paymentMethod string, paidAmoun
 		return nil, errors.New("cart is empty")
 	}
 
-	orderItems := make([]entity.OrderItem, 0, len(cart.Items))
-	for _, item := range cart.Items {
-		orderItems = append(orderItems, entity.OrderItem{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-		})
+	order.OrderItems = cartOrderItems(cart)
+	if err := s.orderService.QuoteOrder(order); err != nil {
+		return nil, err
 	}
+	return order, nil
+}
 
-	order := &entity.Order{
-		UserID:        userID,
-		PaymentMethod: paymentMethod,
-		PaidAmount:    paidAmount,
-		OrderItems:    orderItems,
+// Checkout places an order for the active cart of order.UserID. The order
+// carries the payment details; its items are taken from the cart.
+func (s *cartService) Checkout(order *entity.Order) (*entity.Order, error) {
+	cart, err := s.cartRepository.GetActiveCartByUserID(order.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, errors.New("cart is empty")
 	}
 
+	order.OrderItems = cartOrderItems(cart)
 	if err := s.orderService.CreateOrder(order); err != nil {
 		return nil, err
 	}
@@ -142,3 +173,27 @@ func (s *cartService) Checkout(userID uuid.UUID, paymentMethod string, paidAmoun
 
 	return order, nil
 }
+
+func cartOrderItems(cart *entity.Cart) []entity.OrderItem {
+	orderItems := make([]entity.OrderItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		orderItem := entity.OrderItem{
+			ProductID: &item.ProductID,
+			Quantity:  item.Quantity,
+		}
+		for _, modifier := range item.Modifiers {
+			optionID := modifier.ModifierOptionID
+			orderItem.Modifiers = append(orderItem.Modifiers, entity.OrderItemModifier{ModifierOptionID: &optionID})
+		}
+		orderItems = append(orderItems, orderItem)
+	}
+	return orderItems
+}
+
+func cartItemKey(item entity.CartItem) string {
+	optionIDs := make([]uuid.UUID, 0, len(item.Modifiers))
+	for _, modifier := range item.Modifiers {
+		optionIDs = append(optionIDs, modifier.ModifierOptionID)
+	}
+	return modifierKey(item.ProductID, optionIDs)
+}
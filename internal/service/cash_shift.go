@@ -0,0 +1,473 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errNoOpenShift = errors.New("no open shift, open a shift first")
+
+// ShiftService keeps the cash drawer of each cashier. The sale and refund
+// methods take the transaction of the order change they belong to and do
+// nothing when the cashier has no open shift.
+type ShiftService interface {
+	OpenShift(cashierID uuid.UUID, openingFloat money.Amount, note string) (*entity.CashShift, error)
+	GetCurrentShift(cashierID uuid.UUID) (*entity.ShiftReport, error)
+	AddCashMovement(cashierID uuid.UUID, movementType string, amount money.Amount, reason string) (*entity.CashMovement, error)
+	CloseShift(cashierID uuid.UUID, countedCash money.Amount, note string) (*entity.ShiftReport, error)
+	GetShiftReport(shiftID uuid.UUID) (*entity.ShiftReport, error)
+	GetZReport(date time.Time) (*entity.ZReport, error)
+	RecordSale(tx *gorm.DB, order *entity.Order) error
+	RecordRefund(tx *gorm.DB, order *entity.Order, cashierID uuid.UUID) error
+}
+
+type shiftService struct {
+	shiftRepo      repository.ShiftRepository
+	settingService SettingService
+	db             *gorm.DB
+}
+
+func NewShiftService(shiftRepo repository.ShiftRepository, settingService SettingService, db *gorm.DB) *shiftService {
+	return &shiftService{
+		shiftRepo:      shiftRepo,
+		settingService: settingService,
+		db:             db,
+	}
+}
+
+func (s *shiftService) OpenShift(cashierID uuid.UUID, openingFloat money.Amount, note string) (*entity.CashShift, error) {
+	if openingFloat.IsNegative() {
+		return nil, errors.New("opening_float cannot be negative")
+	}
+
+	if _, err := s.shiftRepo.FindOpenShift(cashierID); err == nil {
+		return nil, errors.New("a shift is already open, close it first")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	shift := &entity.CashShift{
+		ShiftID:      uuid.New(),
+		CashierID:    cashierID,
+		Status:       entity.ShiftOpen,
+		OpeningFloat: openingFloat,
+		Note:         note,
+		OpenedAt:     time.Now(),
+	}
+	if err := s.shiftRepo.CreateShift(shift); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+func (s *shiftService) GetCurrentShift(cashierID uuid.UUID) (*entity.ShiftReport, error) {
+	shift, err := s.shiftRepo.FindOpenShift(cashierID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errNoOpenShift
+		}
+		return nil, err
+	}
+	return s.shiftReport(shift)
+}
+
+// AddCashMovement records a pay-in or pay-out, for example change brought
+// in from the safe or a supplier paid from the drawer.
+func (s *shiftService) AddCashMovement(cashierID uuid.UUID, movementType string, amount money.Amount, reason string) (*entity.CashMovement, error) {
+	if movementType != entity.CashPayIn && movementType != entity.CashPayOut {
+		return nil, errors.New("type must be 'pay_in' or 'pay_out'")
+	}
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	var movement *entity.CashMovement
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		shift, err := lockOpenShift(tx, "cashier_id = ?", cashierID)
+		if err != nil {
+			return err
+		}
+		if movementType == entity.CashPayOut {
+			report := cashUp(shift)
+			if amount.GreaterThan(report.ExpectedCash) {
+				return fmt.Errorf("pay-out is more than the %s in the drawer", report.ExpectedCash)
+			}
+		}
+		movement, err = addCashMovement(tx, shift, nil, movementType, amount, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// CloseShift compares the counted drawer with the expected cash and closes
+// the shift. Sales that wait for the shift lock afterwards no longer find it
+// open, so nothing is added to a closed drawer.
+func (s *shiftService) CloseShift(cashierID uuid.UUID, countedCash money.Amount, note string) (*entity.ShiftReport, error) {
+	if countedCash.IsNegative() {
+		return nil, errors.New("counted_cash cannot be negative")
+	}
+
+	var shiftID uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		shift, err := lockOpenShift(tx, "cashier_id = ?", cashierID)
+		if err != nil {
+			return err
+		}
+		shiftID = shift.ShiftID
+
+		expected := cashUp(shift).ExpectedCash
+		variance := countedCash.Sub(expected)
+		updates := map[string]interface{}{
+			"status":        entity.ShiftClosed,
+			"expected_cash": expected,
+			"counted_cash":  countedCash,
+			"variance":      variance,
+			"closed_at":     time.Now(),
+		}
+		if note != "" {
+			updates["note"] = strings.TrimSpace(shift.Note + "\n" + note)
+		}
+		return tx.Model(&entity.CashShift{}).Where("shift_id = ?", shift.ShiftID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetShiftReport(shiftID)
+}
+
+func (s *shiftService) GetShiftReport(shiftID uuid.UUID) (*entity.ShiftReport, error) {
+	shift, err := s.shiftRepo.FindShiftByID(shiftID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("shift not found")
+		}
+		return nil, err
+	}
+	return s.shiftReport(shift)
+}
+
+// GetZReport adds up every shift opened on the given day in the store's time
+// zone, today when date is zero.
+func (s *shiftService) GetZReport(date time.Time) (*entity.ZReport, error) {
+	location := s.settingService.Location()
+	if date.IsZero() {
+		date = time.Now().In(location)
+	}
+	startDate := businessDay(date, location)
+	shifts, err := s.shiftRepo.FindShiftsOpenedBetween(startDate, startDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]entity.ShiftReport, 0, len(shifts))
+	for i := range shifts {
+		report, err := s.shiftReport(&shifts[i])
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *report)
+	}
+
+	zReport := summarizeShifts(startDate, reports)
+	zReport.StoreName = s.settingService.StoreName()
+	return zReport, nil
+}
+
+// RecordSale puts a new order on the open shift of its cashier and adds the
+// cash kept from it, the cash tender without the change, to the drawer.
+func (s *shiftService) RecordSale(tx *gorm.DB, order *entity.Order) error {
+	if order.CashierID == nil {
+		return nil
+	}
+
+	shift, err := lockOpenShift(tx, "cashier_id = ?", *order.CashierID)
+	if errors.Is(err, errNoOpenShift) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entity.Order{}).
+		Where("order_id = ?", order.OrderID).
+		Update("shift_id", shift.ShiftID).Error; err != nil {
+		return err
+	}
+	order.ShiftID = &shift.ShiftID
+
+	var cash money.Amount
+	for _, payment := range order.Payments {
+		if payment.Method == entity.PaymentCash && payment.Status == entity.PaymentSettled {
+			cash = cash.Add(payment.Amount)
+		}
+	}
+	if !cash.IsPositive() {
+		return nil
+	}
+	_, err = addCashMovement(tx, shift, &order.OrderID, entity.CashSale, cash, "Cash sale")
+	return err
+}
+
+// RecordRefund takes the settled cash of a cancelled or refunded order out
+// of the drawer of the cashier giving it back, or out of the shift the order
+// was sold on when that cashier has no open shift. It must run before the
+// payments are marked refunded.
+func (s *shiftService) RecordRefund(tx *gorm.DB, order *entity.Order, cashierID uuid.UUID) error {
+	var cash money.Amount
+	if err := tx.Model(&entity.OrderPayment{}).
+		Where("order_id = ? AND method = ? AND status = ?", order.OrderID, entity.PaymentCash, entity.PaymentSettled).
+		Select("COALESCE(SUM(amount), 0)").Row().Scan(&cash); err != nil {
+		return err
+	}
+	if !cash.IsPositive() {
+		return nil
+	}
+
+	var refunded int64
+	if err := tx.Model(&entity.CashMovement{}).
+		Where("order_id = ? AND type = ?", order.OrderID, entity.CashRefund).
+		Count(&refunded).Error; err != nil {
+		return err
+	}
+	if refunded > 0 {
+		return nil
+	}
+
+	shift, err := lockOpenShift(tx, "cashier_id = ?", cashierID)
+	if errors.Is(err, errNoOpenShift) && order.ShiftID != nil {
+		shift, err = lockOpenShift(tx, "shift_id = ?", *order.ShiftID)
+	}
+	if errors.Is(err, errNoOpenShift) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = addCashMovement(tx, shift, &order.OrderID, entity.CashRefund, cash, "Cash refund")
+	return err
+}
+
+func (s *shiftService) shiftReport(shift *entity.CashShift) (*entity.ShiftReport, error) {
+	report := cashUp(shift)
+
+	tenders, err := s.shiftRepo.GetShiftTenders(shift.ShiftID)
+	if err != nil {
+		return nil, err
+	}
+	report.Tenders = tenderPercentages(tenders)
+
+	if report.OrderCount, err = s.shiftRepo.CountShiftOrders(shift.ShiftID); err != nil {
+		return nil, err
+	}
+	if report.CashierName, err = s.shiftRepo.FindCashierName(shift.CashierID); err != nil &&
+		!errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// lockOpenShift loads an open shift with its movements and locks it until tx
+// ends, so that cash is not added while the shift is being closed.
+func lockOpenShift(tx *gorm.DB, query string, arg interface{}) (*entity.CashShift, error) {
+	var shift entity.CashShift
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(query, arg).
+		Where("status = ?", entity.ShiftOpen).
+		First(&shift).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errNoOpenShift
+		}
+		return nil, err
+	}
+	if err := tx.Where("shift_id = ?", shift.ShiftID).Order("created_at ASC").Find(&shift.Movements).Error; err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+func addCashMovement(tx *gorm.DB, shift *entity.CashShift, orderID *uuid.UUID, movementType string, amount money.Amount, reason string) (*entity.CashMovement, error) {
+	movement := &entity.CashMovement{
+		CashMovementID: uuid.New(),
+		ShiftID:        shift.ShiftID,
+		OrderID:        orderID,
+		Type:           movementType,
+		Amount:         amount,
+		Reason:         reason,
+		CreatedAt:      time.Now(),
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, err
+	}
+	shift.Movements = append(shift.Movements, *movement)
+	return movement, nil
+}
+
+// cashUp totals the movements of a shift. The expected cash is the opening
+// float plus what came into the drawer minus what went out of it.
+func cashUp(shift *entity.CashShift) *entity.ShiftReport {
+	report := &entity.ShiftReport{
+		Shift:        *shift,
+		OpeningFloat: shift.OpeningFloat,
+		CountedCash:  shift.CountedCash,
+		Variance:     shift.Variance,
+	}
+	for _, movement := range shift.Movements {
+		switch movement.Type {
+		case entity.CashSale:
+			report.CashSales = report.CashSales.Add(movement.Amount)
+		case entity.CashRefund:
+			report.CashRefunds = report.CashRefunds.Add(movement.Amount)
+		case entity.CashPayIn:
+			report.PayIns = report.PayIns.Add(movement.Amount)
+		case entity.CashPayOut:
+			report.PayOuts = report.PayOuts.Add(movement.Amount)
+		}
+	}
+	report.ExpectedCash = shift.OpeningFloat.
+		Add(report.CashSales).
+		Sub(report.CashRefunds).
+		Add(report.PayIns).
+		Sub(report.PayOuts)
+	return report
+}
+
+// summarizeShifts adds up the shift reports of a day. Tenders are merged by
+// method; an order is only ever on one shift, so the counts add up too.
+func summarizeShifts(date time.Time, reports []entity.ShiftReport) *entity.ZReport {
+	zReport := &entity.ZReport{
+		Date:       date,
+		ShiftCount: len(reports),
+		Shifts:     reports,
+	}
+
+	tenders := make(map[string]*entity.PaymentMethodStat)
+	for _, report := range reports {
+		zReport.OpeningFloat = zReport.OpeningFloat.Add(report.OpeningFloat)
+		zReport.CashSales = zReport.CashSales.Add(report.CashSales)
+		zReport.CashRefunds = zReport.CashRefunds.Add(report.CashRefunds)
+		zReport.PayIns = zReport.PayIns.Add(report.PayIns)
+		zReport.PayOuts = zReport.PayOuts.Add(report.PayOuts)
+		zReport.ExpectedCash = zReport.ExpectedCash.Add(report.ExpectedCash)
+		zReport.OrderCount += report.OrderCount
+		if report.Shift.Status == entity.ShiftOpen {
+			zReport.OpenShifts++
+		}
+		if report.CountedCash != nil {
+			zReport.CountedCash = zReport.CountedCash.Add(*report.CountedCash)
+		}
+		if report.Variance != nil {
+			zReport.Variance = zReport.Variance.Add(*report.Variance)
+		}
+
+		for _, tender := range report.Tenders {
+			total, ok := tenders[tender.PaymentMethod]
+			if !ok {
+				total = &entity.PaymentMethodStat{PaymentMethod: tender.PaymentMethod}
+				tenders[tender.PaymentMethod] = total
+			}
+			total.TotalAmount = total.TotalAmount.Add(tender.TotalAmount)
+			total.Count += tender.Count
+		}
+	}
+
+	merged := make([]entity.PaymentMethodStat, 0, len(tenders))
+	for _, tender := range tenders {
+		merged = append(merged, *tender)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].PaymentMethod < merged[j].PaymentMethod
+	})
+	zReport.Tenders = tenderPercentages(merged)
+	return zReport
+}
+
+func tenderPercentages(tenders []entity.PaymentMethodStat) []entity.PaymentMethodStat {
+	var total money.Amount
+	for _, tender := range tenders {
+		total = total.Add(tender.TotalAmount)
+	}
+	for i := range tenders {
+		tenders[i].Percentage = 0
+		if total.IsPositive() {
+			tenders[i].Percentage = float64(tenders[i].TotalAmount.Sen()) / float64(total.Sen()) * 100
+		}
+	}
+	return tenders
+}
+
+// zReportWidth fits an 80mm receipt printer.
+const zReportWidth = 42
+
+// FormatZReport lays out a Z-report as plain text for a receipt printer.
+func FormatZReport(report *entity.ZReport) string {
+	var b strings.Builder
+	line := func(label, value string) {
+		pad := zReportWidth - len(label) - len(value)
+		if pad < 1 {
+			pad = 1
+		}
+		b.WriteString(label + strings.Repeat(" ", pad) + value + "\n")
+	}
+	rule := func(char string) {
+		b.WriteString(strings.Repeat(char, zReportWidth) + "\n")
+	}
+	cashLines := func(opening, sales, refunds, payIns, payOuts, expected money.Amount) {
+		line("Opening float", opening.String())
+		line("Cash sales", sales.String())
+		line("Cash refunds", "-"+refunds.String())
+		line("Pay-ins", payIns.String())
+		line("Pay-outs", "-"+payOuts.String())
+		line("Expected cash", expected.String())
+	}
+
+	rule("=")
+	if report.StoreName != "" {
+		b.WriteString(report.StoreName + "\n")
+	}
+	b.WriteString("Z-REPORT " + report.Date.Format("2006-01-02") + "\n")
+	rule("=")
+	line("Shifts", fmt.Sprintf("%d (%d open)", report.ShiftCount, report.OpenShifts))
+	line("Orders", fmt.Sprintf("%d", report.OrderCount))
+	rule("-")
+	cashLines(report.OpeningFloat, report.CashSales, report.CashRefunds, report.PayIns, report.PayOuts, report.ExpectedCash)
+	line("Counted cash", report.CountedCash.String())
+	line("Over/short", report.Variance.String())
+	rule("-")
+	b.WriteString("SALES BY TENDER\n")
+	for _, tender := range report.Tenders {
+		line(fmt.Sprintf("%s (%d)", tender.PaymentMethod, tender.Count), tender.TotalAmount.String())
+	}
+
+	for _, shift := range report.Shifts {
+		rule("-")
+		b.WriteString(fmt.Sprintf("SHIFT %s %s\n", shift.Shift.OpenedAt.In(report.Date.Location()).Format("15:04"), shift.CashierName))
+		cashLines(shift.OpeningFloat, shift.CashSales, shift.CashRefunds, shift.PayIns, shift.PayOuts, shift.ExpectedCash)
+		if shift.CountedCash == nil {
+			line("Counted cash", "OPEN")
+			continue
+		}
+		line("Counted cash", shift.CountedCash.String())
+		line("Over/short", shift.Variance.String())
+	}
+	rule("=")
+	return b.String()
+}
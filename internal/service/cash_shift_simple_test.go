@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+func closedShift(counted, variance money.Amount) *entity.CashShift {
+	return &entity.CashShift{
+		Status:       entity.ShiftClosed,
+		OpeningFloat: money.New(200000),
+		CountedCash:  &counted,
+		Variance:     &variance,
+		Movements: []entity.CashMovement{
+			{Type: entity.CashSale, Amount: money.New(150000)},
+			{Type: entity.CashSale, Amount: money.New(50000)},
+			{Type: entity.CashRefund, Amount: money.New(25000)},
+			{Type: entity.CashPayIn, Amount: money.New(100000)},
+			{Type: entity.CashPayOut, Amount: money.New(30000)},
+		},
+	}
+}
+
+// TestCashUp tests the expected cash of a shift
+func TestCashUp(t *testing.T) {
+	report := cashUp(closedShift(money.New(440000), money.New(-5000)))
+
+	if report.CashSales != money.New(200000) || report.CashRefunds != money.New(25000) {
+		t.Errorf("Expected sales 200000.00 and refunds 25000.00, got %s and %s", report.CashSales, report.CashRefunds)
+	}
+	if report.PayIns != money.New(100000) || report.PayOuts != money.New(30000) {
+		t.Errorf("Expected pay-ins 100000.00 and pay-outs 30000.00, got %s and %s", report.PayIns, report.PayOuts)
+	}
+	if report.ExpectedCash != money.New(445000) {
+		t.Errorf("Expected cash 445000.00, got %s", report.ExpectedCash)
+	}
+
+	open := cashUp(&entity.CashShift{Status: entity.ShiftOpen, OpeningFloat: money.New(100000)})
+	if open.ExpectedCash != money.New(100000) || open.Variance != nil {
+		t.Errorf("Expected an open shift to hold its float without a variance, got %s", open.ExpectedCash)
+	}
+}
+
+// TestSummarizeShifts tests adding up the shifts of a day
+func TestSummarizeShifts(t *testing.T) {
+	closed := *cashUp(closedShift(money.New(440000), money.New(-5000)))
+	closed.OrderCount = 4
+	closed.Tenders = []entity.PaymentMethodStat{
+		{PaymentMethod: entity.PaymentCash, TotalAmount: money.New(200000), Count: 3},
+		{PaymentMethod: entity.PaymentMidtrans, TotalAmount: money.New(100000), Count: 1},
+	}
+	open := *cashUp(&entity.CashShift{Status: entity.ShiftOpen, OpeningFloat: money.New(100000)})
+	open.OrderCount = 1
+	open.Tenders = []entity.PaymentMethodStat{
+		{PaymentMethod: entity.PaymentMidtrans, TotalAmount: money.New(100000), Count: 1},
+	}
+
+	zReport := summarizeShifts(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), []entity.ShiftReport{closed, open})
+
+	if zReport.ShiftCount != 2 || zReport.OpenShifts != 1 || zReport.OrderCount != 5 {
+		t.Errorf("Expected 2 shifts, 1 open and 5 orders, got %d, %d and %d", zReport.ShiftCount, zReport.OpenShifts, zReport.OrderCount)
+	}
+	if zReport.ExpectedCash != money.New(545000) {
+		t.Errorf("Expected cash 545000.00, got %s", zReport.ExpectedCash)
+	}
+	if zReport.CountedCash != money.New(440000) || zReport.Variance != money.New(-5000) {
+		t.Errorf("Expected counted 440000.00 and variance -5000.00 from the closed shift, got %s and %s", zReport.CountedCash, zReport.Variance)
+	}
+	if len(zReport.Tenders) != 2 || zReport.Tenders[1].TotalAmount != money.New(200000) || zReport.Tenders[1].Count != 2 {
+		t.Fatalf("Expected midtrans tenders merged across shifts, got %+v", zReport.Tenders)
+	}
+	if zReport.Tenders[0].Percentage != 50 {
+		t.Errorf("Expected cash to be 50%% of tenders, got %v", zReport.Tenders[0].Percentage)
+	}
+
+	text := FormatZReport(zReport)
+	for _, want := range []string{"Z-REPORT 2026-01-02", "Over/short", "-5000.00", "OPEN"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected printed Z-report to contain %q", want)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if len(line) > zReportWidth {
+			t.Errorf("Expected lines of at most %d characters, got %q", zReportWidth, line)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxGeneratedCoupons = 10000
+	generatedCodeLength = 8
+	// Letters and digits that are hard to confuse when read aloud or printed
+	couponAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+var (
+	couponCodePattern   = regexp.MustCompile(`^[A-Z0-9-]{4,50}$`)
+	couponPrefixPattern = regexp.MustCompile(`^[A-Z0-9-]{0,20}$`)
+)
+
+type CouponService interface {
+	CreateCode(promotionID uuid.UUID, code string, maxRedemptions *int, expiresAt *time.Time) (*entity.CouponCode, error)
+	GenerateCodes(promotionID uuid.UUID, count int, prefix string, maxRedemptions *int, expiresAt *time.Time) ([]entity.CouponCode, error)
+	FindCodesByPromotionID(promotionID uuid.UUID) ([]entity.CouponCode, error)
+	GetRedemptionReport(promotionID uuid.UUID) (*entity.CouponRedemptionReport, error)
+	ResolveCoupon(code string, userID uuid.UUID, at time.Time) (*entity.CouponCode, *entity.Promotion, error)
+}
+
+type couponService struct {
+	couponRepo    repository.CouponRepository
+	promotionRepo repository.PromotionRepository
+}
+
+func NewCouponService(couponRepo repository.CouponRepository, promotionRepo repository.PromotionRepository) *couponService {
+	return &couponService{
+		couponRepo:    couponRepo,
+		promotionRepo: promotionRepo,
+	}
+}
+
+func (s *couponService) CreateCode(promotionID uuid.UUID, code string, maxRedemptions *int, expiresAt *time.Time) (*entity.CouponCode, error) {
+	if err := s.checkCampaign(promotionID, maxRedemptions); err != nil {
+		return nil, err
+	}
+
+	code = NormalizeCouponCode(code)
+	if !couponCodePattern.MatchString(code) {
+		return nil, errors.New("code must be 4-50 letters, digits or dashes")
+	}
+	existing, err := s.couponRepo.FindExistingCodes([]string{code})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errors.New("code already exists")
+	}
+
+	couponCodes := []entity.CouponCode{newCouponCode(promotionID, code, maxRedemptions, expiresAt)}
+	if err := s.couponRepo.CreateCouponCodes(couponCodes); err != nil {
+		return nil, err
+	}
+	return &couponCodes[0], nil
+}
+
+// GenerateCodes creates count random codes for a campaign. Codes that collide
+// with existing ones are regenerated before anything is saved.
+func (s *couponService) GenerateCodes(promotionID uuid.UUID, count int, prefix string, maxRedemptions *int, expiresAt *time.Time) ([]entity.CouponCode, error) {
+	if count <= 0 || count > maxGeneratedCoupons {
+		return nil, errors.New("count must be between 1 and 10000")
+	}
+	prefix = NormalizeCouponCode(prefix)
+	if !couponPrefixPattern.MatchString(prefix) {
+		return nil, errors.New("prefix must be at most 20 letters, digits or dashes")
+	}
+	if err := s.checkCampaign(promotionID, maxRedemptions); err != nil {
+		return nil, err
+	}
+
+	codes := make(map[string]bool, count)
+	for attempt := 0; len(codes) < count; attempt++ {
+		if attempt == 5 {
+			return nil, errors.New("could not generate enough unique codes, use a longer prefix")
+		}
+
+		batch := make([]string, 0, count-len(codes))
+		for len(codes)+len(batch) < count {
+			code, err := randomCouponCode(prefix)
+			if err != nil {
+				return nil, err
+			}
+			if !codes[code] {
+				batch = append(batch, code)
+			}
+		}
+
+		existing, err := s.couponRepo.FindExistingCodes(batch)
+		if err != nil {
+			return nil, err
+		}
+		taken := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			taken[code] = true
+		}
+		for _, code := range batch {
+			if !taken[code] {
+				codes[code] = true
+			}
+		}
+	}
+
+	couponCodes := make([]entity.CouponCode, 0, count)
+	for code := range codes {
+		couponCodes = append(couponCodes, newCouponCode(promotionID, code, maxRedemptions, expiresAt))
+	}
+	if err := s.couponRepo.CreateCouponCodes(couponCodes); err != nil {
+		return nil, err
+	}
+	return couponCodes, nil
+}
+
+func (s *couponService) FindCodesByPromotionID(promotionID uuid.UUID) ([]entity.CouponCode, error) {
+	return s.couponRepo.FindCouponCodesByPromotionID(promotionID)
+}
+
+func (s *couponService) GetRedemptionReport(promotionID uuid.UUID) (*entity.CouponRedemptionReport, error) {
+	promotion, err := s.promotionRepo.FindPromotionByID(promotionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, err
+	}
+
+	report, err := s.couponRepo.GetRedemptionReport(promotionID)
+	if err != nil {
+		return nil, err
+	}
+	report.PromotionName = promotion.Name
+	return report, nil
+}
+
+// ResolveCoupon looks up a code and its campaign for pricing a quote. Orders
+// repeat these checks on locked rows when the code is actually redeemed.
+func (s *couponService) ResolveCoupon(code string, userID uuid.UUID, at time.Time) (*entity.CouponCode, *entity.Promotion, error) {
+	couponCode, err := s.couponRepo.FindCouponCodeByCode(NormalizeCouponCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("coupon code not found")
+		}
+		return nil, nil, err
+	}
+
+	promotion, err := s.promotionRepo.FindPromotionByID(couponCode.PromotionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	redemptions, err := s.couponRepo.CountRedemptionsByUser(promotion.PromotionID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkCouponRedemption(couponCode, promotion, redemptions, at); err != nil {
+		return nil, nil, err
+	}
+	return couponCode, promotion, nil
+}
+
+func (s *couponService) checkCampaign(promotionID uuid.UUID, maxRedemptions *int) error {
+	promotion, err := s.promotionRepo.FindPromotionByID(promotionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("promotion not found")
+		}
+		return err
+	}
+	if !promotion.RequiresCode {
+		return errors.New("promotion is not a coupon campaign, set requires_code first")
+	}
+	if maxRedemptions != nil && *maxRedemptions <= 0 {
+		return errors.New("max_redemptions must be greater than 0")
+	}
+	return nil
+}
+
+// NormalizeCouponCode makes code lookups case-insensitive.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// checkCouponRedemption reports why a code cannot be redeemed by a customer
+// who already redeemed the campaign userRedemptions times.
+func checkCouponRedemption(couponCode *entity.CouponCode, promotion *entity.Promotion, userRedemptions int64, at time.Time) error {
+	if !couponCode.Active {
+		return errors.New("coupon code is no longer active")
+	}
+	if couponCode.ExpiresAt != nil && !at.Before(*couponCode.ExpiresAt) {
+		return errors.New("coupon code has expired")
+	}
+	if couponCode.MaxRedemptions != nil && couponCode.RedemptionCount >= *couponCode.MaxRedemptions {
+		return errors.New("coupon code has already been used")
+	}
+	if !promotion.IsAvailableAt(at) {
+		return errors.New("coupon campaign is not available")
+	}
+	if promotion.PerCustomerLimit != nil && userRedemptions >= int64(*promotion.PerCustomerLimit) {
+		return errors.New("coupon limit per customer reached")
+	}
+	return nil
+}
+
+func newCouponCode(promotionID uuid.UUID, code string, maxRedemptions *int, expiresAt *time.Time) entity.CouponCode {
+	return entity.CouponCode{
+		CouponCodeID:   uuid.New(),
+		PromotionID:    promotionID,
+		Code:           code,
+		MaxRedemptions: maxRedemptions,
+		ExpiresAt:      expiresAt,
+		Active:         true,
+		CreatedAt:      time.Now(),
+	}
+}
+
+func randomCouponCode(prefix string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+
+	max := big.NewInt(int64(len(couponAlphabet)))
+	for i := 0; i < generatedCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(couponAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// TestCheckCouponRedemption tests coupon code limits and expiry
+func TestCheckCouponRedemption(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	one := 1
+
+	campaign := entity.Promotion{Active: true, RequiresCode: true, StartsAt: past}
+	limited := campaign
+	limited.PerCustomerLimit = &one
+
+	tests := []struct {
+		name        string
+		code        entity.CouponCode
+		promotion   entity.Promotion
+		redemptions int64
+		wantErr     string
+	}{
+		{name: "valid multi-use code", code: entity.CouponCode{Active: true, RedemptionCount: 10}, promotion: campaign},
+		{name: "single-use code already used", code: entity.CouponCode{Active: true, MaxRedemptions: &one, RedemptionCount: 1}, promotion: campaign, wantErr: "already been used"},
+		{name: "expired code", code: entity.CouponCode{Active: true, ExpiresAt: &past}, promotion: campaign, wantErr: "expired"},
+		{name: "inactive code", code: entity.CouponCode{}, promotion: campaign, wantErr: "no longer active"},
+		{name: "per customer limit reached", code: entity.CouponCode{Active: true}, promotion: limited, redemptions: 1, wantErr: "per customer"},
+		{name: "campaign ended", code: entity.CouponCode{Active: true}, promotion: entity.Promotion{Active: true, StartsAt: past, EndsAt: &now}, wantErr: "not available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCouponRedemption(&tt.code, &tt.promotion, tt.redemptions, now)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestEvaluatePromotionsWithCoupon tests that an entered coupon always applies
+func TestEvaluatePromotionsWithCoupon(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	lines := []PromotionLine{{Quantity: 1, UnitPrice: money.New(100000), Total: money.New(100000)}}
+	automatic := []entity.Promotion{
+		{Type: entity.PromotionOrderPercentage, Percentage: 0.2, Active: true, StartsAt: past},
+		{Type: entity.PromotionOrderFixed, Amount: money.New(1000), Stackable: true, Active: true, StartsAt: past},
+	}
+
+	exclusive := &entity.Promotion{Type: entity.PromotionOrderFixed, Amount: money.New(5000), RequiresCode: true, Active: true, StartsAt: past}
+	result := evaluatePromotions(lines, automatic, exclusive, now)
+	if result.Total != money.New(5000) || len(result.Applied) != 1 {
+		t.Errorf("Expected only the coupon discount of 5000.00, got %s from %d promotions", result.Total, len(result.Applied))
+	}
+
+	stackable := &entity.Promotion{Type: entity.PromotionOrderFixed, Amount: money.New(5000), RequiresCode: true, Stackable: true, Active: true, StartsAt: past}
+	result = evaluatePromotions(lines, automatic, stackable, now)
+	if result.Total != money.New(6000) || len(result.Applied) != 2 {
+		t.Errorf("Expected coupon stacked with automatic promotion for 6000.00, got %s from %d promotions", result.Total, len(result.Applied))
+	}
+}
+
+// TestRandomCouponCode tests generated code format
+func TestRandomCouponCode(t *testing.T) {
+	code, err := randomCouponCode("XMAS-")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(code, "XMAS-") || len(code) != len("XMAS-")+generatedCodeLength {
+		t.Errorf("Unexpected code %q", code)
+	}
+	if !couponCodePattern.MatchString(code) {
+		t.Errorf("Generated code %q does not match the code pattern", code)
+	}
+}
@@ -0,0 +1,540 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/email"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// statementPeriodDays is how far back a statement lists payments.
+const statementPeriodDays = 30
+
+// CreditService keeps the accounts of customers who pay later. Like the
+// gift card service, the order methods take the transaction of the order
+// change they belong to.
+type CreditService interface {
+	ApproveAccount(userID uuid.UUID, creditLimit money.Amount, termsDays int, status string) (*entity.CreditAccount, error)
+	FindAllAccounts() ([]entity.CreditAccount, error)
+	RecordPayment(payment *entity.CreditPayment) (*entity.CreditPayment, error)
+	GetAgingReport(asOf time.Time) (*entity.AgingReport, error)
+	GetStatement(userID uuid.UUID, at time.Time) (*entity.CreditStatement, error)
+	EmailStatement(userID uuid.UUID) error
+	EmailAllStatements() (int, error)
+	ChargeOrder(tx *gorm.DB, order *entity.Order, at time.Time) error
+	ReverseOrder(tx *gorm.DB, order *entity.Order) error
+}
+
+type creditService struct {
+	creditRepo     repository.CreditRepository
+	settingService SettingService
+	emailSender    email.EmailSenderService
+	db             *gorm.DB
+}
+
+func NewCreditService(creditRepo repository.CreditRepository, settingService SettingService, emailSender email.EmailSenderService, db *gorm.DB) *creditService {
+	return &creditService{
+		creditRepo:     creditRepo,
+		settingService: settingService,
+		emailSender:    emailSender,
+		db:             db,
+	}
+}
+
+// ApproveAccount opens or updates the credit account of a customer. Lowering
+// the limit below the balance only stops new credit sales.
+func (s *creditService) ApproveAccount(userID uuid.UUID, creditLimit money.Amount, termsDays int, status string) (*entity.CreditAccount, error) {
+	if creditLimit.IsNegative() {
+		return nil, errors.New("credit_limit cannot be negative")
+	}
+	if termsDays < 0 {
+		return nil, errors.New("payment_terms_days cannot be negative")
+	}
+	if status == "" {
+		status = entity.CreditActive
+	}
+	if status != entity.CreditActive && status != entity.CreditSuspended {
+		return nil, errors.New("status must be 'active' or 'suspended'")
+	}
+
+	now := time.Now()
+	account, err := s.creditRepo.FindAccount(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		users, err := s.creditRepo.FindUsersByIDs([]uuid.UUID{userID})
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return nil, errors.New("user not found")
+		}
+		account = &entity.CreditAccount{UserID: userID, ApprovedAt: now, CreatedAt: now}
+	} else if err != nil {
+		return nil, err
+	}
+
+	account.CreditLimit = creditLimit
+	account.PaymentTermsDays = termsDays
+	account.Status = status
+	account.UpdatedAt = now
+	if err := s.creditRepo.SaveAccount(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (s *creditService) FindAllAccounts() ([]entity.CreditAccount, error) {
+	return s.creditRepo.FindAllAccounts()
+}
+
+// RecordPayment takes a payment from a customer and allocates it to their
+// open invoices, oldest first unless payment.Allocations says otherwise.
+// Cash received by a cashier with an open shift goes into their drawer.
+func (s *creditService) RecordPayment(payment *entity.CreditPayment) (*entity.CreditPayment, error) {
+	if payment.Method != entity.CreditPaymentCash && payment.Method != entity.CreditPaymentTransfer {
+		return nil, errors.New("method must be 'cash' or 'transfer'")
+	}
+	if !payment.Amount.IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		account, err := lockCreditAccount(tx, payment.UserID)
+		if err != nil {
+			return err
+		}
+
+		var invoices []entity.CreditInvoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND status = ?", payment.UserID, entity.InvoiceOpen).
+			Order("issued_at ASC").
+			Find(&invoices).Error; err != nil {
+			return err
+		}
+
+		allocations, err := allocateCreditPayment(invoices, payment.Amount, payment.Allocations)
+		if err != nil {
+			return err
+		}
+
+		payment.CreditPaymentID = uuid.New()
+		payment.CreatedAt = now
+		for i := range allocations {
+			allocations[i].CreditAllocationID = uuid.New()
+			allocations[i].CreditPaymentID = payment.CreditPaymentID
+		}
+		payment.Allocations = allocations
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+
+		byID := make(map[uuid.UUID]*entity.CreditInvoice, len(invoices))
+		for i := range invoices {
+			byID[invoices[i].InvoiceID] = &invoices[i]
+		}
+		for _, allocation := range allocations {
+			invoice := byID[allocation.InvoiceID]
+			invoice.PaidAmount = invoice.PaidAmount.Add(allocation.Amount)
+			updates := map[string]interface{}{"paid_amount": invoice.PaidAmount}
+			if invoice.Outstanding().IsZero() {
+				updates["status"] = entity.InvoicePaid
+				updates["paid_at"] = now
+			}
+			if err := tx.Model(&entity.CreditInvoice{}).
+				Where("invoice_id = ?", invoice.InvoiceID).
+				Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&entity.CreditAccount{}).
+			Where("user_id = ?", account.UserID).
+			Updates(map[string]interface{}{
+				"balance":    account.Balance.Sub(payment.Amount),
+				"updated_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		if payment.Method != entity.CreditPaymentCash || payment.ReceivedBy == nil {
+			return nil
+		}
+		shift, err := lockOpenShift(tx, "cashier_id = ?", *payment.ReceivedBy)
+		if errors.Is(err, errNoOpenShift) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, err = addCashMovement(tx, shift, nil, entity.CashPayIn, payment.Amount, "Credit payment "+payment.CreditPaymentID.String())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *creditService) GetAgingReport(asOf time.Time) (*entity.AgingReport, error) {
+	invoices, err := s.creditRepo.FindAllOpenInvoices()
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.creditRepo.FindAllAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uuid.UUID][]entity.CreditInvoice)
+	var userIDs []uuid.UUID
+	for _, invoice := range invoices {
+		if _, ok := byUser[invoice.UserID]; !ok {
+			userIDs = append(userIDs, invoice.UserID)
+		}
+		byUser[invoice.UserID] = append(byUser[invoice.UserID], invoice)
+	}
+
+	limits := make(map[uuid.UUID]money.Amount, len(accounts))
+	for _, account := range accounts {
+		limits[account.UserID] = account.CreditLimit
+	}
+
+	users := make(map[uuid.UUID]entity.User, len(userIDs))
+	if len(userIDs) > 0 {
+		found, err := s.creditRepo.FindUsersByIDs(userIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range found {
+			users[user.UserId] = user
+		}
+	}
+
+	report := &entity.AgingReport{AsOf: asOf, Customers: []entity.CustomerAging{}}
+	var all []entity.CreditInvoice
+	for _, userID := range userIDs {
+		user := users[userID]
+		report.Customers = append(report.Customers, entity.CustomerAging{
+			UserID:       userID,
+			Fullname:     user.Fullname,
+			Email:        user.Email,
+			CreditLimit:  limits[userID],
+			AgingBuckets: ageInvoices(byUser[userID], asOf),
+		})
+		all = append(all, byUser[userID]...)
+	}
+	report.Totals = ageInvoices(all, asOf)
+
+	sort.SliceStable(report.Customers, func(i, j int) bool {
+		return report.Customers[i].Total.GreaterThan(report.Customers[j].Total)
+	})
+	return report, nil
+}
+
+func (s *creditService) GetStatement(userID uuid.UUID, at time.Time) (*entity.CreditStatement, error) {
+	account, err := s.creditRepo.FindAccount(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("credit account not found")
+		}
+		return nil, err
+	}
+
+	invoices, err := s.creditRepo.FindOpenInvoices(userID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.creditRepo.FindPaymentsSince(userID, at.AddDate(0, 0, -statementPeriodDays))
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.creditRepo.FindUsersByIDs([]uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &entity.CreditStatement{
+		Account:       *account,
+		StatementDate: at,
+		Aging:         ageInvoices(invoices, at),
+		OpenInvoices:  invoices,
+		Payments:      payments,
+	}
+	if len(users) > 0 {
+		statement.Fullname = users[0].Fullname
+		statement.Email = users[0].Email
+	}
+	return statement, nil
+}
+
+func (s *creditService) EmailStatement(userID uuid.UUID) error {
+	statement, err := s.GetStatement(userID, time.Now())
+	if err != nil {
+		return err
+	}
+	return s.sendStatement(statement)
+}
+
+// EmailAllStatements sends a statement to every customer who owes money and
+// returns how many were sent. It keeps going when one of them fails.
+func (s *creditService) EmailAllStatements() (int, error) {
+	accounts, err := s.creditRepo.FindAllAccounts()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	sent := 0
+	var errs []error
+	for _, account := range accounts {
+		if !account.Balance.IsPositive() {
+			continue
+		}
+		statement, err := s.GetStatement(account.UserID, now)
+		if err == nil {
+			err = s.sendStatement(statement)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", account.UserID, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+func (s *creditService) sendStatement(statement *entity.CreditStatement) error {
+	if statement.Email == "" {
+		return errors.New("customer has no email address")
+	}
+	if s.emailSender == nil {
+		return errors.New("email sender is not configured")
+	}
+	storeName := s.settingService.StoreName()
+	subject := fmt.Sprintf("Account Statement %s | %s", statement.StatementDate.Format("2006-01-02"), storeName)
+	return s.emailSender.SendEmail([]string{statement.Email}, subject, formatStatement(statement, storeName))
+}
+
+// ChargeOrder puts the credit tender of a new order on the customer's
+// account and issues its invoice. The account row is locked so that
+// concurrent orders cannot together go over the limit.
+func (s *creditService) ChargeOrder(tx *gorm.DB, order *entity.Order, at time.Time) error {
+	var amount money.Amount
+	for _, payment := range order.Payments {
+		if payment.Method == entity.PaymentCredit {
+			amount = amount.Add(payment.Amount)
+		}
+	}
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	account, err := lockCreditAccount(tx, order.UserID)
+	if err != nil {
+		return err
+	}
+	if account.Status != entity.CreditActive {
+		return errors.New("credit account is suspended")
+	}
+	if amount.GreaterThan(account.Available()) {
+		return fmt.Errorf("credit limit exceeded, %s available", account.Available())
+	}
+
+	if err := tx.Model(&entity.CreditAccount{}).
+		Where("user_id = ?", account.UserID).
+		Updates(map[string]interface{}{
+			"balance":    account.Balance.Add(amount),
+			"updated_at": at,
+		}).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&entity.CreditInvoice{
+		InvoiceID: uuid.New(),
+		UserID:    order.UserID,
+		OrderID:   order.OrderID,
+		Amount:    amount,
+		Status:    entity.InvoiceOpen,
+		IssuedAt:  at,
+		DueDate:   at.AddDate(0, 0, account.PaymentTermsDays),
+	}).Error
+}
+
+// ReverseOrder voids the open invoice of a cancelled or refunded order and
+// takes what is still owed on it off the balance. Whatever the customer
+// already paid on it is given back outside of the account.
+func (s *creditService) ReverseOrder(tx *gorm.DB, order *entity.Order) error {
+	var invoice entity.CreditInvoice
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status = ?", order.OrderID, entity.InvoiceOpen).
+		First(&invoice).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	account, err := lockCreditAccount(tx, invoice.UserID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(&entity.CreditAccount{}).
+		Where("user_id = ?", account.UserID).
+		Updates(map[string]interface{}{
+			"balance":    money.Max(account.Balance.Sub(invoice.Outstanding()), money.Zero),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entity.CreditInvoice{}).
+		Where("invoice_id = ?", invoice.InvoiceID).
+		Update("status", entity.InvoiceVoid).Error
+}
+
+func lockCreditAccount(tx *gorm.DB, userID uuid.UUID) (*entity.CreditAccount, error) {
+	var account entity.CreditAccount
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("customer is not approved for credit")
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// allocateCreditPayment spreads a payment over open invoices. Without
+// requested allocations the oldest invoices are paid first. A payment cannot
+// be more than what is owed.
+func allocateCreditPayment(invoices []entity.CreditInvoice, amount money.Amount, requested []entity.CreditAllocation) ([]entity.CreditAllocation, error) {
+	var owed money.Amount
+	for _, invoice := range invoices {
+		owed = owed.Add(invoice.Outstanding())
+	}
+	if amount.GreaterThan(owed) {
+		return nil, fmt.Errorf("payment is more than the %s owed", owed)
+	}
+
+	if len(requested) == 0 {
+		var allocations []entity.CreditAllocation
+		left := amount
+		for _, invoice := range invoices {
+			if !left.IsPositive() {
+				break
+			}
+			part := money.Min(left, invoice.Outstanding())
+			allocations = append(allocations, entity.CreditAllocation{InvoiceID: invoice.InvoiceID, Amount: part})
+			left = left.Sub(part)
+		}
+		return allocations, nil
+	}
+
+	outstanding := make(map[uuid.UUID]money.Amount, len(invoices))
+	for _, invoice := range invoices {
+		outstanding[invoice.InvoiceID] = invoice.Outstanding()
+	}
+
+	var total money.Amount
+	seen := make(map[uuid.UUID]bool, len(requested))
+	for _, allocation := range requested {
+		left, ok := outstanding[allocation.InvoiceID]
+		if !ok {
+			return nil, fmt.Errorf("invoice %s is not open for this customer", allocation.InvoiceID)
+		}
+		if seen[allocation.InvoiceID] {
+			return nil, fmt.Errorf("invoice %s is allocated twice", allocation.InvoiceID)
+		}
+		seen[allocation.InvoiceID] = true
+		if !allocation.Amount.IsPositive() {
+			return nil, errors.New("allocation amount must be greater than zero")
+		}
+		if allocation.Amount.GreaterThan(left) {
+			return nil, fmt.Errorf("allocation is more than the %s left on invoice %s", left, allocation.InvoiceID)
+		}
+		total = total.Add(allocation.Amount)
+	}
+	if total != amount {
+		return nil, fmt.Errorf("allocations add up to %s but the payment is %s", total, amount)
+	}
+
+	allocations := make([]entity.CreditAllocation, len(requested))
+	for i, allocation := range requested {
+		allocations[i] = entity.CreditAllocation{InvoiceID: allocation.InvoiceID, Amount: allocation.Amount}
+	}
+	return allocations, nil
+}
+
+// ageInvoices buckets what is still owed by the number of days since each
+// invoice was issued.
+func ageInvoices(invoices []entity.CreditInvoice, asOf time.Time) entity.AgingBuckets {
+	var buckets entity.AgingBuckets
+	for _, invoice := range invoices {
+		outstanding := invoice.Outstanding()
+		days := int(asOf.Sub(invoice.IssuedAt).Hours() / 24)
+		switch {
+		case days <= 30:
+			buckets.Days0To30 = buckets.Days0To30.Add(outstanding)
+		case days <= 60:
+			buckets.Days31To60 = buckets.Days31To60.Add(outstanding)
+		case days <= 90:
+			buckets.Days61To90 = buckets.Days61To90.Add(outstanding)
+		default:
+			buckets.Over90 = buckets.Over90.Add(outstanding)
+		}
+		buckets.Total = buckets.Total.Add(outstanding)
+	}
+	return buckets
+}
+
+func formatStatement(statement *entity.CreditStatement, storeName string) string {
+	var b strings.Builder
+	account := statement.Account
+
+	fmt.Fprintf(&b, "Dear %s,\n\n", statement.Fullname)
+	fmt.Fprintf(&b, "This is your account statement from %s as of %s.\n\n", storeName, statement.StatementDate.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Credit limit : %s\n", account.CreditLimit)
+	fmt.Fprintf(&b, "Balance due  : %s\n", account.Balance)
+	fmt.Fprintf(&b, "Available    : %s\n\n", account.Available())
+
+	b.WriteString("Open invoices\n")
+	if len(statement.OpenInvoices) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, invoice := range statement.OpenInvoices {
+		overdue := ""
+		if statement.StatementDate.After(invoice.DueDate) {
+			overdue = " OVERDUE"
+		}
+		fmt.Fprintf(&b, "  %s  issued %s  due %s  amount %s  outstanding %s%s\n",
+			invoice.InvoiceID.String()[:8], invoice.IssuedAt.Format("2006-01-02"), invoice.DueDate.Format("2006-01-02"),
+			invoice.Amount, invoice.Outstanding(), overdue)
+	}
+
+	fmt.Fprintf(&b, "\nPayments in the last %d days\n", statementPeriodDays)
+	if len(statement.Payments) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, payment := range statement.Payments {
+		fmt.Fprintf(&b, "  %s  %s  %s %s\n", payment.CreatedAt.Format("2006-01-02"), payment.Method, payment.Amount, payment.Reference)
+	}
+
+	aging := statement.Aging
+	b.WriteString("\nAging (days since invoice)\n")
+	fmt.Fprintf(&b, "  0-30: %s  31-60: %s  61-90: %s  90+: %s\n\n", aging.Days0To30, aging.Days31To60, aging.Days61To90, aging.Over90)
+	fmt.Fprintf(&b, "%s Team", storeName)
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+func openInvoice(amount, paid money.Amount, issuedAt time.Time) entity.CreditInvoice {
+	return entity.CreditInvoice{
+		InvoiceID:  uuid.New(),
+		Amount:     amount,
+		PaidAmount: paid,
+		Status:     entity.InvoiceOpen,
+		IssuedAt:   issuedAt,
+		DueDate:    issuedAt.AddDate(0, 0, 30),
+	}
+}
+
+// TestAllocateCreditPayment tests spreading payments over open invoices
+func TestAllocateCreditPayment(t *testing.T) {
+	now := time.Now()
+	oldest := openInvoice(money.New(100000), money.New(40000), now.AddDate(0, 0, -45))
+	newest := openInvoice(money.New(200000), money.Zero, now.AddDate(0, 0, -5))
+	invoices := []entity.CreditInvoice{oldest, newest}
+
+	allocations, err := allocateCreditPayment(invoices, money.New(100000), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(allocations) != 2 || allocations[0].Amount != money.New(60000) || allocations[1].Amount != money.New(40000) {
+		t.Errorf("Expected the oldest invoice paid off first, got %+v", allocations)
+	}
+
+	allocations, err = allocateCreditPayment(invoices, money.New(50000), []entity.CreditAllocation{
+		{InvoiceID: newest.InvoiceID, Amount: money.New(50000)},
+	})
+	if err != nil || len(allocations) != 1 || allocations[0].InvoiceID != newest.InvoiceID {
+		t.Errorf("Expected the requested allocation, got %+v, %v", allocations, err)
+	}
+
+	tests := []struct {
+		name      string
+		amount    money.Amount
+		requested []entity.CreditAllocation
+		wantErr   string
+	}{
+		{"more than owed", money.New(300000), nil, "more than the 260000.00 owed"},
+		{"unknown invoice", money.New(10000), []entity.CreditAllocation{{InvoiceID: uuid.New(), Amount: money.New(10000)}}, "not open"},
+		{"more than left on invoice", money.New(70000), []entity.CreditAllocation{{InvoiceID: oldest.InvoiceID, Amount: money.New(70000)}}, "left on invoice"},
+		{"allocations do not add up", money.New(70000), []entity.CreditAllocation{{InvoiceID: newest.InvoiceID, Amount: money.New(50000)}}, "add up to"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := allocateCreditPayment(invoices, tt.amount, tt.requested)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestAgeInvoices tests the aging buckets
+func TestAgeInvoices(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	buckets := ageInvoices([]entity.CreditInvoice{
+		openInvoice(money.New(10000), money.Zero, asOf.AddDate(0, 0, -30)),
+		openInvoice(money.New(20000), money.New(5000), asOf.AddDate(0, 0, -31)),
+		openInvoice(money.New(30000), money.Zero, asOf.AddDate(0, 0, -90)),
+		openInvoice(money.New(40000), money.Zero, asOf.AddDate(0, 0, -91)),
+	}, asOf)
+
+	if buckets.Days0To30 != money.New(10000) || buckets.Days31To60 != money.New(15000) {
+		t.Errorf("Expected 10000.00 and 15000.00 in the first buckets, got %s and %s", buckets.Days0To30, buckets.Days31To60)
+	}
+	if buckets.Days61To90 != money.New(30000) || buckets.Over90 != money.New(40000) {
+		t.Errorf("Expected 30000.00 and 40000.00 in the last buckets, got %s and %s", buckets.Days61To90, buckets.Over90)
+	}
+	if buckets.Total != money.New(95000) {
+		t.Errorf("Expected total 95000.00, got %s", buckets.Total)
+	}
+}
+
+// TestCreditTender tests that a credit tender settles without Midtrans
+func TestCreditTender(t *testing.T) {
+	order := &entity.Order{TotalPrice: money.New(150000)}
+	tenders, err := allocateTenders([]entity.OrderPayment{
+		{Method: entity.PaymentCash, Amount: money.New(50000), TenderedAmount: money.New(50000)},
+		{Method: entity.PaymentCredit},
+	}, order.AmountDue())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	settleTenders(order, tenders, time.Now())
+
+	if order.Status != "paid" || !midtransAmount(order).IsZero() {
+		t.Errorf("Expected a paid order without Midtrans, got %s with %s through Midtrans", order.Status, midtransAmount(order))
+	}
+	if tenders[1].Amount != money.New(100000) {
+		t.Errorf("Expected 100000.00 on credit, got %s", tenders[1].Amount)
+	}
+
+	account := entity.CreditAccount{CreditLimit: money.New(100000), Balance: money.New(120000)}
+	if !account.Available().IsZero() {
+		t.Errorf("Expected nothing available over the limit, got %s", account.Available())
+	}
+}
@@ -0,0 +1,462 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Gift card codes are worth money to whoever holds them, so they are longer
+// than coupon codes.
+var giftCardCodePattern = regexp.MustCompile(`^[A-Z0-9-]{8,50}$`)
+
+// GiftCardService keeps gift card balances. Like the loyalty ledger, the
+// order methods take the transaction of the order change they belong to.
+type GiftCardService interface {
+	IssueGiftCard(value money.Amount, code string, expiresAt *time.Time) (*entity.GiftCard, error)
+	TopUpGiftCard(giftCardID uuid.UUID, amount money.Amount, note string) (*entity.GiftCard, error)
+	VoidGiftCard(giftCardID uuid.UUID, reason string) (*entity.GiftCard, error)
+	FindAllGiftCards() ([]entity.GiftCard, error)
+	FindGiftCardByID(giftCardID uuid.UUID) (*entity.GiftCard, error)
+	CheckBalance(code string) (*entity.GiftCardBalance, error)
+	ApplyGiftCard(tx *gorm.DB, order *entity.Order, at time.Time) error
+	CreateSoldGiftCards(tx *gorm.DB, order *entity.Order) error
+	RedeemGiftCard(tx *gorm.DB, order *entity.Order) error
+	ActivateGiftCards(tx *gorm.DB, order *entity.Order, at time.Time) error
+	ReverseGiftCards(tx *gorm.DB, order *entity.Order) error
+}
+
+type giftCardService struct {
+	giftCardRepo   repository.GiftCardRepository
+	settingService SettingService
+	db             *gorm.DB
+}
+
+func NewGiftCardService(giftCardRepo repository.GiftCardRepository, settingService SettingService, db *gorm.DB) *giftCardService {
+	return &giftCardService{
+		giftCardRepo:   giftCardRepo,
+		settingService: settingService,
+		db:             db,
+	}
+}
+
+// IssueGiftCard creates an active card outside of a sale, for example as a
+// compensation. A code is generated when none is given.
+func (s *giftCardService) IssueGiftCard(value money.Amount, code string, expiresAt *time.Time) (*entity.GiftCard, error) {
+	if err := validateGiftCardValue(value); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if code == "" {
+		generated, err := randomGiftCardCode()
+		if err != nil {
+			return nil, err
+		}
+		code = generated
+	}
+	code = normalizeGiftCardCode(code)
+	if !giftCardCodePattern.MatchString(code) {
+		return nil, errors.New("code must be 8-50 letters, digits or dashes")
+	}
+	if expiresAt == nil {
+		expiresAt = s.defaultExpiry(now)
+	} else if !expiresAt.After(now) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	if _, err := s.giftCardRepo.FindGiftCardByCode(code); err == nil {
+		return nil, errors.New("code already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	giftCard := &entity.GiftCard{
+		GiftCardID:   uuid.New(),
+		Code:         code,
+		InitialValue: value,
+		ExpiresAt:    expiresAt,
+		Status:       entity.GiftCardActive,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(giftCard).Error; err != nil {
+			return err
+		}
+		return addGiftCardBalance(tx, giftCard, nil, entity.GiftCardIssue, value, "Issued by admin")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return giftCard, nil
+}
+
+func (s *giftCardService) TopUpGiftCard(giftCardID uuid.UUID, amount money.Amount, note string) (*entity.GiftCard, error) {
+	if err := validateGiftCardValue(amount); err != nil {
+		return nil, err
+	}
+	if note == "" {
+		note = "Top-up"
+	}
+
+	var giftCard *entity.GiftCard
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		giftCard, err = lockGiftCard(tx, "gift_card_id = ?", giftCardID)
+		if err != nil {
+			return err
+		}
+		if !giftCard.IsUsableAt(time.Now()) {
+			return errors.New("only active, unexpired gift cards can be topped up")
+		}
+		return addGiftCardBalance(tx, giftCard, nil, entity.GiftCardTopUp, amount, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return giftCard, nil
+}
+
+// VoidGiftCard writes off the remaining balance. A void card cannot be used
+// or topped up again.
+func (s *giftCardService) VoidGiftCard(giftCardID uuid.UUID, reason string) (*entity.GiftCard, error) {
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	var giftCard *entity.GiftCard
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		giftCard, err = lockGiftCard(tx, "gift_card_id = ?", giftCardID)
+		if err != nil {
+			return err
+		}
+		if giftCard.Status == entity.GiftCardVoid {
+			return errors.New("gift card is already void")
+		}
+		return voidGiftCard(tx, giftCard, nil, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return giftCard, nil
+}
+
+func (s *giftCardService) FindAllGiftCards() ([]entity.GiftCard, error) {
+	return s.giftCardRepo.FindAllGiftCards()
+}
+
+func (s *giftCardService) FindGiftCardByID(giftCardID uuid.UUID) (*entity.GiftCard, error) {
+	giftCard, err := s.giftCardRepo.FindGiftCardByID(giftCardID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("gift card not found")
+	}
+	return giftCard, err
+}
+
+func (s *giftCardService) CheckBalance(code string) (*entity.GiftCardBalance, error) {
+	giftCard, err := s.giftCardRepo.FindGiftCardByCode(normalizeGiftCardCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("gift card not found")
+		}
+		return nil, err
+	}
+
+	return &entity.GiftCardBalance{
+		Code:      giftCard.MaskedCode(),
+		Balance:   giftCard.Balance,
+		ExpiresAt: giftCard.ExpiresAt,
+		Status:    giftCard.Status,
+	}, nil
+}
+
+// ApplyGiftCard decides how much of order.GiftCardCode pays for the order:
+// what is left after points, at most the card balance and the requested limit.
+// The card row is locked until tx ends, so the balance cannot be spent twice.
+func (s *giftCardService) ApplyGiftCard(tx *gorm.DB, order *entity.Order, at time.Time) error {
+	order.GiftCardID = nil
+	order.GiftCardAmount = money.Zero
+	if order.GiftCardCode == "" {
+		return nil
+	}
+	if len(order.GiftCardSales) > 0 {
+		return errors.New("gift cards cannot be bought with a gift card")
+	}
+	if order.GiftCardLimit.IsNegative() {
+		return errors.New("gift_card_amount cannot be negative")
+	}
+
+	giftCard, err := lockGiftCard(tx, "code = ?", normalizeGiftCardCode(order.GiftCardCode))
+	if err != nil {
+		return err
+	}
+	if !giftCard.IsUsableAt(at) {
+		return errors.New("gift card is not active or has expired")
+	}
+
+	if !giftCard.Balance.IsPositive() {
+		return errors.New("gift card has no balance left")
+	}
+	if !order.AmountDue().IsPositive() {
+		return errors.New("nothing is left to pay with the gift card")
+	}
+
+	amount := money.Min(giftCard.Balance, order.AmountDue())
+	if order.GiftCardLimit.IsPositive() {
+		amount = money.Min(amount, order.GiftCardLimit)
+	}
+
+	order.GiftCardID = &giftCard.GiftCardID
+	order.GiftCardAmount = amount
+	return nil
+}
+
+// CreateSoldGiftCards creates a pending card for every gift card line of a
+// new order. Cards become usable once the order is paid.
+func (s *giftCardService) CreateSoldGiftCards(tx *gorm.DB, order *entity.Order) error {
+	for i := range order.OrderItems {
+		item := &order.OrderItems[i]
+		if item.ItemType != entity.OrderItemGiftCard {
+			continue
+		}
+
+		code, err := randomGiftCardCode()
+		if err != nil {
+			return err
+		}
+		giftCard := &entity.GiftCard{
+			GiftCardID:   uuid.New(),
+			Code:         code,
+			InitialValue: item.TotalPrice,
+			Status:       entity.GiftCardPending,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+		if err := tx.Create(giftCard).Error; err != nil {
+			return err
+		}
+		item.GiftCardID = &giftCard.GiftCardID
+		item.GiftCard = giftCard
+	}
+	return nil
+}
+
+func (s *giftCardService) RedeemGiftCard(tx *gorm.DB, order *entity.Order) error {
+	if order.GiftCardID == nil || !order.GiftCardAmount.IsPositive() {
+		return nil
+	}
+
+	giftCard, err := lockGiftCard(tx, "gift_card_id = ?", *order.GiftCardID)
+	if err != nil {
+		return err
+	}
+	return addGiftCardBalance(tx, giftCard, &order.OrderID, entity.GiftCardRedeem, money.Zero.Sub(order.GiftCardAmount), "Paid for order")
+}
+
+// ActivateGiftCards loads the cards sold on a paid order. Validity starts
+// when the card is paid for.
+func (s *giftCardService) ActivateGiftCards(tx *gorm.DB, order *entity.Order, at time.Time) error {
+	if order.Status != "paid" {
+		return nil
+	}
+
+	giftCards, err := lockSoldGiftCards(tx, order.OrderID)
+	if err != nil {
+		return err
+	}
+	for i := range giftCards {
+		giftCard := &giftCards[i]
+		if giftCard.Status != entity.GiftCardPending {
+			continue
+		}
+
+		giftCard.Status = entity.GiftCardActive
+		giftCard.ExpiresAt = s.defaultExpiry(at)
+		if err := tx.Model(&entity.GiftCard{}).
+			Where("gift_card_id = ?", giftCard.GiftCardID).
+			Updates(map[string]interface{}{"status": giftCard.Status, "expires_at": giftCard.ExpiresAt}).Error; err != nil {
+			return err
+		}
+		if err := addGiftCardBalance(tx, giftCard, &order.OrderID, entity.GiftCardIssue, giftCard.InitialValue, "Sold on order"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReverseGiftCards returns what a cancelled or refunded order took from a
+// gift card, and voids the cards it sold. Whatever was already spent from a
+// sold card cannot be taken back.
+func (s *giftCardService) ReverseGiftCards(tx *gorm.DB, order *entity.Order) error {
+	if order.GiftCardID != nil {
+		var transactions []entity.GiftCardTransaction
+		if err := tx.Where("gift_card_id = ? AND order_id = ?", *order.GiftCardID, order.OrderID).
+			Find(&transactions).Error; err != nil {
+			return err
+		}
+
+		var redeemed money.Amount
+		reversed := false
+		for _, transaction := range transactions {
+			switch transaction.Type {
+			case entity.GiftCardRedeem:
+				redeemed = money.Zero.Sub(transaction.Amount)
+			case entity.GiftCardReverseRedeem:
+				reversed = true
+			}
+		}
+
+		if redeemed.IsPositive() && !reversed {
+			giftCard, err := lockGiftCard(tx, "gift_card_id = ?", *order.GiftCardID)
+			if err != nil {
+				return err
+			}
+			if err := addGiftCardBalance(tx, giftCard, &order.OrderID, entity.GiftCardReverseRedeem, redeemed, "Returned from cancelled order"); err != nil {
+				return err
+			}
+		}
+	}
+
+	giftCards, err := lockSoldGiftCards(tx, order.OrderID)
+	if err != nil {
+		return err
+	}
+	for i := range giftCards {
+		if giftCards[i].Status == entity.GiftCardVoid {
+			continue
+		}
+		if err := voidGiftCard(tx, &giftCards[i], &order.OrderID, "Sold on a cancelled order"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *giftCardService) defaultExpiry(from time.Time) *time.Time {
+	days := s.settingService.GiftCardValidityDays()
+	if days <= 0 {
+		return nil
+	}
+	expiresAt := from.AddDate(0, 0, days)
+	return &expiresAt
+}
+
+func lockGiftCard(tx *gorm.DB, query string, args ...interface{}) (*entity.GiftCard, error) {
+	var giftCard entity.GiftCard
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(query, args...).First(&giftCard).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("gift card not found")
+		}
+		return nil, err
+	}
+	return &giftCard, nil
+}
+
+func lockSoldGiftCards(tx *gorm.DB, orderID uuid.UUID) ([]entity.GiftCard, error) {
+	var giftCards []entity.GiftCard
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("gift_card_id IN (?)", tx.Model(&entity.OrderItem{}).
+			Select("gift_card_id").
+			Where("order_id = ? AND item_type = ?", orderID, entity.OrderItemGiftCard)).
+		Find(&giftCards).Error
+	return giftCards, err
+}
+
+func voidGiftCard(tx *gorm.DB, giftCard *entity.GiftCard, orderID *uuid.UUID, reason string) error {
+	if giftCard.Balance.IsPositive() {
+		if err := addGiftCardBalance(tx, giftCard, orderID, entity.GiftCardVoided, money.Zero.Sub(giftCard.Balance), reason); err != nil {
+			return err
+		}
+	}
+	giftCard.Status = entity.GiftCardVoid
+	return tx.Model(&entity.GiftCard{}).
+		Where("gift_card_id = ?", giftCard.GiftCardID).
+		Updates(map[string]interface{}{"status": giftCard.Status, "updated_at": time.Now()}).Error
+}
+
+// addGiftCardBalance moves the balance of a locked card and writes the
+// ledger entry.
+func addGiftCardBalance(tx *gorm.DB, giftCard *entity.GiftCard, orderID *uuid.UUID, transactionType string, amount money.Amount, note string) error {
+	balance := giftCard.Balance.Add(amount)
+	if balance.IsNegative() {
+		return errors.New("insufficient gift card balance")
+	}
+
+	if err := tx.Model(&entity.GiftCard{}).
+		Where("gift_card_id = ?", giftCard.GiftCardID).
+		Updates(map[string]interface{}{"balance": balance, "updated_at": time.Now()}).Error; err != nil {
+		return err
+	}
+	giftCard.Balance = balance
+
+	return tx.Create(&entity.GiftCardTransaction{
+		GiftCardTransactionID: uuid.New(),
+		GiftCardID:            giftCard.GiftCardID,
+		OrderID:               orderID,
+		Type:                  transactionType,
+		Amount:                amount,
+		BalanceAfter:          balance,
+		Note:                  note,
+		CreatedAt:             time.Now(),
+	}).Error
+}
+
+// addGiftCardLines turns the gift cards being bought into order lines. Gift
+// cards are stored value, so they get no discount and no tax.
+func addGiftCardLines(order *entity.Order) error {
+	for _, value := range order.GiftCardSales {
+		if err := validateGiftCardValue(value); err != nil {
+			return err
+		}
+		order.OrderItems = append(order.OrderItems, entity.OrderItem{
+			OrderItemID:  uuid.New(),
+			OrderID:      order.OrderID,
+			ItemType:     entity.OrderItemGiftCard,
+			Quantity:     1,
+			PricePerItem: value,
+			TotalPrice:   value,
+		})
+		order.TotalPrice = order.TotalPrice.Add(value)
+	}
+	return nil
+}
+
+// Gift card values stay in whole rupiah so that they can be charged through Midtrans.
+func validateGiftCardValue(value money.Amount) error {
+	if !value.IsPositive() {
+		return errors.New("gift card value must be greater than 0")
+	}
+	if !value.IsWholeRupiah() {
+		return errors.New("gift card value must be a whole rupiah amount")
+	}
+	return nil
+}
+
+func normalizeGiftCardCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// randomGiftCardCode returns a code like ABCD-EFGH-JKLM-NPQR.
+func randomGiftCardCode() (string, error) {
+	parts := make([]string, 0, 4)
+	for len(parts) < 4 {
+		code, err := randomCouponCode("")
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, code[:4], code[4:])
+	}
+	return strings.Join(parts, "-"), nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// TestAddGiftCardLines tests that sold gift cards become untaxed order lines
+func TestAddGiftCardLines(t *testing.T) {
+	order := &entity.Order{
+		TotalPrice:    money.New(50000),
+		OrderItems:    []entity.OrderItem{{ItemType: entity.OrderItemProduct, TotalPrice: money.New(50000)}},
+		GiftCardSales: []money.Amount{money.New(100000), money.New(250000)},
+	}
+
+	if err := addGiftCardLines(order); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(order.OrderItems) != 3 {
+		t.Fatalf("Expected 3 order lines, got %d", len(order.OrderItems))
+	}
+	if order.TotalPrice != money.New(400000) {
+		t.Errorf("Expected total 400000.00, got %s", order.TotalPrice)
+	}
+	for _, item := range order.OrderItems[1:] {
+		if item.ItemType != entity.OrderItemGiftCard || item.ProductID != nil || !item.TaxAmount.IsZero() {
+			t.Errorf("Unexpected gift card line %+v", item)
+		}
+	}
+
+	invalid := &entity.Order{GiftCardSales: []money.Amount{money.FromSen(1050)}}
+	if err := addGiftCardLines(invalid); err == nil {
+		t.Error("Expected error for a gift card value that is not whole rupiah")
+	}
+}
+
+// TestGiftCardUsable tests status and expiry checks
+func TestGiftCardUsable(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		giftCard entity.GiftCard
+		want     bool
+	}{
+		{name: "active without expiry", giftCard: entity.GiftCard{Status: entity.GiftCardActive}, want: true},
+		{name: "expired", giftCard: entity.GiftCard{Status: entity.GiftCardActive, ExpiresAt: &past}, want: false},
+		{name: "not paid yet", giftCard: entity.GiftCard{Status: entity.GiftCardPending}, want: false},
+		{name: "void", giftCard: entity.GiftCard{Status: entity.GiftCardVoid}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.giftCard.IsUsableAt(now); got != tt.want {
+				t.Errorf("Expected usable %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestRandomGiftCardCode tests generated code format and masking
+func TestRandomGiftCardCode(t *testing.T) {
+	code, err := randomGiftCardCode()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(code) != 19 || !giftCardCodePattern.MatchString(code) {
+		t.Errorf("Unexpected code %q", code)
+	}
+
+	giftCard := entity.GiftCard{Code: code}
+	if masked := giftCard.MaskedCode(); masked != "****"+code[15:] {
+		t.Errorf("Unexpected masked code %q", masked)
+	}
+}
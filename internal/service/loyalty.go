@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoyaltyService keeps the points ledger. The ledger methods take the
+// transaction of the order change they belong to, so points move together
+// with the order or not at all.
+type LoyaltyService interface {
+	GetBalance(userID uuid.UUID) (*entity.LoyaltyBalance, error)
+	GetHistory(userID uuid.UUID) ([]entity.LoyaltyTransaction, error)
+	PointsValue(points int) money.Amount
+	PointsEarned(spent money.Amount) int
+	RedeemPoints(tx *gorm.DB, order *entity.Order) error
+	EarnPoints(tx *gorm.DB, order *entity.Order) error
+	ReversePoints(tx *gorm.DB, order *entity.Order) error
+}
+
+type loyaltyService struct {
+	loyaltyRepo    repository.LoyaltyRepository
+	settingService SettingService
+}
+
+func NewLoyaltyService(loyaltyRepo repository.LoyaltyRepository, settingService SettingService) *loyaltyService {
+	return &loyaltyService{
+		loyaltyRepo:    loyaltyRepo,
+		settingService: settingService,
+	}
+}
+
+func (s *loyaltyService) GetBalance(userID uuid.UUID) (*entity.LoyaltyBalance, error) {
+	points := 0
+	account, err := s.loyaltyRepo.FindAccountByUserID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if account != nil {
+		points = account.PointsBalance
+	}
+
+	return &entity.LoyaltyBalance{
+		UserID:     userID,
+		Points:     points,
+		PointValue: s.settingService.LoyaltyPointValue(),
+		Value:      s.PointsValue(points),
+	}, nil
+}
+
+func (s *loyaltyService) GetHistory(userID uuid.UUID) ([]entity.LoyaltyTransaction, error) {
+	return s.loyaltyRepo.FindTransactionsByUserID(userID)
+}
+
+func (s *loyaltyService) PointsValue(points int) money.Amount {
+	return s.settingService.LoyaltyPointValue().Mul(int64(points))
+}
+
+// PointsEarned rounds down, so only full spend steps earn a point.
+func (s *loyaltyService) PointsEarned(spent money.Amount) int {
+	return pointsForSpend(spent, s.settingService.LoyaltySpendPerPoint())
+}
+
+func (s *loyaltyService) RedeemPoints(tx *gorm.DB, order *entity.Order) error {
+	if order.PointsRedeemed == 0 {
+		return nil
+	}
+	_, err := addLoyaltyPoints(tx, order.UserID, &order.OrderID, entity.LoyaltyRedeem, -order.PointsRedeemed, "Redeemed at checkout")
+	return err
+}
+
+// EarnPoints credits a paid order once, based on what was paid with money.
+func (s *loyaltyService) EarnPoints(tx *gorm.DB, order *entity.Order) error {
+	if order.Status != "paid" {
+		return nil
+	}
+
+	transactions, err := orderLoyaltyTransactions(tx, order.OrderID)
+	if err != nil {
+		return err
+	}
+	if _, earned := transactions[entity.LoyaltyEarn]; earned {
+		return nil
+	}
+
+	// Tips belong to the cashier, so they earn no points
+	points := s.PointsEarned(money.Max(order.AmountDue().Sub(order.TipAmount), money.Zero))
+	_, err = addLoyaltyPoints(tx, order.UserID, &order.OrderID, entity.LoyaltyEarn, points, "Earned from order")
+	return err
+}
+
+// ReversePoints gives back redeemed points and takes back earned points of a
+// cancelled or refunded order. Earned points that were already spent cannot
+// be taken back, so the balance stops at zero.
+func (s *loyaltyService) ReversePoints(tx *gorm.DB, order *entity.Order) error {
+	transactions, err := orderLoyaltyTransactions(tx, order.OrderID)
+	if err != nil {
+		return err
+	}
+
+	if redeem, ok := transactions[entity.LoyaltyRedeem]; ok {
+		if _, reversed := transactions[entity.LoyaltyReverseRedeem]; !reversed {
+			if _, err := addLoyaltyPoints(tx, order.UserID, &order.OrderID, entity.LoyaltyReverseRedeem, -redeem.Points, "Returned from cancelled order"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if earn, ok := transactions[entity.LoyaltyEarn]; ok {
+		if _, reversed := transactions[entity.LoyaltyReverseEarn]; !reversed {
+			account, err := lockLoyaltyAccount(tx, order.UserID)
+			if err != nil {
+				return err
+			}
+			points := earn.Points
+			if points > account.PointsBalance {
+				points = account.PointsBalance
+			}
+			if points == 0 {
+				// The earned points are all spent, but the empty entry still
+				// marks the earn as reversed so it is never taken back later
+				_, err = createLoyaltyTransaction(tx, order.UserID, &order.OrderID, entity.LoyaltyReverseEarn, 0, account.PointsBalance, "Reversed from cancelled order")
+			} else {
+				_, err = addLoyaltyPoints(tx, order.UserID, &order.OrderID, entity.LoyaltyReverseEarn, -points, "Reversed from cancelled order")
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func pointsForSpend(spent, spendPerPoint money.Amount) int {
+	if !spent.IsPositive() || !spendPerPoint.IsPositive() {
+		return 0
+	}
+	return int(spent.Sen() / spendPerPoint.Sen())
+}
+
+func orderLoyaltyTransactions(tx *gorm.DB, orderID uuid.UUID) (map[string]entity.LoyaltyTransaction, error) {
+	var transactions []entity.LoyaltyTransaction
+	if err := tx.Where("order_id = ?", orderID).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]entity.LoyaltyTransaction, len(transactions))
+	for _, transaction := range transactions {
+		byType[transaction.Type] = transaction
+	}
+	return byType, nil
+}
+
+func lockLoyaltyAccount(tx *gorm.DB, userID uuid.UUID) (*entity.LoyaltyAccount, error) {
+	account := entity.LoyaltyAccount{UserID: userID, UpdatedAt: time.Now()}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// addLoyaltyPoints moves the balance and writes the ledger entry. The account
+// row is locked so concurrent orders cannot spend the same points twice.
+func addLoyaltyPoints(tx *gorm.DB, userID uuid.UUID, orderID *uuid.UUID, transactionType string, points int, description string) (*entity.LoyaltyTransaction, error) {
+	if points == 0 {
+		return nil, nil
+	}
+
+	account, err := lockLoyaltyAccount(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := account.PointsBalance + points
+	if balance < 0 {
+		return nil, errors.New("insufficient loyalty points")
+	}
+
+	if err := tx.Model(&entity.LoyaltyAccount{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"points_balance": balance, "updated_at": time.Now()}).Error; err != nil {
+		return nil, err
+	}
+	return createLoyaltyTransaction(tx, userID, orderID, transactionType, points, balance, description)
+}
+
+// createLoyaltyTransaction writes a ledger entry without touching the balance.
+func createLoyaltyTransaction(tx *gorm.DB, userID uuid.UUID, orderID *uuid.UUID, transactionType string, points, balance int, description string) (*entity.LoyaltyTransaction, error) {
+	transaction := &entity.LoyaltyTransaction{
+		LoyaltyTransactionID: uuid.New(),
+		UserID:               userID,
+		OrderID:              orderID,
+		Type:                 transactionType,
+		Points:               points,
+		BalanceAfter:         balance,
+		Description:          description,
+		CreatedAt:            time.Now(),
+	}
+	if err := tx.Create(transaction).Error; err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
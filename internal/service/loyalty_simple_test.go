@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// TestPointsForSpend tests that only full spend steps earn points
+func TestPointsForSpend(t *testing.T) {
+	tests := []struct {
+		name          string
+		spent         money.Amount
+		spendPerPoint money.Amount
+		want          int
+	}{
+		{name: "exact steps", spent: money.New(50000), spendPerPoint: money.New(10000), want: 5},
+		{name: "rounds down", spent: money.New(59999), spendPerPoint: money.New(10000), want: 5},
+		{name: "below one step", spent: money.New(9999), spendPerPoint: money.New(10000), want: 0},
+		{name: "nothing spent", spent: money.Zero, spendPerPoint: money.New(10000), want: 0},
+		{name: "earning disabled", spent: money.New(50000), spendPerPoint: money.Zero, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pointsForSpend(tt.spent, tt.spendPerPoint); got != tt.want {
+				t.Errorf("Expected %d points, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestLoyaltyPointsFromSettings tests earn and redeem rates read from settings
+func TestLoyaltyPointsFromSettings(t *testing.T) {
+	repo := &fakeSettingRepository{values: map[string]string{
+		entity.SettingLoyaltySpendPerPoint: "20000",
+		entity.SettingLoyaltyPointValue:    "250",
+	}}
+	loyalty := NewLoyaltyService(nil, NewSettingService(repo))
+
+	if got := loyalty.PointsEarned(money.New(100000)); got != 5 {
+		t.Errorf("Expected 5 points, got %d", got)
+	}
+	if got := loyalty.PointsValue(40); got != money.New(10000) {
+		t.Errorf("Expected points worth 10000.00, got %s", got)
+	}
+
+	order := &entity.Order{TotalPrice: money.New(50000), PointsAmount: loyalty.PointsValue(40)}
+	if got := order.AmountDue(); got != money.New(40000) {
+		t.Errorf("Expected amount due 40000.00, got %s", got)
+	}
+}
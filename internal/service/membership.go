@@ -0,0 +1,232 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+	"Kevinmajesta/OrderManagementAPI/worker"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MembershipService assigns customers to tiers based on what they spent in
+// the rolling membership window. Tiers are recalculated periodically, so an
+// order is priced with the tier the customer had when it was placed.
+type MembershipService interface {
+	CreateTier(tier *entity.MembershipTier) (*entity.MembershipTier, error)
+	UpdateTier(tier *entity.MembershipTier) (*entity.MembershipTier, error)
+	DeleteTier(tierID uuid.UUID) error
+	FindAllTiers() ([]entity.MembershipTier, error)
+	GetMembership(userID uuid.UUID) (*entity.UserMembership, error)
+	FindTierForUser(userID uuid.UUID) (*entity.MembershipTier, error)
+	RecalculateTiers(at time.Time) error
+}
+
+type membershipService struct {
+	membershipRepo repository.MembershipRepository
+	settingService SettingService
+}
+
+func NewMembershipService(membershipRepo repository.MembershipRepository, settingService SettingService) *membershipService {
+	return &membershipService{
+		membershipRepo: membershipRepo,
+		settingService: settingService,
+	}
+}
+
+func (s *membershipService) CreateTier(tier *entity.MembershipTier) (*entity.MembershipTier, error) {
+	if err := validateMembershipTier(tier); err != nil {
+		return nil, err
+	}
+
+	tier.TierID = uuid.New()
+	tier.CreatedAt = time.Now()
+	tier.UpdatedAt = time.Now()
+	if err := s.membershipRepo.CreateTier(tier); err != nil {
+		return nil, err
+	}
+	return tier, nil
+}
+
+func (s *membershipService) UpdateTier(tier *entity.MembershipTier) (*entity.MembershipTier, error) {
+	existing, err := s.membershipRepo.FindTierByID(tier.TierID)
+	if err != nil {
+		return nil, errors.New("membership tier not found")
+	}
+	if err := validateMembershipTier(tier); err != nil {
+		return nil, err
+	}
+
+	tier.CreatedAt = existing.CreatedAt
+	tier.UpdatedAt = time.Now()
+	if err := s.membershipRepo.UpdateTier(tier); err != nil {
+		return nil, err
+	}
+	return tier, nil
+}
+
+// DeleteTier drops members of the tier to no tier until the next recalculation.
+func (s *membershipService) DeleteTier(tierID uuid.UUID) error {
+	if _, err := s.membershipRepo.FindTierByID(tierID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("membership tier not found")
+		}
+		return err
+	}
+	return s.membershipRepo.DeleteTier(tierID)
+}
+
+func (s *membershipService) FindAllTiers() ([]entity.MembershipTier, error) {
+	return s.membershipRepo.FindAllTiers()
+}
+
+func (s *membershipService) GetMembership(userID uuid.UUID) (*entity.UserMembership, error) {
+	membership, err := s.membershipRepo.FindMembershipByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &entity.UserMembership{UserID: userID}, nil
+	}
+	return membership, err
+}
+
+// FindTierForUser returns nil when the customer has no tier.
+func (s *membershipService) FindTierForUser(userID uuid.UUID) (*entity.MembershipTier, error) {
+	membership, err := s.GetMembership(userID)
+	if err != nil {
+		return nil, err
+	}
+	return membership.Tier, nil
+}
+
+// RecalculateTiers re-evaluates every customer who spent something in the
+// window or already has a membership, and emails those whose tier changed.
+func (s *membershipService) RecalculateTiers(at time.Time) error {
+	tiers, err := s.membershipRepo.FindAllTiers()
+	if err != nil {
+		return err
+	}
+
+	since := at.AddDate(0, 0, -s.settingService.MembershipWindowDays())
+	spend, err := s.membershipRepo.SumSpendByUser(since)
+	if err != nil {
+		return err
+	}
+
+	memberships, err := s.membershipRepo.FindAllMemberships()
+	if err != nil {
+		return err
+	}
+	previous := make(map[uuid.UUID]*uuid.UUID, len(memberships))
+	for _, membership := range memberships {
+		previous[membership.UserID] = membership.TierID
+		if _, ok := spend[membership.UserID]; !ok {
+			spend[membership.UserID] = money.Zero
+		}
+	}
+
+	var changed []uuid.UUID
+	changes := make(map[uuid.UUID]struct{ from, to *entity.MembershipTier })
+	for userID, total := range spend {
+		tier := tierForSpend(tiers, total)
+
+		membership := &entity.UserMembership{
+			UserID:          userID,
+			QualifyingSpend: total,
+			EvaluatedAt:     at,
+		}
+		if tier != nil {
+			membership.TierID = &tier.TierID
+		}
+		if err := s.membershipRepo.SaveMembership(membership); err != nil {
+			return err
+		}
+
+		previousTierID, existed := previous[userID]
+		if sameTier(previousTierID, membership.TierID) || (!existed && tier == nil) {
+			continue
+		}
+		changed = append(changed, userID)
+		changes[userID] = struct{ from, to *entity.MembershipTier }{findTier(tiers, previousTierID), tier}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+	users, err := s.membershipRepo.FindUsersByIDs(changed)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		change := changes[user.UserId]
+		worker.EmailQueue <- worker.EmailJob{
+			Type:    "notification",
+			To:      user.Email,
+			Name:    user.Fullname,
+			Subject: "Membership Update",
+			Body:    tierChangeMessage(user.Fullname, change.from, change.to),
+		}
+	}
+	return nil
+}
+
+// tierForSpend returns the highest tier whose minimum spend is reached, or
+// nil when the spend does not reach any tier.
+func tierForSpend(tiers []entity.MembershipTier, spend money.Amount) *entity.MembershipTier {
+	var best *entity.MembershipTier
+	for i := range tiers {
+		if spend.LessThan(tiers[i].MinSpend) {
+			continue
+		}
+		if best == nil || tiers[i].MinSpend.GreaterThan(best.MinSpend) {
+			best = &tiers[i]
+		}
+	}
+	return best
+}
+
+func findTier(tiers []entity.MembershipTier, tierID *uuid.UUID) *entity.MembershipTier {
+	if tierID == nil {
+		return nil
+	}
+	for i := range tiers {
+		if tiers[i].TierID == *tierID {
+			return &tiers[i]
+		}
+	}
+	return nil
+}
+
+func sameTier(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func tierChangeMessage(name string, from, to *entity.MembershipTier) string {
+	switch {
+	case to == nil:
+		return fmt.Sprintf("Hi %s, your membership tier has ended. Keep shopping with us to reach a tier again.", name)
+	case from == nil || to.MinSpend.GreaterThan(from.MinSpend):
+		return fmt.Sprintf("Hi %s, congratulations! You are now a %s member and get %.2f%% off every order.", name, to.Name, to.DiscountRate*100)
+	default:
+		return fmt.Sprintf("Hi %s, your membership tier has changed to %s. You now get %.2f%% off every order.", name, to.Name, to.DiscountRate*100)
+	}
+}
+
+func validateMembershipTier(tier *entity.MembershipTier) error {
+	if tier.Name == "" {
+		return errors.New("tier name cannot be empty")
+	}
+	if tier.MinSpend.IsNegative() {
+		return errors.New("min_spend cannot be negative")
+	}
+	if tier.DiscountRate < 0 || tier.DiscountRate >= 1 {
+		return errors.New("discount_rate must be at least 0 and less than 1")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// TestTierForSpend tests that the highest reached tier is picked
+func TestTierForSpend(t *testing.T) {
+	tiers := []entity.MembershipTier{
+		{Name: "Gold", MinSpend: money.New(5000000), DiscountRate: 0.05},
+		{Name: "Silver", MinSpend: money.New(1000000), DiscountRate: 0.02},
+		{Name: "Platinum", MinSpend: money.New(20000000), DiscountRate: 0.1},
+	}
+
+	tests := []struct {
+		name  string
+		spend money.Amount
+		want  string
+	}{
+		{name: "below every tier", spend: money.New(999999), want: ""},
+		{name: "exactly the minimum", spend: money.New(1000000), want: "Silver"},
+		{name: "between tiers", spend: money.New(7500000), want: "Gold"},
+		{name: "above every tier", spend: money.New(50000000), want: "Platinum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier := tierForSpend(tiers, tt.spend)
+			got := ""
+			if tier != nil {
+				got = tier.Name
+			}
+			if got != tt.want {
+				t.Errorf("Expected tier %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSameTier tests tier change detection with missing tiers
+func TestSameTier(t *testing.T) {
+	a := uuid.New()
+	b := uuid.New()
+	copyA := a
+
+	if !sameTier(nil, nil) || !sameTier(&a, &copyA) {
+		t.Error("Expected equal tiers to be the same")
+	}
+	if sameTier(&a, nil) || sameTier(nil, &a) || sameTier(&a, &b) {
+		t.Error("Expected different tiers not to be the same")
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModifierService keeps the modifier groups of products, such as size or
+// sugar level, and checks the options chosen for a cart, tab or order line.
+type ModifierService interface {
+	CreateGroup(group *entity.ModifierGroup) error
+	UpdateGroup(group *entity.ModifierGroup) (*entity.ModifierGroup, error)
+	FindAllGroups() ([]entity.ModifierGroup, error)
+	AddOption(option *entity.ModifierOption) error
+	UpdateOption(option *entity.ModifierOption) (*entity.ModifierOption, error)
+	SetProductGroups(productID uuid.UUID, groupIDs []uuid.UUID) ([]entity.ModifierGroup, error)
+	GetProductModifiers(productID uuid.UUID) ([]entity.ModifierGroup, error)
+	ResolveModifiers(productID uuid.UUID, optionIDs []uuid.UUID) ([]entity.OrderItemModifier, error)
+}
+
+type modifierService struct {
+	modifierRepo repository.ModifierRepository
+}
+
+func NewModifierService(modifierRepo repository.ModifierRepository) *modifierService {
+	return &modifierService{modifierRepo: modifierRepo}
+}
+
+// CreateGroup creates a group together with its options.
+func (s *modifierService) CreateGroup(group *entity.ModifierGroup) error {
+	if err := validateModifierGroup(group); err != nil {
+		return err
+	}
+
+	group.ModifierGroupID = uuid.New()
+	for i := range group.Options {
+		if err := validateModifierOption(&group.Options[i]); err != nil {
+			return err
+		}
+		group.Options[i].ModifierOptionID = uuid.New()
+		group.Options[i].ModifierGroupID = group.ModifierGroupID
+		group.Options[i].IsActive = true
+	}
+	return s.modifierRepo.CreateGroup(group)
+}
+
+func (s *modifierService) UpdateGroup(group *entity.ModifierGroup) (*entity.ModifierGroup, error) {
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.modifierRepo.FindGroupByID(group.ModifierGroupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("modifier group not found")
+		}
+		return nil, err
+	}
+
+	existing.Name = group.Name
+	existing.IsRequired = group.IsRequired
+	existing.MinSelect = group.MinSelect
+	existing.MaxSelect = group.MaxSelect
+	if err := s.modifierRepo.SaveGroup(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// validateModifierGroup checks the selection bounds. A required group needs
+// at least one option, and a group with a minimum is required.
+func validateModifierGroup(group *entity.ModifierGroup) error {
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		return errors.New("name is required")
+	}
+	if group.IsRequired && group.MinSelect == 0 {
+		group.MinSelect = 1
+	}
+	if group.MaxSelect == 0 {
+		group.MaxSelect = 1
+	}
+	if group.MinSelect < 0 {
+		return errors.New("min_select cannot be negative")
+	}
+	if group.MaxSelect < group.MinSelect {
+		return errors.New("max_select must be at least min_select")
+	}
+	group.IsRequired = group.MinSelect > 0
+	return nil
+}
+
+func validateModifierOption(option *entity.ModifierOption) error {
+	option.Name = strings.TrimSpace(option.Name)
+	if option.Name == "" {
+		return errors.New("option name is required")
+	}
+	if !option.PriceDelta.IsWholeRupiah() {
+		return errors.New("price_delta must be a whole rupiah amount")
+	}
+	return nil
+}
+
+func (s *modifierService) FindAllGroups() ([]entity.ModifierGroup, error) {
+	return s.modifierRepo.FindAllGroups()
+}
+
+func (s *modifierService) AddOption(option *entity.ModifierOption) error {
+	if err := validateModifierOption(option); err != nil {
+		return err
+	}
+	if _, err := s.modifierRepo.FindGroupByID(option.ModifierGroupID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("modifier group not found")
+		}
+		return err
+	}
+
+	option.ModifierOptionID = uuid.New()
+	option.IsActive = true
+	return s.modifierRepo.CreateOption(option)
+}
+
+// UpdateOption changes an option. Orders already placed keep the name and
+// price they were sold with.
+func (s *modifierService) UpdateOption(option *entity.ModifierOption) (*entity.ModifierOption, error) {
+	if err := validateModifierOption(option); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.modifierRepo.FindOptionByID(option.ModifierOptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("modifier option not found")
+		}
+		return nil, err
+	}
+
+	existing.Name = option.Name
+	existing.PriceDelta = option.PriceDelta
+	existing.IsActive = option.IsActive
+	existing.SortOrder = option.SortOrder
+	if err := s.modifierRepo.SaveOption(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// SetProductGroups attaches groups to a product in the given order,
+// replacing the groups it had.
+func (s *modifierService) SetProductGroups(productID uuid.UUID, groupIDs []uuid.UUID) ([]entity.ModifierGroup, error) {
+	links := make([]entity.ProductModifierGroup, 0, len(groupIDs))
+	seen := make(map[uuid.UUID]bool, len(groupIDs))
+	for i, groupID := range groupIDs {
+		if seen[groupID] {
+			return nil, errors.New("modifier group is listed more than once")
+		}
+		seen[groupID] = true
+
+		if _, err := s.modifierRepo.FindGroupByID(groupID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("modifier group not found")
+			}
+			return nil, err
+		}
+		links = append(links, entity.ProductModifierGroup{ProductID: productID, ModifierGroupID: groupID, SortOrder: i})
+	}
+
+	if err := s.modifierRepo.SetProductGroups(productID, links); err != nil {
+		return nil, err
+	}
+	return s.modifierRepo.FindProductGroups(productID)
+}
+
+// GetProductModifiers lists the groups of a product with the options that
+// can be chosen.
+func (s *modifierService) GetProductModifiers(productID uuid.UUID) ([]entity.ModifierGroup, error) {
+	groups, err := s.modifierRepo.FindProductGroups(productID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		active := groups[i].Options[:0]
+		for _, option := range groups[i].Options {
+			if option.IsActive {
+				active = append(active, option)
+			}
+		}
+		groups[i].Options = active
+	}
+	return groups, nil
+}
+
+// ResolveModifiers checks the options chosen for a product against its
+// groups and copies their names and price deltas.
+func (s *modifierService) ResolveModifiers(productID uuid.UUID, optionIDs []uuid.UUID) ([]entity.OrderItemModifier, error) {
+	groups, err := s.modifierRepo.FindProductGroups(productID)
+	if err != nil {
+		return nil, err
+	}
+	return selectModifiers(groups, optionIDs)
+}
+
+// selectModifiers checks every chosen option belongs to one of the groups,
+// is active and that each group gets between MinSelect and MaxSelect options.
+// The result follows the order of the groups and their options.
+func selectModifiers(groups []entity.ModifierGroup, optionIDs []uuid.UUID) ([]entity.OrderItemModifier, error) {
+	chosen := make(map[uuid.UUID]bool, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if chosen[optionID] {
+			return nil, errors.New("modifier option is chosen more than once")
+		}
+		chosen[optionID] = true
+	}
+
+	var modifiers []entity.OrderItemModifier
+	for _, group := range groups {
+		count := 0
+		for _, option := range group.Options {
+			if !chosen[option.ModifierOptionID] {
+				continue
+			}
+			if !option.IsActive {
+				return nil, fmt.Errorf("%s %s is not available", group.Name, option.Name)
+			}
+			delete(chosen, option.ModifierOptionID)
+			count++
+
+			optionID := option.ModifierOptionID
+			modifiers = append(modifiers, entity.OrderItemModifier{
+				ModifierOptionID: &optionID,
+				GroupName:        group.Name,
+				OptionName:       option.Name,
+				PriceDelta:       option.PriceDelta,
+			})
+		}
+
+		if count < group.MinSelect {
+			return nil, fmt.Errorf("choose at least %d %s", group.MinSelect, group.Name)
+		}
+		if count > group.MaxSelect {
+			return nil, fmt.Errorf("choose at most %d %s", group.MaxSelect, group.Name)
+		}
+	}
+
+	if len(chosen) > 0 {
+		return nil, errors.New("modifier option is not available for this product")
+	}
+	return modifiers, nil
+}
+
+func modifierTotal(modifiers []entity.OrderItemModifier) money.Amount {
+	var total money.Amount
+	for _, modifier := range modifiers {
+		total = total.Add(modifier.PriceDelta)
+	}
+	return total
+}
+
+// modifierKey identifies a product with a set of options, so lines with the
+// same choices can be put together.
+func modifierKey(productID uuid.UUID, optionIDs []uuid.UUID) string {
+	ids := make([]string, 0, len(optionIDs))
+	for _, optionID := range optionIDs {
+		ids = append(ids, optionID.String())
+	}
+	sort.Strings(ids)
+	return productID.String() + "|" + strings.Join(ids, ",")
+}
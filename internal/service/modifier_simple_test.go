@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+func modifierOption(name string, delta int64, active bool) entity.ModifierOption {
+	return entity.ModifierOption{ModifierOptionID: uuid.New(), Name: name, PriceDelta: money.New(delta), IsActive: active}
+}
+
+// TestSelectModifiers tests required groups, selection bounds and inactive options
+func TestSelectModifiers(t *testing.T) {
+	regular, large := modifierOption("Regular", 0, true), modifierOption("Large", 5000, true)
+	extraShot, oatMilk := modifierOption("Extra shot", 4000, true), modifierOption("Oat milk", 6000, false)
+	groups := []entity.ModifierGroup{
+		{Name: "Size", IsRequired: true, MinSelect: 1, MaxSelect: 1, Options: []entity.ModifierOption{regular, large}},
+		{Name: "Add-ons", MinSelect: 0, MaxSelect: 2, Options: []entity.ModifierOption{extraShot, oatMilk}},
+	}
+
+	modifiers, err := selectModifiers(groups, []uuid.UUID{extraShot.ModifierOptionID, large.ModifierOptionID})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(modifiers) != 2 || modifiers[0].OptionName != "Large" || modifiers[1].GroupName != "Add-ons" {
+		t.Errorf("Expected Large then Extra shot, got %+v", modifiers)
+	}
+	if total := modifierTotal(modifiers); total != money.New(9000) {
+		t.Errorf("Expected price delta 9000.00, got %s", total)
+	}
+
+	tests := []struct {
+		name      string
+		optionIDs []uuid.UUID
+	}{
+		{"missing required", []uuid.UUID{extraShot.ModifierOptionID}},
+		{"too many", []uuid.UUID{regular.ModifierOptionID, large.ModifierOptionID}},
+		{"inactive", []uuid.UUID{regular.ModifierOptionID, oatMilk.ModifierOptionID}},
+		{"other product", []uuid.UUID{regular.ModifierOptionID, uuid.New()}},
+		{"duplicate", []uuid.UUID{regular.ModifierOptionID, regular.ModifierOptionID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := selectModifiers(groups, tt.optionIDs); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	if modifiers, err := selectModifiers(nil, nil); err != nil || len(modifiers) != 0 {
+		t.Errorf("Expected no modifiers for a product without groups, got %v, %v", modifiers, err)
+	}
+}
+
+// TestValidateModifierGroup tests how required and the selection bounds go together
+func TestValidateModifierGroup(t *testing.T) {
+	group := &entity.ModifierGroup{Name: "Sugar level", IsRequired: true}
+	if err := validateModifierGroup(group); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if group.MinSelect != 1 || group.MaxSelect != 1 {
+		t.Errorf("Expected a required group to choose exactly 1, got %d..%d", group.MinSelect, group.MaxSelect)
+	}
+
+	group = &entity.ModifierGroup{Name: "Toppings", MinSelect: 2, MaxSelect: 3}
+	if err := validateModifierGroup(group); err != nil || !group.IsRequired {
+		t.Errorf("Expected a group with a minimum to be required, got %v", err)
+	}
+
+	if err := validateModifierGroup(&entity.ModifierGroup{Name: "Size", MinSelect: 2, MaxSelect: 1}); err == nil {
+		t.Error("Expected error when max_select is below min_select")
+	}
+}
+
+// TestModifierKey tests that the order options are chosen in does not matter
+func TestModifierKey(t *testing.T) {
+	productID, first, second := uuid.New(), uuid.New(), uuid.New()
+	if modifierKey(productID, []uuid.UUID{first, second}) != modifierKey(productID, []uuid.UUID{second, first}) {
+		t.Error("Expected the same key for the same options")
+	}
+	if modifierKey(productID, []uuid.UUID{first}) == modifierKey(productID, nil) {
+		t.Error("Expected a different key with other options")
+	}
+}
@@ -4,36 +4,67 @@ import (
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
 	"Kevinmajesta/OrderManagementAPI/internal/repository"
 	"Kevinmajesta/OrderManagementAPI/pkg/midtrans"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
 	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderService interface {
 	CreateOrder(order *entity.Order) error
-	UpdateOrderStatus(orderID uuid.UUID, status string) error
+	QuoteOrder(order *entity.Order) error
+	UpdateOrderStatus(orderID uuid.UUID, status string, cashierID uuid.UUID) error
 	UpdateOrderStatusByOrderID(orderID string, status string) error
 	GetOrderHistory(userID string) ([]entity.Order, error)
+	GetOrderQueue() ([]entity.Order, error)
+	BumpOrder(orderID uuid.UUID, fulfilmentStatus string) (*entity.Order, error)
 }
 
 type orderService struct {
-	repo            repository.OrderRepository
-	db              *gorm.DB
-	midtransService *midtrans.MidtransService
+	repo              repository.OrderRepository
+	taxService        TaxService
+	promotionService  PromotionService
+	couponService     CouponService
+	loyaltyService    LoyaltyService
+	membershipService MembershipService
+	giftCardService   GiftCardService
+	shiftService      ShiftService
+	creditService     CreditService
+	settingService    SettingService
+	modifierService   ModifierService
+	orderEvents       OrderEventService
+	receiptService    ReceiptService
+	db                *gorm.DB
+	midtransService   *midtrans.MidtransService
 }
 
-func NewOrderService(repo repository.OrderRepository, db *gorm.DB, midtransService *midtrans.MidtransService) *orderService {
+func NewOrderService(repo repository.OrderRepository, taxService TaxService, promotionService PromotionService, couponService CouponService, loyaltyService LoyaltyService, membershipService MembershipService, giftCardService GiftCardService, shiftService ShiftService, creditService CreditService, settingService SettingService, modifierService ModifierService, orderEvents OrderEventService, receiptService ReceiptService, db *gorm.DB, midtransService *midtrans.MidtransService) *orderService {
 	return &orderService{
-		repo:            repo,
-		db:              db,
-		midtransService: midtransService,
+		repo:              repo,
+		taxService:        taxService,
+		promotionService:  promotionService,
+		couponService:     couponService,
+		loyaltyService:    loyaltyService,
+		membershipService: membershipService,
+		giftCardService:   giftCardService,
+		shiftService:      shiftService,
+		creditService:     creditService,
+		settingService:    settingService,
+		modifierService:   modifierService,
+		orderEvents:       orderEvents,
+		receiptService:    receiptService,
+		db:                db,
+		midtransService:   midtransService,
 	}
 }
 
 func (s *orderService) CreateOrder(order *entity.Order) error {
-	var totalPrice float64
+	now := time.Now()
 
 	tx := s.db.Begin()
 	if tx.Error != nil {
@@ -45,10 +76,11 @@ func (s *orderService) CreateOrder(order *entity.Order) error {
 		}
 	}()
 
+	products := make([]entity.Products, len(order.OrderItems))
 	for i, item := range order.OrderItems {
 		// Generate UUID untuk OrderItem
 		order.OrderItems[i].OrderItemID = uuid.New()
-		
+
 		var product entity.Products
 		err := tx.Set("gorm:query_option", "FOR UPDATE").
 			Where("product_id = ?", item.ProductID.String()).
@@ -65,48 +97,140 @@ func (s *orderService) CreateOrder(order *entity.Order) error {
 		}
 
 		tx.Model(&product).Update("stock", product.Stock-item.Quantity)
+		products[i] = product
+	}
+	if err := s.resolveItemModifiers(order); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var couponCode *entity.CouponCode
+	var couponPromotion *entity.Promotion
+	if order.CouponCode != "" {
+		var err error
+		couponCode, couponPromotion, err = lockCoupon(tx, order.CouponCode, order.UserID, now)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		order.CouponCode = couponCode.Code
+	}
 
-		order.OrderItems[i].PricePerItem = product.Price
-		order.OrderItems[i].TotalPrice = float64(item.Quantity) * product.Price
-		totalPrice += order.OrderItems[i].TotalPrice
+	// Orders without a payments list pay with payment_method and paid_amount
+	if len(order.Payments) == 0 && order.PaymentMethod != "" &&
+		order.PaymentMethod != entity.PaymentPoints && order.PaymentMethod != entity.PaymentGiftCard {
+		order.Payments = []entity.OrderPayment{{Method: order.PaymentMethod, TenderedAmount: order.PaidAmount}}
 	}
 
 	order.OrderID = uuid.New()
-	order.TotalPrice = totalPrice
-	if order.PaymentMethod == "" {
-		order.PaymentMethod = "midtrans"
+	if err := s.priceOrder(order, products, couponPromotion, now); err != nil {
+		tx.Rollback()
+		return err
 	}
+	if err := s.applyPoints(order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := takeGiftCardTender(order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.giftCardService.ApplyGiftCard(tx, order, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+	amountDue := order.AmountDue()
 
-	switch order.PaymentMethod {
-	case "cash":
-		if order.PaidAmount <= 0 {
+	// The conditional update keeps concurrent orders from going over a usage limit
+	for _, applied := range order.Promotions {
+		result := tx.Model(&entity.Promotion{}).
+			Where("promotion_id = ? AND (usage_limit IS NULL OR usage_count < usage_limit)", applied.PromotionID).
+			UpdateColumn("usage_count", gorm.Expr("usage_count + 1"))
+		if result.Error != nil {
 			tx.Rollback()
-			return errors.New("paid_amount is required for cash payment")
+			return result.Error
 		}
-		if order.PaidAmount < totalPrice {
+		if result.RowsAffected == 0 {
 			tx.Rollback()
-			return errors.New("paid_amount is less than total price")
+			return fmt.Errorf("promotion %s is no longer available", applied.PromotionName)
 		}
-		order.ChangeAmount = order.PaidAmount - totalPrice
-		order.Status = "paid"
-	case "midtrans":
-		order.Status = "pending"
-	default:
+	}
+
+	tenders, err := allocateTenders(order.Payments, amountDue)
+	if err != nil {
 		tx.Rollback()
-		return errors.New("invalid payment_method, use 'cash' or 'midtrans'")
+		return err
 	}
+	settleTenders(order, tenders, now)
 
+	if err := s.giftCardService.CreateSoldGiftCards(tx, order); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	order.FulfilmentStatus = initialFulfilment(order)
 	if err := tx.Create(order).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	if err := s.loyaltyService.RedeemPoints(tx, order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.loyaltyService.EarnPoints(tx, order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.giftCardService.RedeemGiftCard(tx, order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.giftCardService.ActivateGiftCards(tx, order, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.shiftService.RecordSale(tx, order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.creditService.ChargeOrder(tx, order, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := closeTab(tx, order, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if couponCode != nil {
+		if err := redeemCoupon(tx, couponCode, order); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// Commit transaction first
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
 
-	if order.PaymentMethod == "cash" {
+	// The event gets a copy, as the snap token is still to be set on order
+	created := *order
+	s.orderEvents.Publish(entity.OrderEvent{
+		Type:             entity.OrderEventCreated,
+		OrderID:          order.OrderID,
+		Status:           order.Status,
+		FulfilmentStatus: order.FulfilmentStatus,
+		Order:            &created,
+		OccurredAt:       now,
+	})
+	if order.Status == "paid" {
+		s.issueReceipt(order.OrderID)
+	}
+
+	amount := midtransAmount(order)
+	if amount.IsZero() {
 		return nil
 	}
 
@@ -123,7 +247,7 @@ func (s *orderService) CreateOrder(order *entity.Order) error {
 	// Create Midtrans transaction (after order saved)
 	snapResp, errMidtrans := s.midtransService.CreateTransaction(
 		order.OrderID.String(),
-		int64(totalPrice),
+		amount.Rupiah(),
 		user.Fullname,
 		user.Email,
 		user.Phone,
@@ -139,8 +263,355 @@ func (s *orderService) CreateOrder(order *entity.Order) error {
 	return nil
 }
 
-func (s *orderService) UpdateOrderStatus(orderID uuid.UUID, status string) error {
-	return s.repo.UpdateOrderStatus(orderID, status)
+// QuoteOrder prices the order items the same way CreateOrder does, without
+// reserving stock or saving anything.
+func (s *orderService) QuoteOrder(order *entity.Order) error {
+	now := time.Now()
+
+	var couponPromotion *entity.Promotion
+	if order.CouponCode != "" {
+		couponCode, promotion, err := s.couponService.ResolveCoupon(order.CouponCode, order.UserID, now)
+		if err != nil {
+			return err
+		}
+		order.CouponCode = couponCode.Code
+		couponPromotion = promotion
+	}
+
+	products := make([]entity.Products, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		var product entity.Products
+		if err := s.db.Where("product_id = ?", item.ProductID.String()).First(&product).Error; err != nil {
+			return err
+		}
+		products[i] = product
+	}
+	if err := s.resolveItemModifiers(order); err != nil {
+		return err
+	}
+
+	if err := s.priceOrder(order, products, couponPromotion, now); err != nil {
+		return err
+	}
+	if err := s.applyPoints(order); err != nil {
+		return err
+	}
+	if err := s.giftCardService.ApplyGiftCard(s.db, order, now); err != nil {
+		return err
+	}
+
+	if order.PointsRedeemed > 0 {
+		balance, err := s.loyaltyService.GetBalance(order.UserID)
+		if err != nil {
+			return err
+		}
+		if balance.Points < order.PointsRedeemed {
+			return errors.New("insufficient loyalty points")
+		}
+	}
+	return nil
+}
+
+// resolveItemModifiers checks the options chosen for each line against the
+// modifier groups of its product and copies their names and prices onto the
+// line.
+func (s *orderService) resolveItemModifiers(order *entity.Order) error {
+	for i, item := range order.OrderItems {
+		optionIDs := make([]uuid.UUID, 0, len(item.Modifiers))
+		for _, modifier := range item.Modifiers {
+			if modifier.ModifierOptionID != nil {
+				optionIDs = append(optionIDs, *modifier.ModifierOptionID)
+			}
+		}
+
+		modifiers, err := s.modifierService.ResolveModifiers(*item.ProductID, optionIDs)
+		if err != nil {
+			return err
+		}
+		for j := range modifiers {
+			modifiers[j].OrderItemModifierID = uuid.New()
+			modifiers[j].OrderItemID = item.OrderItemID
+		}
+		order.OrderItems[i].Modifiers = modifiers
+	}
+	return nil
+}
+
+// applyPoints values the points the customer wants to redeem. Points pay
+// for the order like a tender, so they do not change the tax.
+func (s *orderService) applyPoints(order *entity.Order) error {
+	if order.PointsRedeemed < 0 {
+		return errors.New("redeem_points cannot be negative")
+	}
+
+	order.PointsAmount = s.loyaltyService.PointsValue(order.PointsRedeemed)
+	if order.PointsAmount.GreaterThan(order.TotalPrice) {
+		return errors.New("redeem_points is worth more than the order total")
+	}
+	return nil
+}
+
+// priceOrder fills in line prices, promotion and membership discounts, tax,
+// service charge and tip. The unit price of a line is the product price with
+// the price deltas of its modifiers. The membership tier discount applies to what is
+// left of each line after promotions, and tax is calculated on each line
+// after all discounts.
+// A coupon campaign, when given, must give a discount on the order.
+func (s *orderService) priceOrder(order *entity.Order, products []entity.Products, coupon *entity.Promotion, now time.Time) error {
+	lines := make([]PromotionLine, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		product := products[i]
+		unitPrice := product.Price.Add(modifierTotal(item.Modifiers))
+		if unitPrice.IsNegative() {
+			return fmt.Errorf("modifiers make the price of %s negative", product.Name)
+		}
+		lines[i] = PromotionLine{
+			ProductID: product.ProductID,
+			Category:  product.Category,
+			Quantity:  item.Quantity,
+			UnitPrice: unitPrice,
+			Total:     unitPrice.Mul(int64(item.Quantity)),
+		}
+	}
+
+	if coupon != nil {
+		var subtotal money.Amount
+		for _, line := range lines {
+			subtotal = subtotal.Add(line.Total)
+		}
+		if subtotal.LessThan(coupon.MinSpend) {
+			return fmt.Errorf("coupon requires a minimum spend of %s", coupon.MinSpend)
+		}
+	}
+
+	promotions, err := s.promotionService.ApplyPromotions(lines, coupon, now)
+	if err != nil {
+		return err
+	}
+
+	if coupon != nil && !promotionApplied(promotions, coupon.PromotionID) {
+		return errors.New("coupon code does not apply to the items in this order")
+	}
+
+	tier, err := s.membershipService.FindTierForUser(order.UserID)
+	if err != nil {
+		return err
+	}
+
+	var totalPrice, taxAmount, tierDiscount, netTotal money.Amount
+	for i := range order.OrderItems {
+		product := products[i]
+
+		rate, err := s.taxService.ResolveRate(product.TaxClassID, now)
+		if err != nil {
+			return err
+		}
+
+		discount := promotions.LineDiscounts[i]
+		if tier != nil {
+			lineTierDiscount := lines[i].Total.Sub(discount).MulRate(tier.DiscountRate).RoundRupiah()
+			tierDiscount = tierDiscount.Add(lineTierDiscount)
+			discount = discount.Add(lineTierDiscount)
+		}
+		lineNet, lineTax, lineGross := s.taxService.CalculateLineTax(lines[i].Total.Sub(discount), rate)
+
+		order.OrderItems[i].ItemType = entity.OrderItemProduct
+		order.OrderItems[i].PricePerItem = lines[i].UnitPrice
+		order.OrderItems[i].TotalPrice = lines[i].Total
+		order.OrderItems[i].DiscountAmount = discount
+		order.OrderItems[i].TaxClassID = product.TaxClassID
+		order.OrderItems[i].TaxRate = rate
+		order.OrderItems[i].TaxAmount = lineTax
+		totalPrice = totalPrice.Add(lineGross)
+		taxAmount = taxAmount.Add(lineTax)
+		netTotal = netTotal.Add(lineNet)
+	}
+
+	for i := range promotions.Applied {
+		promotions.Applied[i].OrderPromotionID = uuid.New()
+		promotions.Applied[i].OrderID = order.OrderID
+	}
+
+	order.TotalPrice = totalPrice
+	order.TaxAmount = taxAmount
+	order.DiscountAmount = promotions.Total.Add(tierDiscount)
+	order.TierDiscountAmount = tierDiscount
+	order.MembershipTierID = nil
+	if tier != nil {
+		order.MembershipTierID = &tier.TierID
+	}
+	order.Promotions = promotions.Applied
+	if err := addServiceChargeAndTip(order, netTotal, s.settingService.ServiceChargeRate()); err != nil {
+		return err
+	}
+	return addGiftCardLines(order)
+}
+
+// addServiceChargeAndTip charges the service charge rate on the product
+// lines after discounts and before tax, and adds the tip the customer gives.
+// Both are whole rupiah so the total can still be charged through Midtrans.
+func addServiceChargeAndTip(order *entity.Order, netTotal money.Amount, rate float64) error {
+	if order.TipAmount.IsNegative() {
+		return errors.New("tip_amount cannot be negative")
+	}
+	if !order.TipAmount.IsWholeRupiah() {
+		return errors.New("tip_amount must be a whole rupiah amount")
+	}
+
+	order.ServiceChargeRate = rate
+	order.ServiceChargeAmount = netTotal.MulRate(rate).RoundRupiah()
+	order.TotalPrice = order.TotalPrice.Add(order.ServiceChargeAmount).Add(order.TipAmount)
+	return nil
+}
+
+func promotionApplied(result *PromotionResult, promotionID uuid.UUID) bool {
+	for _, applied := range result.Applied {
+		if applied.PromotionID == promotionID {
+			return true
+		}
+	}
+	return false
+}
+
+// lockCoupon loads a coupon code and its campaign with row locks, so that
+// concurrent orders redeeming the same code or campaign run one at a time and
+// see each other's redemptions when checking the limits.
+func lockCoupon(tx *gorm.DB, code string, userID uuid.UUID, now time.Time) (*entity.CouponCode, *entity.Promotion, error) {
+	var couponCode entity.CouponCode
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ?", NormalizeCouponCode(code)).
+		First(&couponCode).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("coupon code not found")
+		}
+		return nil, nil, err
+	}
+
+	var promotion entity.Promotion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("promotion_id = ?", couponCode.PromotionID).
+		First(&promotion).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var redemptions int64
+	if err := tx.Model(&entity.CouponRedemption{}).
+		Where("promotion_id = ? AND user_id = ?", promotion.PromotionID, userID).
+		Count(&redemptions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkCouponRedemption(&couponCode, &promotion, redemptions, now); err != nil {
+		return nil, nil, err
+	}
+	return &couponCode, &promotion, nil
+}
+
+func redeemCoupon(tx *gorm.DB, couponCode *entity.CouponCode, order *entity.Order) error {
+	var discount money.Amount
+	for _, applied := range order.Promotions {
+		if applied.PromotionID == couponCode.PromotionID {
+			discount = applied.DiscountAmount
+		}
+	}
+
+	if err := tx.Model(&entity.CouponCode{}).
+		Where("coupon_code_id = ?", couponCode.CouponCodeID).
+		UpdateColumn("redemption_count", gorm.Expr("redemption_count + 1")).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&entity.CouponRedemption{
+		CouponRedemptionID: uuid.New(),
+		CouponCodeID:       couponCode.CouponCodeID,
+		PromotionID:        couponCode.PromotionID,
+		OrderID:            order.OrderID,
+		UserID:             order.UserID,
+		DiscountAmount:     discount,
+		CreatedAt:          time.Now(),
+	}).Error
+}
+
+// UpdateOrderStatus also moves loyalty points and gift card balances: a paid
+// order earns points and activates the gift cards it sold, and a cancelled or
+// refunded one gives back what it redeemed and earned, voids its gift cards and
+// voids its credit invoice. Cash given back is taken out of the drawer of cashierID, which is uuid.Nil
+// for status changes that do not come from a cashier.
+func (s *orderService) UpdateOrderStatus(orderID uuid.UUID, status string, cashierID uuid.UUID) error {
+	var order entity.Order
+	changed := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ?", orderID).
+			First(&order).Error; err != nil {
+			return err
+		}
+		if order.Status == status {
+			return nil
+		}
+		changed = true
+
+		if err := tx.Model(&entity.Order{}).
+			Where("order_id = ?", orderID).
+			Update("status", status).Error; err != nil {
+			return err
+		}
+		order.Status = status
+
+		if status == "cancelled" || status == "refunded" {
+			if err := s.shiftService.RecordRefund(tx, &order, cashierID); err != nil {
+				return err
+			}
+		}
+		if err := updatePaymentStatus(tx, orderID, status, time.Now()); err != nil {
+			return err
+		}
+
+		switch status {
+		case "paid":
+			if err := s.loyaltyService.EarnPoints(tx, &order); err != nil {
+				return err
+			}
+			return s.giftCardService.ActivateGiftCards(tx, &order, time.Now())
+		case "cancelled", "refunded":
+			if err := s.loyaltyService.ReversePoints(tx, &order); err != nil {
+				return err
+			}
+			if err := s.creditService.ReverseOrder(tx, &order); err != nil {
+				return err
+			}
+			return s.giftCardService.ReverseGiftCards(tx, &order)
+		}
+		return nil
+	})
+	if err != nil || !changed {
+		return err
+	}
+
+	s.orderEvents.Publish(entity.OrderEvent{
+		Type:             entity.OrderEventStatus,
+		OrderID:          order.OrderID,
+		Status:           order.Status,
+		FulfilmentStatus: order.FulfilmentStatus,
+		OccurredAt:       time.Now(),
+	})
+	if status == "paid" {
+		s.issueReceipt(orderID)
+	}
+	return nil
+}
+
+// issueReceipt makes and emails the receipt of an order that has just been
+// paid. The payment is already committed, so a failure is only logged and the
+// receipt can still be generated by hand.
+func (s *orderService) issueReceipt(orderID uuid.UUID) {
+	if s.receiptService == nil {
+		return
+	}
+	if _, err := s.receiptService.IssueReceipt(orderID); err != nil {
+		log.Printf("failed to issue receipt for order %s: %v", orderID, err)
+	}
 }
 
 func (s *orderService) UpdateOrderStatusByOrderID(orderID string, status string) error {
@@ -148,9 +619,91 @@ func (s *orderService) UpdateOrderStatusByOrderID(orderID string, status string)
 	if err != nil {
 		return fmt.Errorf("invalid order_id format: %v", err)
 	}
-	return s.repo.UpdateOrderStatus(orderUUID, status)
+	return s.UpdateOrderStatus(orderUUID, status, uuid.Nil)
 }
 
 func (s *orderService) GetOrderHistory(userID string) ([]entity.Order, error) {
 	return s.repo.GetOrderHistoryByUserID(userID)
 }
+
+// GetOrderQueue lists the orders still to be served, oldest first.
+func (s *orderService) GetOrderQueue() ([]entity.Order, error) {
+	return s.repo.FindOrderQueue()
+}
+
+// BumpOrder moves an order forward on the order display.
+func (s *orderService) BumpOrder(orderID uuid.UUID, fulfilmentStatus string) (*entity.Order, error) {
+	var order entity.Order
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ?", orderID).
+			First(&order).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("order not found")
+			}
+			return err
+		}
+		if err := checkFulfilment(&order, fulfilmentStatus); err != nil {
+			return err
+		}
+
+		order.FulfilmentStatus = fulfilmentStatus
+		order.FulfilmentUpdatedAt = &now
+		return tx.Model(&entity.Order{}).
+			Where("order_id = ?", orderID).
+			Updates(map[string]interface{}{
+				"fulfilment_status":     fulfilmentStatus,
+				"fulfilment_updated_at": now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.orderEvents.Publish(entity.OrderEvent{
+		Type:             entity.OrderEventFulfilment,
+		OrderID:          order.OrderID,
+		Status:           order.Status,
+		FulfilmentStatus: order.FulfilmentStatus,
+		OccurredAt:       now,
+	})
+	return &order, nil
+}
+
+// initialFulfilment puts orders with products on the order display. Dine-in
+// orders were made from their kitchen tickets and gift cards need no making.
+func initialFulfilment(order *entity.Order) string {
+	if order.TabID != nil {
+		return entity.FulfilmentServed
+	}
+	for _, item := range order.OrderItems {
+		if item.ItemType == entity.OrderItemProduct {
+			return entity.FulfilmentQueued
+		}
+	}
+	return entity.FulfilmentServed
+}
+
+var fulfilmentSteps = map[string]int{
+	entity.FulfilmentQueued:    0,
+	entity.FulfilmentPreparing: 1,
+	entity.FulfilmentReady:     2,
+	entity.FulfilmentServed:    3,
+}
+
+// checkFulfilment only lets an order move forward, skipping steps if needed.
+// Cancelled and refunded orders are off the display.
+func checkFulfilment(order *entity.Order, fulfilmentStatus string) error {
+	step, ok := fulfilmentSteps[fulfilmentStatus]
+	if !ok || fulfilmentStatus == entity.FulfilmentQueued {
+		return errors.New("fulfilment_status must be preparing, ready or served")
+	}
+	if order.Status == "cancelled" || order.Status == "refunded" {
+		return fmt.Errorf("order is %s", order.Status)
+	}
+	if step <= fulfilmentSteps[order.FulfilmentStatus] {
+		return fmt.Errorf("order is already %s", order.FulfilmentStatus)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"sync"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+)
+
+// orderEventBuffer is how many events a display can fall behind before it is
+// dropped. A dropped display reconnects and loads the queue again.
+const orderEventBuffer = 64
+
+// OrderEventService fans order events out to the order displays connected to
+// this server.
+type OrderEventService interface {
+	Publish(event entity.OrderEvent)
+	Subscribe() (<-chan entity.OrderEvent, func())
+}
+
+type orderEventService struct {
+	mu          sync.Mutex
+	subscribers map[chan entity.OrderEvent]struct{}
+}
+
+func NewOrderEventService() *orderEventService {
+	return &orderEventService{subscribers: make(map[chan entity.OrderEvent]struct{})}
+}
+
+// Publish never blocks. A subscriber whose buffer is full has its channel
+// closed instead of holding up the order that caused the event.
+func (s *orderEventService) Publish(event entity.OrderEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for events := range s.subscribers {
+		select {
+		case events <- event:
+		default:
+			delete(s.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Subscribe returns the events published from now on and a func to stop
+// receiving them.
+func (s *orderEventService) Subscribe() (<-chan entity.OrderEvent, func()) {
+	events := make(chan entity.OrderEvent, orderEventBuffer)
+
+	s.mu.Lock()
+	s.subscribers[events] = struct{}{}
+	s.mu.Unlock()
+
+	return events, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[events]; ok {
+			delete(s.subscribers, events)
+			close(events)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+// TestOrderEventService tests that events reach every subscriber until it unsubscribes
+func TestOrderEventService(t *testing.T) {
+	events := NewOrderEventService()
+	first, unsubscribeFirst := events.Subscribe()
+	second, unsubscribeSecond := events.Subscribe()
+	defer unsubscribeSecond()
+
+	orderID := uuid.New()
+	events.Publish(entity.OrderEvent{Type: entity.OrderEventCreated, OrderID: orderID})
+	for _, subscriber := range []<-chan entity.OrderEvent{first, second} {
+		if event := <-subscriber; event.OrderID != orderID {
+			t.Errorf("Expected event for %s, got %+v", orderID, event)
+		}
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, ok := <-first; ok {
+		t.Error("Expected the channel to be closed after unsubscribing")
+	}
+
+	events.Publish(entity.OrderEvent{Type: entity.OrderEventStatus, OrderID: orderID})
+	if event := <-second; event.Type != entity.OrderEventStatus {
+		t.Errorf("Expected a status event, got %s", event.Type)
+	}
+}
+
+// TestOrderEventServiceSlowSubscriber tests that a subscriber that falls behind is dropped
+func TestOrderEventServiceSlowSubscriber(t *testing.T) {
+	events := NewOrderEventService()
+	slow, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i <= orderEventBuffer; i++ {
+		events.Publish(entity.OrderEvent{Type: entity.OrderEventFulfilment})
+	}
+
+	received := 0
+	for range slow {
+		received++
+	}
+	if received != orderEventBuffer {
+		t.Errorf("Expected %d buffered events before the channel closed, got %d", orderEventBuffer, received)
+	}
+}
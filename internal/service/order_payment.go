@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// takeGiftCardTender moves a requested gift card tender to the gift card
+// fields of the order, which are applied together with loyalty points
+// before the money tenders are allocated.
+func takeGiftCardTender(order *entity.Order) error {
+	tenders := make([]entity.OrderPayment, 0, len(order.Payments))
+	for _, tender := range order.Payments {
+		switch tender.Method {
+		case entity.PaymentGiftCard:
+			if order.GiftCardCode != "" {
+				return errors.New("only one gift card can be used per order")
+			}
+			if tender.GiftCardCode == "" {
+				return errors.New("gift_card_code is required for gift card payment")
+			}
+			order.GiftCardCode = tender.GiftCardCode
+			order.GiftCardLimit = tender.Amount
+		case entity.PaymentPoints:
+			return errors.New("use redeem_points to pay with loyalty points")
+		default:
+			tenders = append(tenders, tender)
+		}
+	}
+	order.Payments = tenders
+	return nil
+}
+
+// allocateTenders splits what is due over the cash, Midtrans and credit
+// tenders of an order. One tender may leave its amount empty to take what the others do
+// not cover, and orders without tenders are paid through Midtrans. Change is
+// only given on the cash tender.
+func allocateTenders(tenders []entity.OrderPayment, due money.Amount) ([]entity.OrderPayment, error) {
+	if !due.IsPositive() {
+		for _, tender := range tenders {
+			if tender.Amount.IsPositive() {
+				return nil, errors.New("nothing is left to pay, remove the extra payments")
+			}
+		}
+		return nil, nil
+	}
+	if len(tenders) == 0 {
+		tenders = []entity.OrderPayment{{Method: entity.PaymentMidtrans}}
+	}
+
+	allocated := make([]entity.OrderPayment, len(tenders))
+	copy(allocated, tenders)
+
+	remainder := -1
+	var fixed money.Amount
+	seen := make(map[string]bool, len(allocated))
+	for i := range allocated {
+		tender := &allocated[i]
+		if tender.Method != entity.PaymentCash && tender.Method != entity.PaymentMidtrans && tender.Method != entity.PaymentCredit {
+			return nil, fmt.Errorf("invalid payment method %q, use 'cash', 'midtrans', 'credit' or 'gift_card'", tender.Method)
+		}
+		if seen[tender.Method] {
+			return nil, fmt.Errorf("only one %s payment is allowed per order", tender.Method)
+		}
+		seen[tender.Method] = true
+
+		if tender.Amount.IsNegative() {
+			return nil, errors.New("payment amount cannot be negative")
+		}
+		if tender.Amount.IsZero() {
+			if remainder >= 0 {
+				return nil, errors.New("only one payment can leave its amount empty")
+			}
+			remainder = i
+			continue
+		}
+		// Cash handed over defaults to the exact amount of the tender
+		if tender.Method == entity.PaymentCash && tender.TenderedAmount.IsZero() {
+			tender.TenderedAmount = tender.Amount
+		}
+		fixed = fixed.Add(tender.Amount)
+	}
+
+	if remainder >= 0 {
+		left := due.Sub(fixed)
+		if !left.IsPositive() {
+			return nil, errors.New("the other payments already cover the order total")
+		}
+		allocated[remainder].Amount = left
+		fixed = due
+	}
+	if fixed != due {
+		return nil, fmt.Errorf("payments add up to %s but %s is due", fixed, due)
+	}
+
+	for i := range allocated {
+		tender := &allocated[i]
+		switch tender.Method {
+		case entity.PaymentCash:
+			if !tender.TenderedAmount.IsPositive() {
+				return nil, errors.New("paid_amount is required for cash payment")
+			}
+			if tender.TenderedAmount.LessThan(tender.Amount) {
+				return nil, errors.New("paid_amount is less than the cash amount")
+			}
+			tender.ChangeAmount = tender.TenderedAmount.Sub(tender.Amount)
+			tender.Status = entity.PaymentSettled
+		case entity.PaymentMidtrans:
+			// Midtrans only settles whole rupiah, so the tender must match exactly
+			if !tender.Amount.IsWholeRupiah() {
+				return nil, errors.New("midtrans amount must be a whole rupiah amount")
+			}
+			tender.TenderedAmount = money.Zero
+			tender.Status = entity.PaymentPending
+		case entity.PaymentCredit:
+			// The store accepts the credit right away, the customer owes it on an invoice
+			tender.TenderedAmount = money.Zero
+			tender.Status = entity.PaymentSettled
+		}
+	}
+	return allocated, nil
+}
+
+// settleTenders records every tender of a new order, including redeemed
+// points and gift card, and sets the order status and payment summary. The
+// order is only paid when no tender is still pending.
+func settleTenders(order *entity.Order, tenders []entity.OrderPayment, now time.Time) {
+	payments := make([]entity.OrderPayment, 0, len(tenders)+2)
+	if order.PointsAmount.IsPositive() {
+		payments = append(payments, entity.OrderPayment{Method: entity.PaymentPoints, Amount: order.PointsAmount, Status: entity.PaymentSettled})
+	}
+	if order.GiftCardAmount.IsPositive() {
+		payments = append(payments, entity.OrderPayment{Method: entity.PaymentGiftCard, Amount: order.GiftCardAmount, Status: entity.PaymentSettled})
+	}
+	payments = append(payments, tenders...)
+
+	order.Status = "paid"
+	order.PaidAmount = money.Zero
+	order.ChangeAmount = money.Zero
+	for i := range payments {
+		payment := &payments[i]
+		payment.OrderPaymentID = uuid.New()
+		payment.OrderID = order.OrderID
+		payment.CreatedAt = now
+		if payment.Status == entity.PaymentSettled {
+			payment.SettledAt = &now
+		} else {
+			order.Status = "pending"
+		}
+		if payment.Method == entity.PaymentCash {
+			order.PaidAmount = payment.TenderedAmount
+			order.ChangeAmount = payment.ChangeAmount
+		}
+	}
+
+	switch len(payments) {
+	case 0:
+		if order.PaymentMethod == "" {
+			order.PaymentMethod = entity.PaymentCash
+		}
+	case 1:
+		order.PaymentMethod = payments[0].Method
+	default:
+		order.PaymentMethod = entity.PaymentSplit
+	}
+	order.Payments = payments
+}
+
+// midtransAmount is what the customer still pays through Midtrans.
+func midtransAmount(order *entity.Order) money.Amount {
+	for _, payment := range order.Payments {
+		if payment.Method == entity.PaymentMidtrans {
+			return payment.Amount
+		}
+	}
+	return money.Zero
+}
+
+// updatePaymentStatus follows an order status change: a paid order settles
+// its pending tenders, and a cancelled or refunded one cancels what is still
+// pending and refunds what was settled.
+func updatePaymentStatus(tx *gorm.DB, orderID uuid.UUID, status string, now time.Time) error {
+	switch status {
+	case "paid":
+		return tx.Model(&entity.OrderPayment{}).
+			Where("order_id = ? AND status = ?", orderID, entity.PaymentPending).
+			Updates(map[string]interface{}{"status": entity.PaymentSettled, "settled_at": now}).Error
+	case "cancelled", "refunded":
+		if err := tx.Model(&entity.OrderPayment{}).
+			Where("order_id = ? AND status = ?", orderID, entity.PaymentPending).
+			Update("status", entity.PaymentCancelled).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.OrderPayment{}).
+			Where("order_id = ? AND status = ?", orderID, entity.PaymentSettled).
+			Update("status", entity.PaymentRefunded).Error
+	}
+	return nil
+}
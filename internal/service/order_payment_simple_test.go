@@ -0,0 +1,129 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// TestAllocateTenders tests splitting the amount due over tenders
+func TestAllocateTenders(t *testing.T) {
+	due := money.New(150000)
+
+	tests := []struct {
+		name       string
+		tenders    []entity.OrderPayment
+		wantErr    string
+		wantAmount []money.Amount
+		wantChange money.Amount
+	}{
+		{
+			name:       "no tenders defaults to midtrans",
+			wantAmount: []money.Amount{due},
+		},
+		{
+			name:       "single cash tender gives change",
+			tenders:    []entity.OrderPayment{{Method: entity.PaymentCash, TenderedAmount: money.New(200000)}},
+			wantAmount: []money.Amount{due},
+			wantChange: money.New(50000),
+		},
+		{
+			name: "change only on the cash portion",
+			tenders: []entity.OrderPayment{
+				{Method: entity.PaymentCash, Amount: money.New(50000), TenderedAmount: money.New(100000)},
+				{Method: entity.PaymentMidtrans},
+			},
+			wantAmount: []money.Amount{money.New(50000), money.New(100000)},
+			wantChange: money.New(50000),
+		},
+		{
+			name: "tenders must add up to the amount due",
+			tenders: []entity.OrderPayment{
+				{Method: entity.PaymentCash, Amount: money.New(50000)},
+				{Method: entity.PaymentMidtrans, Amount: money.New(50000)},
+			},
+			wantErr: "add up to",
+		},
+		{
+			name:    "cash needs paid amount",
+			tenders: []entity.OrderPayment{{Method: entity.PaymentCash}},
+			wantErr: "paid_amount is required",
+		},
+		{
+			name:    "cash handed over below the cash amount",
+			tenders: []entity.OrderPayment{{Method: entity.PaymentCash, TenderedAmount: money.New(100000)}},
+			wantErr: "less than",
+		},
+		{
+			name:    "one tender per method",
+			tenders: []entity.OrderPayment{{Method: entity.PaymentMidtrans, Amount: money.New(100000)}, {Method: entity.PaymentMidtrans}},
+			wantErr: "only one midtrans",
+		},
+		{
+			name:    "unknown method",
+			tenders: []entity.OrderPayment{{Method: "card"}},
+			wantErr: "invalid payment method",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenders, err := allocateTenders(tt.tenders, due)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(tenders) != len(tt.wantAmount) {
+				t.Fatalf("Expected %d tenders, got %d", len(tt.wantAmount), len(tenders))
+			}
+			for i, want := range tt.wantAmount {
+				if tenders[i].Amount != want {
+					t.Errorf("Expected tender %d amount %s, got %s", i, want, tenders[i].Amount)
+				}
+				if tenders[i].Method == entity.PaymentCash && tenders[i].ChangeAmount != tt.wantChange {
+					t.Errorf("Expected change %s, got %s", tt.wantChange, tenders[i].ChangeAmount)
+				}
+			}
+		})
+	}
+}
+
+// TestSettleTenders tests that an order is only paid when every tender settles
+func TestSettleTenders(t *testing.T) {
+	now := time.Now()
+	order := &entity.Order{TotalPrice: money.New(150000), PointsAmount: money.New(10000)}
+
+	tenders, err := allocateTenders([]entity.OrderPayment{
+		{Method: entity.PaymentCash, Amount: money.New(40000), TenderedAmount: money.New(50000)},
+		{Method: entity.PaymentMidtrans},
+	}, order.AmountDue())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	settleTenders(order, tenders, now)
+
+	if order.Status != "pending" || order.PaymentMethod != entity.PaymentSplit {
+		t.Errorf("Expected pending split order, got %s %s", order.Status, order.PaymentMethod)
+	}
+	if len(order.Payments) != 3 || midtransAmount(order) != money.New(100000) {
+		t.Errorf("Expected points, cash and midtrans tenders with 100000.00 through midtrans, got %d tenders and %s", len(order.Payments), midtransAmount(order))
+	}
+	if order.PaidAmount != money.New(50000) || order.ChangeAmount != money.New(10000) {
+		t.Errorf("Expected paid 50000.00 with change 10000.00, got %s and %s", order.PaidAmount, order.ChangeAmount)
+	}
+
+	cashOnly := &entity.Order{TotalPrice: money.New(20000)}
+	tenders, _ = allocateTenders([]entity.OrderPayment{{Method: entity.PaymentCash, TenderedAmount: money.New(20000)}}, cashOnly.AmountDue())
+	settleTenders(cashOnly, tenders, now)
+	if cashOnly.Status != "paid" || cashOnly.PaymentMethod != entity.PaymentCash {
+		t.Errorf("Expected paid cash order, got %s %s", cashOnly.Status, cashOnly.PaymentMethod)
+	}
+}
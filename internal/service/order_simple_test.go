@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
 
 	"github.com/google/uuid"
 )
@@ -23,7 +24,7 @@ func TestOrderValidation(t *testing.T) {
 				OrderID:    uuid.New(),
 				UserID:     uuid.New(),
 				Status:     "pending",
-				TotalPrice: 299.99,
+				TotalPrice: money.FromFloat(299.99),
 				CreatedAt:  time.Now(),
 			},
 			wantErr: false,
@@ -34,7 +35,7 @@ func TestOrderValidation(t *testing.T) {
 				OrderID:    uuid.New(),
 				UserID:     uuid.New(),
 				Status:     "pending",
-				TotalPrice: -50.00,
+				TotalPrice: money.FromFloat(-50.00),
 				CreatedAt:  time.Now(),
 			},
 			wantErr:  true,
@@ -46,7 +47,7 @@ func TestOrderValidation(t *testing.T) {
 				OrderID:    uuid.New(),
 				UserID:     uuid.New(),
 				Status:     "invalid_status",
-				TotalPrice: 299.99,
+				TotalPrice: money.FromFloat(299.99),
 				CreatedAt:  time.Now(),
 			},
 			wantErr:  true,
@@ -57,7 +58,7 @@ func TestOrderValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Validate order
-			if tt.order.TotalPrice < 0 && tt.wantErr {
+			if tt.order.TotalPrice.IsNegative() && tt.wantErr {
 				t.Log("Total price validation: PASS (negative amount caught)")
 			}
 			if tt.order.Status != "pending" && tt.order.Status != "processing" && tt.order.Status != "shipped" && tt.order.Status != "delivered" && tt.wantErr {
@@ -75,7 +76,7 @@ func TestOrderFields(t *testing.T) {
 			OrderID:    uuid.New(),
 			UserID:     uuid.New(),
 			Status:     "pending",
-			TotalPrice: 299.99,
+			TotalPrice: money.FromFloat(299.99),
 			CreatedAt:  now,
 			UpdatedAt:  now,
 		}
@@ -86,7 +87,7 @@ func TestOrderFields(t *testing.T) {
 		if order.UserID == uuid.Nil {
 			t.Error("User ID should not be nil")
 		}
-		if order.TotalPrice <= 0 {
+		if !order.TotalPrice.IsPositive() {
 			t.Error("Total price should be positive")
 		}
 		if order.Status == "" {
@@ -106,7 +107,7 @@ func TestOrderStatus(t *testing.T) {
 				OrderID:    uuid.New(),
 				UserID:     uuid.New(),
 				Status:     status,
-				TotalPrice: 100.00,
+				TotalPrice: money.FromFloat(100.00),
 				CreatedAt:  time.Now(),
 			}
 
@@ -127,13 +128,13 @@ func TestOrderPrice(t *testing.T) {
 			order := &entity.Order{
 				OrderID:    uuid.New(),
 				UserID:     uuid.New(),
-				TotalPrice: price,
+				TotalPrice: money.FromFloat(price),
 				Status:     "pending",
 				CreatedAt:  time.Now(),
 			}
 
-			if order.TotalPrice != price {
-				t.Errorf("Expected price %.2f, got %.2f", price, order.TotalPrice)
+			if order.TotalPrice != money.FromFloat(price) {
+				t.Errorf("Expected price %.2f, got %s", price, order.TotalPrice)
 			}
 			t.Logf("Price %.2f assigned successfully", price)
 		})
@@ -147,20 +148,20 @@ func TestOrderUpdate(t *testing.T) {
 			OrderID:    uuid.New(),
 			UserID:     uuid.New(),
 			Status:     "pending",
-			TotalPrice: 299.99,
+			TotalPrice: money.FromFloat(299.99),
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		}
 
 		// Update fields
 		order.Status = "shipped"
-		order.TotalPrice = 350.00
+		order.TotalPrice = money.FromFloat(350.00)
 		order.UpdatedAt = time.Now()
 
 		if order.Status != "shipped" {
 			t.Error("Status should be updated")
 		}
-		if order.TotalPrice != 350.00 {
+		if order.TotalPrice != money.FromFloat(350.00) {
 			t.Error("Total price should be updated")
 		}
 		t.Log("Order updated successfully")
@@ -170,29 +171,31 @@ func TestOrderUpdate(t *testing.T) {
 // TestOrderItems tests order items functionality
 func TestOrderItems(t *testing.T) {
 	t.Run("order can have multiple items", func(t *testing.T) {
+		firstProductID := uuid.New()
+		secondProductID := uuid.New()
 		order := &entity.Order{
 			OrderID:    uuid.New(),
 			UserID:     uuid.New(),
 			Status:     "pending",
-			TotalPrice: 299.99,
+			TotalPrice: money.FromFloat(299.99),
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 			OrderItems: []entity.OrderItem{
 				{
 					OrderItemID:  uuid.New(),
 					OrderID:      uuid.New(),
-					ProductID:    uuid.New(),
+					ProductID:    &firstProductID,
 					Quantity:     2,
-					PricePerItem: 50.00,
-					TotalPrice:   100.00,
+					PricePerItem: money.FromFloat(50.00),
+					TotalPrice:   money.FromFloat(100.00),
 				},
 				{
 					OrderItemID:  uuid.New(),
 					OrderID:      uuid.New(),
-					ProductID:    uuid.New(),
+					ProductID:    &secondProductID,
 					Quantity:     3,
-					PricePerItem: 66.66,
-					TotalPrice:   199.98,
+					PricePerItem: money.FromFloat(66.66),
+					TotalPrice:   money.FromFloat(199.98),
 				},
 			},
 		}
@@ -203,3 +206,70 @@ func TestOrderItems(t *testing.T) {
 		t.Logf("Order created with %d items successfully", len(order.OrderItems))
 	})
 }
+
+// TestAddServiceChargeAndTip tests the service charge on the net amount and tip validation
+func TestAddServiceChargeAndTip(t *testing.T) {
+	order := &entity.Order{TotalPrice: money.New(111000), TipAmount: money.New(5000)}
+
+	if err := addServiceChargeAndTip(order, money.FromFloat(100000.50), 0.05); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if order.ServiceChargeAmount != money.New(5000) {
+		t.Errorf("Expected service charge 5000.00, got %s", order.ServiceChargeAmount)
+	}
+	if order.TotalPrice != money.New(121000) {
+		t.Errorf("Expected total 121000.00, got %s", order.TotalPrice)
+	}
+
+	if err := addServiceChargeAndTip(&entity.Order{TipAmount: money.New(-1000)}, money.Zero, 0); err == nil {
+		t.Error("Expected error for a negative tip")
+	}
+	if err := addServiceChargeAndTip(&entity.Order{TipAmount: money.FromSen(150)}, money.Zero, 0); err == nil {
+		t.Error("Expected error for a tip that is not whole rupiah")
+	}
+}
+
+// TestCheckFulfilment tests that orders only move forward on the order display
+func TestCheckFulfilment(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   entity.Order
+		target  string
+		wantErr bool
+	}{
+		{"queued to preparing", entity.Order{Status: "paid", FulfilmentStatus: entity.FulfilmentQueued}, entity.FulfilmentPreparing, false},
+		{"skip to ready", entity.Order{Status: "pending", FulfilmentStatus: entity.FulfilmentQueued}, entity.FulfilmentReady, false},
+		{"ready to served", entity.Order{Status: "paid", FulfilmentStatus: entity.FulfilmentReady}, entity.FulfilmentServed, false},
+		{"back to preparing", entity.Order{Status: "paid", FulfilmentStatus: entity.FulfilmentReady}, entity.FulfilmentPreparing, true},
+		{"same step", entity.Order{Status: "paid", FulfilmentStatus: entity.FulfilmentPreparing}, entity.FulfilmentPreparing, true},
+		{"back to queued", entity.Order{Status: "paid", FulfilmentStatus: entity.FulfilmentPreparing}, entity.FulfilmentQueued, true},
+		{"unknown step", entity.Order{Status: "paid", FulfilmentStatus: entity.FulfilmentQueued}, "cooking", true},
+		{"cancelled order", entity.Order{Status: "cancelled", FulfilmentStatus: entity.FulfilmentQueued}, entity.FulfilmentPreparing, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFulfilment(&tt.order, tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestInitialFulfilment tests which new orders go on the order display
+func TestInitialFulfilment(t *testing.T) {
+	tabID := uuid.New()
+	product := []entity.OrderItem{{ItemType: entity.OrderItemProduct}}
+	giftCard := []entity.OrderItem{{ItemType: entity.OrderItemGiftCard}}
+
+	if got := initialFulfilment(&entity.Order{OrderItems: product}); got != entity.FulfilmentQueued {
+		t.Errorf("Expected a product order to be queued, got %s", got)
+	}
+	if got := initialFulfilment(&entity.Order{OrderItems: giftCard}); got != entity.FulfilmentServed {
+		t.Errorf("Expected a gift card order to be served, got %s", got)
+	}
+	if got := initialFulfilment(&entity.Order{TabID: &tabID, OrderItems: product}); got != entity.FulfilmentServed {
+		t.Errorf("Expected a tab order to be served, got %s", got)
+	}
+}
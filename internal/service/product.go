@@ -39,10 +39,14 @@ func (s *productService) CreateProduct(product *entity.Products) (*entity.Produc
 		return nil, errors.New("Photo URL cannot be empty")
 	}
 
-	if product.Price <= 0 {
+	if !product.Price.IsPositive() {
 		return nil, errors.New("Price must be greater than 0")
 	}
 
+	if !product.Price.IsWholeRupiah() {
+		return nil, errors.New("Price must be a whole rupiah amount")
+	}
+
 	if product.Stock <= 0 {
 		return nil, errors.New("Stock must be greater than 0")
 	}
@@ -54,6 +58,8 @@ func (s *productService) CreateProduct(product *entity.Products) (*entity.Produc
 		product.Price,
 		product.Stock,
 	)
+	newProduct.Category = product.Category
+	newProduct.TaxClassID = product.TaxClassID
 
 	savedProduct, err := s.productRepository.CreateProduct(newProduct)
 	if err != nil {
@@ -72,10 +78,14 @@ func (s *productService) UpdateProduct(product *entity.Products) (*entity.Produc
 		return nil, errors.New("Product description cannot be empty")
 	}
 
-	if product.Price <= 0 {
+	if !product.Price.IsPositive() {
 		return nil, errors.New("Price must be greater than 0")
 	}
 
+	if !product.Price.IsWholeRupiah() {
+		return nil, errors.New("Price must be a whole rupiah amount")
+	}
+
 	if product.Stock <= 0 {
 		return nil, errors.New("Stock must be greater than 0")
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
 
 	"github.com/google/uuid"
 )
@@ -22,7 +23,7 @@ func TestProductValidation(t *testing.T) {
 				Name:        "Laptop",
 				Description: "High-performance laptop",
 				PhotoURL:    "https://example.com/laptop.jpg",
-				Price:       999.99,
+				Price:       money.FromFloat(999.99),
 				Stock:       10,
 			},
 			wantErr: false,
@@ -33,7 +34,7 @@ func TestProductValidation(t *testing.T) {
 				Name:        "Product",
 				Description: "Description",
 				PhotoURL:    "https://example.com/photo.jpg",
-				Price:       -50.00,
+				Price:       money.FromFloat(-50.00),
 				Stock:       5,
 			},
 			wantErr:  true,
@@ -45,7 +46,7 @@ func TestProductValidation(t *testing.T) {
 				Name:        "Product",
 				Description: "Description",
 				PhotoURL:    "https://example.com/photo.jpg",
-				Price:       99.99,
+				Price:       money.FromFloat(99.99),
 				Stock:       0,
 			},
 			wantErr:  true,
@@ -57,7 +58,7 @@ func TestProductValidation(t *testing.T) {
 				Name:        "Product",
 				Description: "Description",
 				PhotoURL:    "https://example.com/photo.jpg",
-				Price:       99.99,
+				Price:       money.FromFloat(99.99),
 				Stock:       -10,
 			},
 			wantErr:  true,
@@ -68,7 +69,7 @@ func TestProductValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Validate product
-			if tt.product.Price <= 0 && tt.wantErr {
+			if !tt.product.Price.IsPositive() && tt.wantErr {
 				t.Log("Price validation: PASS (invalid price caught)")
 			}
 			if tt.product.Stock <= 0 && tt.wantErr {
@@ -86,7 +87,7 @@ func TestProductFields(t *testing.T) {
 			Name:        "Laptop",
 			Description: "High-performance laptop",
 			PhotoURL:    "https://example.com/laptop.jpg",
-			Price:       999.99,
+			Price:       money.FromFloat(999.99),
 			Stock:       10,
 		}
 
@@ -96,7 +97,7 @@ func TestProductFields(t *testing.T) {
 		if product.Name == "" {
 			t.Error("Name should not be empty")
 		}
-		if product.Price <= 0 {
+		if !product.Price.IsPositive() {
 			t.Error("Price should be positive")
 		}
 		if product.Stock <= 0 {
@@ -115,12 +116,12 @@ func TestProductPriceRange(t *testing.T) {
 			product := &entity.Products{
 				ProductID: uuid.New(),
 				Name:      "Test Product",
-				Price:     price,
+				Price:     money.FromFloat(price),
 				Stock:     10,
 			}
 
-			if product.Price != price {
-				t.Errorf("Expected price %f, got %f", price, product.Price)
+			if product.Price != money.FromFloat(price) {
+				t.Errorf("Expected price %f, got %s", price, product.Price)
 			}
 			t.Logf("Price %.2f assigned successfully", price)
 		})
@@ -136,7 +137,7 @@ func TestProductStockRange(t *testing.T) {
 			product := &entity.Products{
 				ProductID: uuid.New(),
 				Name:      "Test Product",
-				Price:     99.99,
+				Price:     money.FromFloat(99.99),
 				Stock:     stock,
 			}
 
@@ -155,20 +156,20 @@ func TestProductUpdate(t *testing.T) {
 			ProductID:   uuid.New(),
 			Name:        "Original Name",
 			Description: "Original description",
-			Price:       99.99,
+			Price:       money.FromFloat(99.99),
 			Stock:       10,
 		}
 
 		// Update fields
 		product.Name = "Updated Name"
 		product.Description = "Updated description"
-		product.Price = 149.99
+		product.Price = money.FromFloat(149.99)
 		product.Stock = 20
 
 		if product.Name != "Updated Name" {
 			t.Error("Name should be updated")
 		}
-		if product.Price != 149.99 {
+		if product.Price != money.FromFloat(149.99) {
 			t.Error("Price should be updated")
 		}
 		if product.Stock != 20 {
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PromotionService interface {
+	CreatePromotion(promotion *entity.Promotion) (*entity.Promotion, error)
+	UpdatePromotion(promotion *entity.Promotion) (*entity.Promotion, error)
+	DeletePromotion(promotionID uuid.UUID) error
+	FindAllPromotions() ([]entity.Promotion, error)
+	FindPromotionByID(promotionID uuid.UUID) (*entity.Promotion, error)
+	ApplyPromotions(lines []PromotionLine, coupon *entity.Promotion, at time.Time) (*PromotionResult, error)
+}
+
+// PromotionLine is the part of an order line that promotions are evaluated on.
+// Total is the line amount before any discount.
+type PromotionLine struct {
+	ProductID uuid.UUID
+	Category  string
+	Quantity  int
+	UnitPrice money.Amount
+	Total     money.Amount
+}
+
+// PromotionResult holds the discount of each line, in the same order as the
+// evaluated lines, and the promotions that produced them.
+type PromotionResult struct {
+	LineDiscounts []money.Amount
+	Applied       []entity.OrderPromotion
+	Total         money.Amount
+}
+
+type promotionService struct {
+	promotionRepo repository.PromotionRepository
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepository) *promotionService {
+	return &promotionService{
+		promotionRepo: promotionRepo,
+	}
+}
+
+func (s *promotionService) CreatePromotion(promotion *entity.Promotion) (*entity.Promotion, error) {
+	if err := validatePromotion(promotion); err != nil {
+		return nil, err
+	}
+
+	promotion.PromotionID = uuid.New()
+	promotion.UsageCount = 0
+	promotion.CreatedAt = time.Now()
+	promotion.UpdatedAt = time.Now()
+	if err := s.promotionRepo.CreatePromotion(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *promotionService) UpdatePromotion(promotion *entity.Promotion) (*entity.Promotion, error) {
+	existing, err := s.promotionRepo.FindPromotionByID(promotion.PromotionID)
+	if err != nil {
+		return nil, errors.New("promotion not found")
+	}
+	if err := validatePromotion(promotion); err != nil {
+		return nil, err
+	}
+	if promotion.UsageLimit != nil && *promotion.UsageLimit < existing.UsageCount {
+		return nil, errors.New("usage_limit cannot be lower than the current usage count")
+	}
+
+	promotion.UsageCount = existing.UsageCount
+	promotion.CreatedAt = existing.CreatedAt
+	promotion.UpdatedAt = time.Now()
+	if err := s.promotionRepo.UpdatePromotion(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// DeletePromotion only removes promotions that were never used, so discounts
+// on past orders keep pointing to their promotion. Used ones can be deactivated.
+func (s *promotionService) DeletePromotion(promotionID uuid.UUID) error {
+	promotion, err := s.promotionRepo.FindPromotionByID(promotionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("promotion not found")
+		}
+		return err
+	}
+	if promotion.UsageCount > 0 {
+		return errors.New("promotion has been used, deactivate it instead")
+	}
+	return s.promotionRepo.DeletePromotion(promotionID)
+}
+
+func (s *promotionService) FindAllPromotions() ([]entity.Promotion, error) {
+	return s.promotionRepo.FindAllPromotions()
+}
+
+func (s *promotionService) FindPromotionByID(promotionID uuid.UUID) (*entity.Promotion, error) {
+	return s.promotionRepo.FindPromotionByID(promotionID)
+}
+
+// ApplyPromotions evaluates the automatic promotions together with the
+// campaign of a redeemed coupon code, if any.
+func (s *promotionService) ApplyPromotions(lines []PromotionLine, coupon *entity.Promotion, at time.Time) (*PromotionResult, error) {
+	promotions, err := s.promotionRepo.FindAvailablePromotions(at)
+	if err != nil {
+		return nil, err
+	}
+	return evaluatePromotions(lines, promotions, coupon, at), nil
+}
+
+// evaluatePromotions picks the best discount for the lines. All eligible
+// stackable promotions are applied together, a non-stackable promotion is
+// applied on its own, and whichever gives the larger discount wins.
+//
+// A coupon campaign the customer entered a code for always applies: a
+// stackable one joins the stackable promotions, a non-stackable one is used alone.
+func evaluatePromotions(lines []PromotionLine, promotions []entity.Promotion, coupon *entity.Promotion, at time.Time) *PromotionResult {
+	var subtotal money.Amount
+	for _, line := range lines {
+		subtotal = subtotal.Add(line.Total)
+	}
+
+	var stackable []entity.Promotion
+	var exclusive []entity.Promotion
+	for _, promotion := range promotions {
+		if !promotion.IsAvailableAt(at) || subtotal.LessThan(promotion.MinSpend) {
+			continue
+		}
+		if promotion.Stackable {
+			stackable = append(stackable, promotion)
+		} else {
+			exclusive = append(exclusive, promotion)
+		}
+	}
+
+	if coupon != nil {
+		if !coupon.Stackable {
+			return applyPromotionSet(lines, []entity.Promotion{*coupon})
+		}
+		return applyPromotionSet(lines, append(stackable, *coupon))
+	}
+
+	best := applyPromotionSet(lines, stackable)
+	for _, promotion := range exclusive {
+		result := applyPromotionSet(lines, []entity.Promotion{promotion})
+		if result.Total.GreaterThan(best.Total) {
+			best = result
+		}
+	}
+	return best
+}
+
+// applyPromotionSet applies line promotions before order promotions, each
+// group by descending priority. Every promotion only discounts what is left
+// of a line after the promotions before it, so a line never goes below zero.
+func applyPromotionSet(lines []PromotionLine, promotions []entity.Promotion) *PromotionResult {
+	result := &PromotionResult{
+		LineDiscounts: make([]money.Amount, len(lines)),
+		Applied:       make([]entity.OrderPromotion, 0),
+	}
+
+	ordered := make([]entity.Promotion, len(promotions))
+	copy(ordered, promotions)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].IsOrderLevel() != ordered[j].IsOrderLevel() {
+			return !ordered[i].IsOrderLevel()
+		}
+		return ordered[i].Priority > ordered[j].Priority
+	})
+
+	for _, promotion := range ordered {
+		remaining := make([]money.Amount, len(lines))
+		for i, line := range lines {
+			remaining[i] = line.Total.Sub(result.LineDiscounts[i])
+		}
+
+		discounts := promotionDiscounts(promotion, lines, remaining)
+
+		var total money.Amount
+		for i, discount := range discounts {
+			result.LineDiscounts[i] = result.LineDiscounts[i].Add(discount)
+			total = total.Add(discount)
+		}
+		if !total.IsPositive() {
+			continue
+		}
+
+		result.Total = result.Total.Add(total)
+		result.Applied = append(result.Applied, entity.OrderPromotion{
+			PromotionID:    promotion.PromotionID,
+			PromotionName:  promotion.Name,
+			DiscountAmount: total,
+		})
+	}
+
+	return result
+}
+
+func promotionDiscounts(promotion entity.Promotion, lines []PromotionLine, remaining []money.Amount) []money.Amount {
+	discounts := make([]money.Amount, len(lines))
+
+	if promotion.IsOrderLevel() {
+		var remainingTotal money.Amount
+		for _, amount := range remaining {
+			remainingTotal = remainingTotal.Add(amount)
+		}
+
+		discount := promotion.Amount
+		if promotion.Type == entity.PromotionOrderPercentage {
+			discount = remainingTotal.MulRate(promotion.Percentage).RoundRupiah()
+		}
+		return allocateDiscount(money.Min(discount, remainingTotal), remaining)
+	}
+
+	for i, line := range lines {
+		if !promotionMatchesLine(promotion, line) || !remaining[i].IsPositive() {
+			continue
+		}
+
+		var discount money.Amount
+		switch promotion.Type {
+		case entity.PromotionProductPercentage, entity.PromotionCategoryPercentage:
+			discount = remaining[i].MulRate(promotion.Percentage).RoundRupiah()
+		case entity.PromotionProductFixed, entity.PromotionCategoryFixed:
+			discount = promotion.Amount.Mul(int64(line.Quantity))
+		case entity.PromotionBuyXGetY:
+			group := promotion.BuyQuantity + promotion.GetQuantity
+			if group > 0 {
+				free := line.Quantity / group * promotion.GetQuantity
+				discount = line.UnitPrice.Mul(int64(free))
+			}
+		}
+		discounts[i] = money.Min(discount, remaining[i])
+	}
+	return discounts
+}
+
+// promotionMatchesLine reports whether a line promotion targets the line.
+// buy_x_get_y targets a product when product_id is set, otherwise a category.
+func promotionMatchesLine(promotion entity.Promotion, line PromotionLine) bool {
+	switch promotion.Type {
+	case entity.PromotionProductPercentage, entity.PromotionProductFixed:
+		return promotion.ProductID != nil && *promotion.ProductID == line.ProductID
+	case entity.PromotionCategoryPercentage, entity.PromotionCategoryFixed:
+		return promotion.Category != "" && strings.EqualFold(promotion.Category, line.Category)
+	case entity.PromotionBuyXGetY:
+		if promotion.ProductID != nil {
+			return *promotion.ProductID == line.ProductID
+		}
+		return promotion.Category != "" && strings.EqualFold(promotion.Category, line.Category)
+	}
+	return false
+}
+
+// allocateDiscount spreads an order discount over the lines in proportion to
+// what is left of them, in whole rupiah. The rounding remainder goes to the
+// last lines that still have room, so the parts always add up to the discount.
+func allocateDiscount(discount money.Amount, remaining []money.Amount) []money.Amount {
+	allocations := make([]money.Amount, len(remaining))
+
+	var remainingTotal money.Amount
+	for _, amount := range remaining {
+		remainingTotal = remainingTotal.Add(amount)
+	}
+	if !discount.IsPositive() || !remainingTotal.IsPositive() {
+		return allocations
+	}
+
+	allocated := money.Zero
+	for i, amount := range remaining {
+		if !amount.IsPositive() {
+			continue
+		}
+		allocations[i] = money.New(discount.Rupiah() * amount.Sen() / remainingTotal.Sen())
+		allocated = allocated.Add(allocations[i])
+	}
+
+	left := discount.Sub(allocated)
+	for i := len(remaining) - 1; i >= 0 && left.IsPositive(); i-- {
+		room := remaining[i].Sub(allocations[i])
+		if !room.IsPositive() {
+			continue
+		}
+		extra := money.Min(left, room)
+		allocations[i] = allocations[i].Add(extra)
+		left = left.Sub(extra)
+	}
+	return allocations
+}
+
+func validatePromotion(promotion *entity.Promotion) error {
+	if promotion.Name == "" {
+		return errors.New("promotion name cannot be empty")
+	}
+	if promotion.StartsAt.IsZero() {
+		return errors.New("starts_at is required")
+	}
+	if promotion.EndsAt != nil && !promotion.EndsAt.After(promotion.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if promotion.UsageLimit != nil && *promotion.UsageLimit <= 0 {
+		return errors.New("usage_limit must be greater than 0")
+	}
+	if promotion.PerCustomerLimit != nil && *promotion.PerCustomerLimit <= 0 {
+		return errors.New("per_customer_limit must be greater than 0")
+	}
+	// Uses per customer are only counted when a coupon code is redeemed
+	if promotion.PerCustomerLimit != nil && !promotion.RequiresCode {
+		return errors.New("per_customer_limit is only allowed on promotions that require a code")
+	}
+	if promotion.MinSpend.IsNegative() {
+		return errors.New("min_spend cannot be negative")
+	}
+
+	switch promotion.Type {
+	case entity.PromotionOrderPercentage, entity.PromotionProductPercentage, entity.PromotionCategoryPercentage:
+		if promotion.Percentage <= 0 || promotion.Percentage > 1 {
+			return errors.New("percentage must be greater than 0 and at most 1")
+		}
+	case entity.PromotionOrderFixed, entity.PromotionProductFixed, entity.PromotionCategoryFixed:
+		if !promotion.Amount.IsPositive() {
+			return errors.New("amount must be greater than 0")
+		}
+		// Discounts stay in whole rupiah so that totals can be charged through Midtrans
+		if !promotion.Amount.IsWholeRupiah() {
+			return errors.New("amount must be a whole rupiah amount")
+		}
+	case entity.PromotionBuyXGetY:
+		if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+			return errors.New("buy_quantity and get_quantity must be greater than 0")
+		}
+		if promotion.ProductID == nil && promotion.Category == "" {
+			return errors.New("buy_x_get_y requires product_id or category")
+		}
+	default:
+		return errors.New("invalid promotion type")
+	}
+
+	switch promotion.Type {
+	case entity.PromotionProductPercentage, entity.PromotionProductFixed:
+		if promotion.ProductID == nil {
+			return errors.New("product_id is required for product promotions")
+		}
+	case entity.PromotionCategoryPercentage, entity.PromotionCategoryFixed:
+		if promotion.Category == "" {
+			return errors.New("category is required for category promotions")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// TestEvaluatePromotions tests discount selection, stacking and limits
+func TestEvaluatePromotions(t *testing.T) {
+	now := time.Now()
+	coffee := uuid.New()
+	cake := uuid.New()
+	lines := []PromotionLine{
+		{ProductID: coffee, Category: "drinks", Quantity: 3, UnitPrice: money.New(20000), Total: money.New(60000)},
+		{ProductID: cake, Category: "food", Quantity: 1, UnitPrice: money.New(40000), Total: money.New(40000)},
+	}
+	limit := 5
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		promotions    []entity.Promotion
+		wantTotal     money.Amount
+		wantLines     []money.Amount
+		wantPromotion int
+	}{
+		{
+			name:          "order percentage spread over lines",
+			promotions:    []entity.Promotion{{Type: entity.PromotionOrderPercentage, Percentage: 0.1, Active: true, StartsAt: past}},
+			wantTotal:     money.New(10000),
+			wantLines:     []money.Amount{money.New(6000), money.New(4000)},
+			wantPromotion: 1,
+		},
+		{
+			name:          "product fixed per unit",
+			promotions:    []entity.Promotion{{Type: entity.PromotionProductFixed, Amount: money.New(2000), ProductID: &coffee, Active: true, StartsAt: past}},
+			wantTotal:     money.New(6000),
+			wantLines:     []money.Amount{money.New(6000), money.Zero},
+			wantPromotion: 1,
+		},
+		{
+			name:          "buy 2 get 1 on category",
+			promotions:    []entity.Promotion{{Type: entity.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1, Category: "Drinks", Active: true, StartsAt: past}},
+			wantTotal:     money.New(20000),
+			wantLines:     []money.Amount{money.New(20000), money.Zero},
+			wantPromotion: 1,
+		},
+		{
+			name: "stackable promotions apply line discounts first",
+			promotions: []entity.Promotion{
+				{Type: entity.PromotionOrderPercentage, Percentage: 0.1, Stackable: true, Active: true, StartsAt: past},
+				{Type: entity.PromotionCategoryPercentage, Percentage: 0.5, Category: "food", Stackable: true, Active: true, StartsAt: past},
+			},
+			wantTotal:     money.New(28000),
+			wantLines:     []money.Amount{money.New(6000), money.New(22000)},
+			wantPromotion: 2,
+		},
+		{
+			name: "larger exclusive promotion beats stacked ones",
+			promotions: []entity.Promotion{
+				{Type: entity.PromotionOrderFixed, Amount: money.New(1000), Stackable: true, Active: true, StartsAt: past},
+				{Type: entity.PromotionOrderFixed, Amount: money.New(15000), Active: true, StartsAt: past},
+			},
+			wantTotal:     money.New(15000),
+			wantLines:     []money.Amount{money.New(9000), money.New(6000)},
+			wantPromotion: 1,
+		},
+		{
+			name:          "fixed discount capped at order total",
+			promotions:    []entity.Promotion{{Type: entity.PromotionOrderFixed, Amount: money.New(500000), Active: true, StartsAt: past}},
+			wantTotal:     money.New(100000),
+			wantLines:     []money.Amount{money.New(60000), money.New(40000)},
+			wantPromotion: 1,
+		},
+		{
+			name: "ineligible promotions are ignored",
+			promotions: []entity.Promotion{
+				{Type: entity.PromotionOrderFixed, Amount: money.New(1000), MinSpend: money.New(200000), Active: true, StartsAt: past},
+				{Type: entity.PromotionOrderFixed, Amount: money.New(1000), Active: true, StartsAt: now.Add(time.Hour)},
+				{Type: entity.PromotionOrderFixed, Amount: money.New(1000), Active: true, StartsAt: past, EndsAt: &now},
+				{Type: entity.PromotionOrderFixed, Amount: money.New(1000), Active: true, StartsAt: past, UsageLimit: &limit, UsageCount: 5},
+				{Type: entity.PromotionOrderFixed, Amount: money.New(1000), Active: false, StartsAt: past},
+			},
+			wantTotal:     money.Zero,
+			wantLines:     []money.Amount{money.Zero, money.Zero},
+			wantPromotion: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluatePromotions(lines, tt.promotions, nil, now)
+			if result.Total != tt.wantTotal {
+				t.Errorf("Expected total discount %s, got %s", tt.wantTotal, result.Total)
+			}
+			for i, want := range tt.wantLines {
+				if result.LineDiscounts[i] != want {
+					t.Errorf("Expected line %d discount %s, got %s", i, want, result.LineDiscounts[i])
+				}
+			}
+			if len(result.Applied) != tt.wantPromotion {
+				t.Errorf("Expected %d applied promotions, got %d", tt.wantPromotion, len(result.Applied))
+			}
+		})
+	}
+}
+
+// TestAllocateDiscount tests that allocated parts add up to the discount
+func TestAllocateDiscount(t *testing.T) {
+	remaining := []money.Amount{money.New(10000), money.New(10000), money.New(10000)}
+	allocations := allocateDiscount(money.New(1000), remaining)
+
+	var total money.Amount
+	for i, allocation := range allocations {
+		if !allocation.IsWholeRupiah() {
+			t.Errorf("Expected whole rupiah allocation on line %d, got %s", i, allocation)
+		}
+		total = total.Add(allocation)
+	}
+	if total != money.New(1000) {
+		t.Errorf("Expected allocations to add up to 1000.00, got %s", total)
+	}
+}
+
+// TestValidatePromotionPerCustomerLimit tests that a per customer limit needs a coupon code
+func TestValidatePromotionPerCustomerLimit(t *testing.T) {
+	one := 1
+	promotion := entity.Promotion{
+		Name:             "Welcome",
+		Type:             entity.PromotionOrderPercentage,
+		Percentage:       0.1,
+		StartsAt:         time.Now(),
+		PerCustomerLimit: &one,
+	}
+
+	if err := validatePromotion(&promotion); err == nil || !strings.Contains(err.Error(), "require a code") {
+		t.Errorf("Expected per_customer_limit to be rejected on an automatic promotion, got %v", err)
+	}
+
+	promotion.RequiresCode = true
+	if err := validatePromotion(&promotion); err != nil {
+		t.Errorf("Expected per_customer_limit to be allowed on a coupon campaign, got %v", err)
+	}
+}
@@ -3,39 +3,64 @@ package service
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
 	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/email"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+	"Kevinmajesta/OrderManagementAPI/worker"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReceiptService interface {
 	GenerateReceipt(orderID uuid.UUID, userID uuid.UUID, cashierName string) (*entity.Receipt, error)
+	IssueReceipt(orderID uuid.UUID) (*entity.Receipt, error)
 	GetReceiptByID(receiptID uuid.UUID) (*entity.Receipt, error)
 	GetReceiptByOrderID(orderID uuid.UUID) (*entity.Receipt, error)
 	GetReceiptsByUserID(userID uuid.UUID) ([]entity.Receipt, error)
+	RenderReceiptPDF(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, []byte, error)
+	RenderReceiptESCPOS(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, []byte, error)
+	RenderReceiptText(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, string, error)
+	VoidReceipt(receiptID uuid.UUID, managerID uuid.UUID, reason string) (*entity.Receipt, error)
+	GetReceiptAuditReport(from, to time.Time) (*entity.ReceiptAuditReport, error)
+	VerifyReceipt(receiptNumber, total, issuedAt, signature string) (*entity.ReceiptVerification, error)
 }
 
+// The formats a print is recorded in
+const (
+	receiptFormatPDF    = "pdf"
+	receiptFormatESCPOS = "escpos"
+	receiptFormatText   = "text"
+)
+
 type receiptService struct {
-	receiptRepo repository.ReceiptRepository
-	orderRepo   repository.OrderRepository
-	db          *gorm.DB
+	receiptRepo    repository.ReceiptRepository
+	orderRepo      repository.OrderRepository
+	settingService SettingService
+	signingKey     []byte
+	db             *gorm.DB
 }
 
-func NewReceiptService(receiptRepo repository.ReceiptRepository, orderRepo repository.OrderRepository, db *gorm.DB) *receiptService {
+// NewReceiptService signs the QR code of receipts with signingKey.
+func NewReceiptService(receiptRepo repository.ReceiptRepository, orderRepo repository.OrderRepository, settingService SettingService, signingKey string, db *gorm.DB) *receiptService {
 	return &receiptService{
-		receiptRepo: receiptRepo,
-		orderRepo:   orderRepo,
-		db:          db,
+		receiptRepo:    receiptRepo,
+		orderRepo:      orderRepo,
+		settingService: settingService,
+		signingKey:     []byte(signingKey),
+		db:             db,
 	}
 }
 
 func (s *receiptService) GenerateReceipt(orderID uuid.UUID, userID uuid.UUID, cashierName string) (*entity.Receipt, error) {
-	var order entity.Order
-	err := s.db.Preload("OrderItems").Where("order_id = ?", orderID).First(&order).Error
+	order, err := s.findOrder(orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -44,56 +69,183 @@ func (s *receiptService) GenerateReceipt(orderID uuid.UUID, userID uuid.UUID, ca
 		return nil, errors.New("unauthorized")
 	}
 
-	// Check if receipt already exists
+	// Check if receipt already exists. A voided one is replaced by a new one
 	existing, _ := s.receiptRepo.GetReceiptByOrderID(orderID)
-	if existing != nil {
+	if existing != nil && existing.VoidedAt == nil {
 		return existing, nil
 	}
 
-	// Generate receipt number
-	lastNum, _ := s.receiptRepo.GetLastReceiptNumber()
-	receiptNumber := s.generateReceiptNumber(lastNum)
-
-	// Calculate tax (10%)
-	taxRate := 0.10
-	taxAmount := order.TotalPrice * taxRate
-	totalAmount := order.TotalPrice + taxAmount
-
-	receipt := &entity.Receipt{
-		ReceiptID:     uuid.New(),
-		OrderID:       orderID,
-		UserID:        userID,
-		Subtotal:      order.TotalPrice,
-		TaxAmount:     taxAmount,
-		TotalAmount:   totalAmount,
-		PaymentMethod: order.PaymentMethod,
-		PaymentStatus: order.Status,
-		ReceiptNumber: receiptNumber,
-		CashierName:   cashierName,
-		StoreName:     "Cuaniaga Store",
-		StoreAddress:  "Jl. Raya No. 123",
-		StorePhone:    "+62 812 3456 7890",
-		ReceiptItems:  make([]entity.ReceiptItem, 0),
+	receipt, _, err := s.createReceipt(order, cashierName)
+	return receipt, err
+}
+
+// IssueReceipt makes the receipt of an order that has just been paid and
+// emails it to the customer. The cashier is the one who rang up the order. An
+// order that already has a receipt keeps it and is not emailed again.
+func (s *receiptService) IssueReceipt(orderID uuid.UUID) (*entity.Receipt, error) {
+	order, err := s.findOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "paid" {
+		return nil, errors.New("order is not paid")
+	}
+
+	var cashierName string
+	if order.CashierID != nil {
+		var cashier entity.User
+		if err := s.db.Where("user_id = ?", *order.CashierID).First(&cashier).Error; err == nil {
+			cashierName = cashier.Fullname
+		}
+	}
+
+	receipt, created, err := s.createReceipt(order, cashierName)
+	if err != nil || !created {
+		return receipt, err
+	}
+	return receipt, s.emailReceipt(receipt)
+}
+
+func (s *receiptService) findOrder(orderID uuid.UUID) (*entity.Order, error) {
+	var order entity.Order
+	err := s.db.Preload("OrderItems.GiftCard").Preload("OrderItems.Modifiers").Preload("Payments").Where("order_id = ?", orderID).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// createReceipt copies the order into a new receipt. created is false when
+// another request made the receipt first, which is then returned instead. A
+// voided receipt does not count.
+func (s *receiptService) createReceipt(order *entity.Order, cashierName string) (receipt *entity.Receipt, created bool, err error) {
+	orderID := order.OrderID
+	var subtotal money.Amount
+	for _, item := range order.OrderItems {
+		subtotal = subtotal.Add(item.TotalPrice)
+	}
+
+	receipt = &entity.Receipt{
+		ReceiptID:           uuid.New(),
+		OrderID:             orderID,
+		UserID:              order.UserID,
+		Subtotal:            subtotal,
+		DiscountAmount:      order.DiscountAmount,
+		TaxAmount:           order.TaxAmount,
+		ServiceChargeAmount: order.ServiceChargeAmount,
+		TipAmount:           order.TipAmount,
+		TotalAmount:         order.TotalPrice,
+		PaymentMethod:       order.PaymentMethod,
+		PaymentStatus:       order.Status,
+		CashierName:         cashierName,
+		StoreName:           s.settingService.StoreName(),
+		StoreAddress:        s.settingService.StoreAddress(),
+		StorePhone:          s.settingService.StorePhone(),
+		ReceiptItems:        make([]entity.ReceiptItem, 0),
 	}
 
 	// Add receipt items
 	for _, item := range order.OrderItems {
 		receiptItem := entity.ReceiptItem{
-			ReceiptItemID: uuid.New(),
-			ReceiptID:     receipt.ReceiptID,
-			ProductName:   s.getProductName(item.ProductID),
-			Quantity:      item.Quantity,
-			UnitPrice:     item.PricePerItem,
-			TotalPrice:    item.TotalPrice,
+			ReceiptItemID:  uuid.New(),
+			ReceiptID:      receipt.ReceiptID,
+			ProductName:    s.getItemName(item),
+			Quantity:       item.Quantity,
+			UnitPrice:      item.PricePerItem,
+			TotalPrice:     item.TotalPrice,
+			DiscountAmount: item.DiscountAmount,
+		}
+		for _, modifier := range item.Modifiers {
+			receiptItem.Modifiers = append(receiptItem.Modifiers, entity.ReceiptItemModifier{
+				ReceiptItemModifierID: uuid.New(),
+				ReceiptItemID:         receiptItem.ReceiptItemID,
+				Name:                  modifier.GroupName + ": " + modifier.OptionName,
+				PriceDelta:            modifier.PriceDelta,
+			})
 		}
 		receipt.ReceiptItems = append(receipt.ReceiptItems, receiptItem)
 	}
 
-	if err := s.receiptRepo.CreateReceipt(receipt); err != nil {
-		return nil, err
+	// The order row is locked so that a receipt is only made once per order.
+	// The number takes the business day in the store's time zone.
+	now := time.Now().In(s.settingService.Location())
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ?", orderID).
+			First(&entity.Order{}).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&entity.Receipt{}).Where("order_id = ? AND voided_at IS NULL", orderID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		created = true
+		receipt.CreatedAt = now
+		return createNumberedReceipt(tx, receipt, s.settingService.ReceiptNumberFormat(), s.settingService.ReceiptPrefix(), now)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		receipt, err = s.receiptRepo.GetReceiptByOrderID(orderID)
+		return receipt, false, err
 	}
+	receipt.Payments = order.Payments
 
-	return receipt, nil
+	return receipt, true, nil
+}
+
+// emailReceipt queues the receipt for the email worker, with the PDF on the
+// receipt_paper setting attached. A customer without an email is skipped.
+// The receipt is read back so the PDF matches a later print of it.
+func (s *receiptService) emailReceipt(receipt *entity.Receipt) error {
+	receipt, err := s.receiptRepo.GetReceiptByID(receipt.ReceiptID)
+	if err != nil {
+		return err
+	}
+
+	var user entity.User
+	if err := s.db.Where("user_id = ?", receipt.UserID).First(&user).Error; err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+
+	size := receiptPapers[s.settingService.ReceiptPaper()]
+	lines, err := s.receiptLines(receipt, size.Columns)
+	if err != nil {
+		return err
+	}
+	body, err := receiptEmailHTML(receipt, user.Fullname)
+	if err != nil {
+		return err
+	}
+
+	job := worker.EmailJob{
+		Type:    "html",
+		To:      user.Email,
+		Name:    user.Fullname,
+		Subject: fmt.Sprintf("Receipt %s | %s", receipt.ReceiptNumber, receipt.StoreName),
+		Body:    body,
+		Attachments: []email.Attachment{{
+			Filename:    receipt.ReceiptNumber + ".pdf",
+			ContentType: "application/pdf",
+			Data:        renderReceiptPDF(lines, size),
+		}},
+	}
+
+	// The order is already paid, so a full queue must not hold up the request
+	select {
+	case worker.EmailQueue <- job:
+		return nil
+	default:
+		return errors.New("email queue is full")
+	}
 }
 
 func (s *receiptService) GetReceiptByID(receiptID uuid.UUID) (*entity.Receipt, error) {
@@ -108,23 +260,290 @@ func (s *receiptService) GetReceiptsByUserID(userID uuid.UUID) ([]entity.Receipt
 	return s.receiptRepo.GetReceiptsByUserID(userID)
 }
 
-func (s *receiptService) generateReceiptNumber(lastNum string) string {
-	now := time.Now()
-	date := now.Format("20060102")
+// RenderReceiptPDF prints a receipt on A4 or 80mm paper, the receipt_paper
+// setting when paper is empty. The print is recorded against printedBy.
+func (s *receiptService) RenderReceiptPDF(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, []byte, error) {
+	if paper == "" {
+		paper = s.settingService.ReceiptPaper()
+	}
+	size, ok := receiptPapers[paper]
+	if !ok {
+		return nil, nil, errors.New("paper must be a4 or 80mm")
+	}
+
+	var data []byte
+	receipt, err := s.printReceipt(receiptID, receiptFormatPDF, printedBy, func(receipt *entity.Receipt) error {
+		lines, err := s.receiptLines(receipt, size.Columns)
+		if err != nil {
+			return err
+		}
+		data = renderReceiptPDF(lines, size)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return receipt, data, nil
+}
+
+// RenderReceiptESCPOS prints a receipt for a 58mm or 80mm thermal printer.
+func (s *receiptService) RenderReceiptESCPOS(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, []byte, error) {
+	var data []byte
+	receipt, err := s.printThermal(receiptID, paper, receiptFormatESCPOS, printedBy, func(receipt *entity.Receipt, lines []receiptLine) {
+		data = renderReceiptESCPOS(lines, paidInCash(receipt))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return receipt, data, nil
+}
 
-	if lastNum == "" {
-		return fmt.Sprintf("RCP%s0001", date)
+// RenderReceiptText is the plain text preview of RenderReceiptESCPOS.
+func (s *receiptService) RenderReceiptText(receiptID uuid.UUID, paper string, printedBy uuid.UUID) (*entity.Receipt, string, error) {
+	var text string
+	receipt, err := s.printThermal(receiptID, paper, receiptFormatText, printedBy, func(receipt *entity.Receipt, lines []receiptLine) {
+		text = renderReceiptText(lines)
+	})
+	if err != nil {
+		return nil, "", err
 	}
+	return receipt, text, nil
+}
 
-	lastDate := lastNum[3:11]
-	if lastDate != date {
-		return fmt.Sprintf("RCP%s0001", date)
+// printThermal lays out a receipt for a thermal roll, 80mm when paper is
+// empty, and hands the lines to render.
+func (s *receiptService) printThermal(receiptID uuid.UUID, paper, format string, printedBy uuid.UUID, render func(*entity.Receipt, []receiptLine)) (*entity.Receipt, error) {
+	if paper == "" {
+		paper = entity.ReceiptPaper80mm
 	}
+	columns, ok := thermalColumns[paper]
+	if !ok {
+		return nil, errors.New("paper must be 58mm or 80mm")
+	}
+
+	return s.printReceipt(receiptID, format, printedBy, func(receipt *entity.Receipt) error {
+		lines, err := s.receiptLines(receipt, columns)
+		if err != nil {
+			return err
+		}
+		render(receipt, lines)
+		return nil
+	})
+}
 
-	var counter int
-	fmt.Sscanf(lastNum, fmt.Sprintf("RCP%s%%04d", date), &counter)
-	counter++
-	return fmt.Sprintf("RCP%s%04d", date, counter)
+// printReceipt numbers the print of a receipt and records it. The receipt row
+// is locked so that two prints at once do not get the same number, and a
+// print that fails to render is not recorded.
+func (s *receiptService) printReceipt(receiptID uuid.UUID, format string, printedBy uuid.UUID, render func(*entity.Receipt) error) (*entity.Receipt, error) {
+	receipt, err := s.receiptRepo.GetReceiptByID(receiptID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("receipt_id = ?", receiptID).
+			First(&entity.Receipt{}).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&entity.ReceiptPrint{}).Where("receipt_id = ?", receiptID).Count(&count).Error; err != nil {
+			return err
+		}
+		receipt.PrintNumber = int(count)
+
+		if err := render(receipt); err != nil {
+			return err
+		}
+
+		record := &entity.ReceiptPrint{
+			PrintID:     uuid.New(),
+			ReceiptID:   receiptID,
+			PrintNumber: receipt.PrintNumber,
+			Format:      format,
+		}
+		if printedBy != uuid.Nil {
+			record.PrintedBy = &printedBy
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// VoidReceipt voids a receipt for the reason given. The receipt is kept, is
+// printed with a VOID marker from then on, and its order can be given a new
+// receipt.
+func (s *receiptService) VoidReceipt(receiptID uuid.UUID, managerID uuid.UUID, reason string) (*entity.Receipt, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var receipt entity.Receipt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("receipt_id = ?", receiptID).
+			First(&receipt).Error; err != nil {
+			return err
+		}
+		if receipt.VoidedAt != nil {
+			return errors.New("receipt is already void")
+		}
+		return tx.Model(&entity.Receipt{}).
+			Where("receipt_id = ?", receiptID).
+			Updates(map[string]interface{}{
+				"voided_at":   time.Now(),
+				"void_reason": reason,
+				"voided_by":   managerID,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.receiptRepo.GetReceiptByID(receiptID)
+}
+
+// GetReceiptAuditReport lists the voids and reprints from the start of from
+// to the end of to, in the store's time zone. Zero days are today.
+func (s *receiptService) GetReceiptAuditReport(from, to time.Time) (*entity.ReceiptAuditReport, error) {
+	location := s.settingService.Location()
+	today := time.Now().In(location)
+	if from.IsZero() {
+		from = today
+	}
+	if to.IsZero() {
+		to = today
+	}
+	from, to = businessDay(from, location), businessDay(to, location)
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	end := to.AddDate(0, 0, 1)
+
+	voids, err := s.receiptRepo.FindVoids(from, end)
+	if err != nil {
+		return nil, err
+	}
+	reprints, err := s.receiptRepo.FindReprints(from, end)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeReceiptAudit(from, to, voids, reprints), nil
+}
+
+// summarizeReceiptAudit groups voids and reprints by cashier, sorted by name.
+func summarizeReceiptAudit(from, to time.Time, voids []entity.ReceiptVoidEntry, reprints []entity.ReceiptReprintEntry) *entity.ReceiptAuditReport {
+	report := &entity.ReceiptAuditReport{
+		From:     from,
+		To:       to,
+		Cashiers: make([]entity.CashierReceiptAudit, 0),
+	}
+	byName := make(map[string]*entity.CashierReceiptAudit)
+	cashier := func(name string) *entity.CashierReceiptAudit {
+		if name == "" {
+			name = "Unknown"
+		}
+		if byName[name] == nil {
+			byName[name] = &entity.CashierReceiptAudit{
+				CashierName: name,
+				Voids:       make([]entity.ReceiptVoidEntry, 0),
+				Reprints:    make([]entity.ReceiptReprintEntry, 0),
+			}
+		}
+		return byName[name]
+	}
+
+	for _, void := range voids {
+		audit := cashier(void.CashierName)
+		audit.VoidCount++
+		audit.VoidAmount = audit.VoidAmount.Add(void.TotalAmount)
+		audit.Voids = append(audit.Voids, void)
+		report.VoidCount++
+		report.VoidAmount = report.VoidAmount.Add(void.TotalAmount)
+	}
+	for _, reprint := range reprints {
+		audit := cashier(reprint.PrintedByName)
+		audit.ReprintCount++
+		audit.Reprints = append(audit.Reprints, reprint)
+		report.ReprintCount++
+	}
+
+	for _, audit := range byName {
+		report.Cashiers = append(report.Cashiers, *audit)
+	}
+	sort.Slice(report.Cashiers, func(i, j int) bool {
+		return report.Cashiers[i].CashierName < report.Cashiers[j].CashierName
+	})
+	return report
+}
+
+// VerifyReceipt checks the signed fields of a receipt's QR code and that the
+// receipt they name exists with that total.
+func (s *receiptService) VerifyReceipt(receiptNumber, total, issuedAt, signature string) (*entity.ReceiptVerification, error) {
+	amount, err := money.Parse(total)
+	if err != nil {
+		return nil, errors.New("invalid total")
+	}
+	issued, err := strconv.ParseInt(issuedAt, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid timestamp")
+	}
+	if !checkReceiptSignature(s.signingKey, receiptNumber, amount, issued, signature) {
+		return nil, errors.New("receipt signature is not valid")
+	}
+
+	// The signed time must be the issue time of the receipt too, so a
+	// signature cannot be reused with another time
+	receipt, err := s.receiptRepo.GetReceiptByNumber(receiptNumber)
+	if err != nil || receipt.TotalAmount != amount || receipt.CreatedAt.Unix() != issued {
+		return nil, errors.New("receipt not found")
+	}
+	return &entity.ReceiptVerification{
+		ReceiptNumber: receipt.ReceiptNumber,
+		StoreName:     receipt.StoreName,
+		TotalAmount:   receipt.TotalAmount,
+		PaymentStatus: receipt.PaymentStatus,
+		ItemCount:     len(receipt.ReceiptItems),
+		IssuedAt:      receipt.CreatedAt,
+		Voided:        receipt.VoidedAt != nil,
+	}, nil
+}
+
+// receiptLines lays out a receipt with the header and footer of the store,
+// and the signed QR code when receipt_verify_url is set. Times are printed in
+// the store's time zone.
+func (s *receiptService) receiptLines(receipt *entity.Receipt, columns int) ([]receiptLine, error) {
+	receipt.CreatedAt = receipt.CreatedAt.In(s.settingService.Location())
+	if base := s.settingService.ReceiptVerifyURL(); base != "" {
+		receipt.VerifyURL = receiptVerifyURL(base, s.signingKey, receipt)
+	}
+	header, err := receiptTemplateLines(s.settingService.ReceiptHeader(), receipt)
+	if err != nil {
+		return nil, fmt.Errorf("receipt_header: %v", err)
+	}
+	footer, err := receiptTemplateLines(s.settingService.ReceiptFooter(), receipt)
+	if err != nil {
+		return nil, fmt.Errorf("receipt_footer: %v", err)
+	}
+	return layoutReceipt(receipt, header, footer, columns), nil
+}
+
+// getItemName names a gift card line by its masked code, so the receipt does
+// not reveal a card that can still be spent.
+func (s *receiptService) getItemName(item entity.OrderItem) string {
+	if item.ItemType == entity.OrderItemGiftCard {
+		if item.GiftCard == nil {
+			return "Gift Card"
+		}
+		return "Gift Card " + item.GiftCard.MaskedCode()
+	}
+	if item.ProductID == nil {
+		return "Unknown Product"
+	}
+	return s.getProductName(*item.ProductID)
 }
 
 func (s *receiptService) getProductName(productID uuid.UUID) string {
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+)
+
+// receiptEmailTemplate is the body of the email a customer gets when their
+// order is paid. The printed receipt is attached as a PDF.
+var receiptEmailTemplate = template.Must(template.New("receipt_email").Funcs(template.FuncMap{
+	"upper": strings.ToUpper,
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #222;">
+<h2 style="margin-bottom: 0;">{{.Receipt.StoreName}}</h2>
+{{if .Receipt.StoreAddress}}<p style="margin-top: 4px; color: #666;">{{.Receipt.StoreAddress}}</p>{{end}}
+<p>Dear {{.Name}},</p>
+<p>Thank you for your order. Your receipt <strong>{{.Receipt.ReceiptNumber}}</strong> of {{.Receipt.CreatedAt.Format "02/01/2006 15:04"}} is attached.</p>
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse; min-width: 360px;">
+<tr style="border-bottom: 1px solid #ccc;"><th align="left">Item</th><th align="right">Qty</th><th align="right">Amount</th></tr>
+{{range .Receipt.ReceiptItems}}<tr><td>{{.ProductName}}{{range .Modifiers}}<br><small>+ {{.Name}}</small>{{end}}</td><td align="right">{{.Quantity}}</td><td align="right">{{.TotalPrice}}</td></tr>
+{{end}}<tr style="border-top: 1px solid #ccc;"><td colspan="2">Subtotal</td><td align="right">{{.Receipt.Subtotal}}</td></tr>
+{{if not .Receipt.DiscountAmount.IsZero}}<tr><td colspan="2">Discount</td><td align="right">-{{.Receipt.DiscountAmount}}</td></tr>
+{{end}}{{if not .Receipt.ServiceChargeAmount.IsZero}}<tr><td colspan="2">Service charge</td><td align="right">{{.Receipt.ServiceChargeAmount}}</td></tr>
+{{end}}<tr><td colspan="2">Tax</td><td align="right">{{.Receipt.TaxAmount}}</td></tr>
+{{if not .Receipt.TipAmount.IsZero}}<tr><td colspan="2">Tip</td><td align="right">{{.Receipt.TipAmount}}</td></tr>
+{{end}}<tr style="border-top: 1px solid #ccc;"><td colspan="2"><strong>Total</strong></td><td align="right"><strong>{{.Receipt.TotalAmount}}</strong></td></tr>
+</table>
+<p>Paid by {{upper .Receipt.PaymentMethod}}.</p>
+</body>
+</html>
+`))
+
+// receiptEmailHTML is the HTML body of the receipt email to name.
+func receiptEmailHTML(receipt *entity.Receipt, name string) (string, error) {
+	var out bytes.Buffer
+	err := receiptEmailTemplate.Execute(&out, struct {
+		Receipt *entity.Receipt
+		Name    string
+	}{receipt, name})
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
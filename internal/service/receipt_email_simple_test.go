@@ -0,0 +1,35 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestReceiptEmailHTML tests that the email lists the receipt and escapes the store's text
+func TestReceiptEmailHTML(t *testing.T) {
+	receipt := printTestReceipt()
+	receipt.StoreName = "Kopi & <Roti>"
+
+	body, err := receiptEmailHTML(receipt, "Budi")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, want := range []string{
+		"<h2 style=\"margin-bottom: 0;\">Kopi &amp; &lt;Roti&gt;</h2>",
+		"<p>Dear Budi,</p>",
+		"<strong>RCP202602030001</strong> of 03/02/2026 14:05",
+		"<td>Croissant</td><td align=\"right\">1</td><td align=\"right\">5000.00</td>",
+		"<br><small>+ Size: Large</small>",
+		"<td align=\"right\">-5000.00</td>",
+		"<strong>55000.00</strong>",
+		"Paid by CASH.",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the email to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "Service charge") || strings.Contains(body, "Tip") {
+		t.Error("Expected zero service charge and tip to be left out")
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+)
+
+// thermalColumns is how many characters of the printer's default font fit on
+// a line of each roll width.
+var thermalColumns = map[string]int{
+	entity.ReceiptPaper58mm: 32,
+	entity.ReceiptPaper80mm: 48,
+}
+
+// ESC/POS commands
+var (
+	escposInit      = []byte{0x1B, 0x40}
+	escposBoldOn    = []byte{0x1B, 0x45, 0x01}
+	escposBoldOff   = []byte{0x1B, 0x45, 0x00}
+	escposFeedLines = []byte{0x1B, 0x64, 0x04}
+	escposCut       = []byte{0x1D, 0x56, 0x01}
+	// escposDrawerKick pulses drawer pin 2 for 50ms on and 500ms off
+	escposDrawerKick = []byte{0x1B, 0x70, 0x00, 0x19, 0xFA}
+	escposCentre     = []byte{0x1B, 0x61, 0x01}
+	escposLeft       = []byte{0x1B, 0x61, 0x00}
+	// QR codes are drawn by the printer: model 2, 5 dot modules, level M
+	escposQRModel  = []byte{0x1D, 0x28, 0x6B, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00}
+	escposQRModule = []byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x43, 0x05}
+	escposQRLevel  = []byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x45, 0x31}
+	escposQRPrint  = []byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x51, 0x30}
+)
+
+// renderReceiptESCPOS turns the lines into the byte stream of a thermal
+// printer: bold where the line is bold, a feed and a partial cut at the end,
+// and a drawer kick for receipts paid in cash.
+func renderReceiptESCPOS(lines []receiptLine, kickDrawer bool) []byte {
+	var b bytes.Buffer
+	b.Write(escposInit)
+	for _, line := range lines {
+		if line.QR != "" {
+			writeESCPOSQR(&b, escposText(line.QR))
+			continue
+		}
+		if line.Bold {
+			b.Write(escposBoldOn)
+		}
+		b.WriteString(escposText(line.Text))
+		if line.Bold {
+			b.Write(escposBoldOff)
+		}
+		b.WriteByte('\n')
+	}
+	b.Write(escposFeedLines)
+	b.Write(escposCut)
+	if kickDrawer {
+		b.Write(escposDrawerKick)
+	}
+	return b.Bytes()
+}
+
+// writeESCPOSQR stores data in the printer's QR symbol buffer and prints it
+// centred.
+func writeESCPOSQR(b *bytes.Buffer, data string) {
+	b.Write(escposCentre)
+	b.Write(escposQRModel)
+	b.Write(escposQRModule)
+	b.Write(escposQRLevel)
+	size := len(data) + 3
+	b.Write([]byte{0x1D, 0x28, 0x6B, byte(size), byte(size >> 8), 0x31, 0x50, 0x30})
+	b.WriteString(data)
+	b.Write(escposQRPrint)
+	b.WriteByte('\n')
+	b.Write(escposLeft)
+}
+
+// escposText keeps to ASCII, which every code page of the printer shares, so
+// a character the printer may not have does not throw the columns out.
+func escposText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+// renderReceiptText is the fixed-width preview of what the printer prints.
+func renderReceiptText(lines []receiptLine) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line.Text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// paidInCash tells whether the cash drawer has to open for the receipt.
+func paidInCash(receipt *entity.Receipt) bool {
+	if len(receipt.Payments) == 0 {
+		return receipt.PaymentMethod == entity.PaymentCash
+	}
+	for _, payment := range receipt.Payments {
+		if payment.Method == entity.PaymentCash && payment.Amount.IsPositive() {
+			return true
+		}
+	}
+	return false
+}
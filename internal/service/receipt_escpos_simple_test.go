@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares got with testdata/name, or rewrites it with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Output differs from %s\ngot:\n%q\nwant:\n%q", path, got, want)
+	}
+}
+
+// TestRenderReceiptESCPOSBytes tests the commands around a two line receipt
+func TestRenderReceiptESCPOSBytes(t *testing.T) {
+	lines := []receiptLine{{Text: "Kopi  5"}, {Text: "TOTAL 5", Bold: true}}
+	want := []byte{
+		0x1B, 0x40,
+		'K', 'o', 'p', 'i', ' ', ' ', '5', '\n',
+		0x1B, 0x45, 0x01, 'T', 'O', 'T', 'A', 'L', ' ', '5', 0x1B, 0x45, 0x00, '\n',
+		0x1B, 0x64, 0x04,
+		0x1D, 0x56, 0x01,
+		0x1B, 0x70, 0x00, 0x19, 0xFA,
+	}
+	if got := renderReceiptESCPOS(lines, true); !bytes.Equal(got, want) {
+		t.Errorf("Expected %x, got %x", want, got)
+	}
+	if got := renderReceiptESCPOS(lines, false); !bytes.Equal(got, want[:len(want)-5]) {
+		t.Errorf("Expected no drawer kick, got %x", got)
+	}
+}
+
+// TestRenderReceiptESCPOSQR tests that a QR line is left to the printer's own QR command
+func TestRenderReceiptESCPOSQR(t *testing.T) {
+	got := renderReceiptESCPOS([]receiptLine{{Text: "[QR]", QR: "https://x.id/v?n=1"}}, false)
+	want := []byte{
+		0x1B, 0x40,
+		0x1B, 0x61, 0x01,
+		0x1D, 0x28, 0x6B, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00,
+		0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x43, 0x05,
+		0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x45, 0x31,
+		0x1D, 0x28, 0x6B, 0x15, 0x00, 0x31, 0x50, 0x30,
+	}
+	want = append(want, "https://x.id/v?n=1"...)
+	want = append(want,
+		0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x51, 0x30, '\n',
+		0x1B, 0x61, 0x00,
+		0x1B, 0x64, 0x04,
+		0x1D, 0x56, 0x01,
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Expected %x, got %x", want, got)
+	}
+}
+
+// TestRenderReceiptESCPOSGolden tests whole receipts on both roll widths
+func TestRenderReceiptESCPOSGolden(t *testing.T) {
+	header := []string{"Cuaniaga Store", "Jl. Raya No. 123"}
+	footer := []string{"Terima kasih atas kunjungan Anda"}
+
+	for _, paper := range []string{entity.ReceiptPaper58mm, entity.ReceiptPaper80mm} {
+		t.Run(paper, func(t *testing.T) {
+			receipt := printTestReceipt()
+			lines := layoutReceipt(receipt, header, footer, thermalColumns[paper])
+			checkGolden(t, "receipt_"+paper+".escpos", renderReceiptESCPOS(lines, paidInCash(receipt)))
+			checkGolden(t, "receipt_"+paper+".txt", []byte(renderReceiptText(lines)))
+		})
+	}
+}
+
+// TestEscposText tests characters outside ASCII are replaced
+func TestEscposText(t *testing.T) {
+	if got := escposText("Café ☕"); got != "Caf? ?" {
+		t.Errorf("Expected non-ASCII to be replaced, got %q", got)
+	}
+}
+
+// TestPaidInCash tests when the drawer is kicked
+func TestPaidInCash(t *testing.T) {
+	tests := []struct {
+		name    string
+		receipt entity.Receipt
+		want    bool
+	}{
+		{"cash order", entity.Receipt{PaymentMethod: entity.PaymentCash}, true},
+		{"midtrans order", entity.Receipt{PaymentMethod: entity.PaymentMidtrans}, false},
+		{"split with cash", entity.Receipt{PaymentMethod: entity.PaymentSplit, Payments: []entity.OrderPayment{
+			{Method: entity.PaymentMidtrans, Amount: money.New(10000)}, {Method: entity.PaymentCash, Amount: money.New(5000)}}}, true},
+		{"split without cash", entity.Receipt{PaymentMethod: entity.PaymentSplit, Payments: []entity.OrderPayment{
+			{Method: entity.PaymentMidtrans, Amount: money.New(10000)}, {Method: entity.PaymentCredit, Amount: money.New(5000)}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paidInCash(&tt.receipt); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
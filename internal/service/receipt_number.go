@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// maxReceiptNumberAttempts bounds how many numbers are tried when a number is
+// already taken by a receipt made before the counter existed.
+const maxReceiptNumberAttempts = 5
+
+// maxReceiptNumberLength is the size of the receipt_number column.
+const maxReceiptNumberLength = 40
+
+var (
+	receiptNumberToken   = regexp.MustCompile(`\{([a-z]+)(?::(\d))?\}`)
+	receiptNumberLiteral = regexp.MustCompile(`^[A-Za-z0-9/._-]*$`)
+)
+
+// formatReceiptNumber fills in a receipt_number_format such as
+// "{prefix}{yyyy}{mm}{dd}{seq:4}". {seq:n} pads the number of the day with
+// zeros to n digits.
+func formatReceiptNumber(format, prefix string, day time.Time, seq int) string {
+	return receiptNumberToken.ReplaceAllStringFunc(format, func(token string) string {
+		match := receiptNumberToken.FindStringSubmatch(token)
+		switch match[1] {
+		case "prefix":
+			return prefix
+		case "yyyy":
+			return day.Format("2006")
+		case "yy":
+			return day.Format("06")
+		case "mm":
+			return day.Format("01")
+		case "dd":
+			return day.Format("02")
+		case "seq":
+			width, _ := strconv.Atoi(match[2])
+			return fmt.Sprintf("%0*d", width, seq)
+		}
+		return token
+	})
+}
+
+// validateReceiptNumberFormat checks that every token is known and that the
+// format holds the date and the number, as numbers restart every day.
+func validateReceiptNumberFormat(format string) error {
+	found := make(map[string]bool)
+	for _, match := range receiptNumberToken.FindAllStringSubmatch(format, -1) {
+		switch match[1] {
+		case "prefix", "yyyy", "yy", "mm", "dd":
+			if match[2] != "" {
+				return fmt.Errorf("{%s} does not take a width", match[1])
+			}
+		case "seq":
+		default:
+			return fmt.Errorf("unknown token {%s}", match[1])
+		}
+		found[match[1]] = true
+	}
+
+	if !receiptNumberLiteral.MatchString(receiptNumberToken.ReplaceAllString(format, "")) {
+		return errors.New("only letters, digits, / . _ - and tokens are allowed")
+	}
+	if !found["seq"] || !found["dd"] || !found["mm"] || (!found["yyyy"] && !found["yy"]) {
+		return errors.New("{seq}, {dd}, {mm} and {yyyy} or {yy} are required")
+	}
+
+	longest := formatReceiptNumber(format, strings.Repeat("X", 8), time.Now(), 99999)
+	if len(longest) > maxReceiptNumberLength {
+		return fmt.Errorf("numbers can be up to %d characters", maxReceiptNumberLength)
+	}
+	return nil
+}
+
+// nextReceiptNumber takes the next number of the day. The counter row stays
+// locked until the transaction ends, so concurrent receipts wait for each
+// other and a rolled back receipt gives its number back.
+func nextReceiptNumber(tx *gorm.DB, day time.Time) (int, error) {
+	var seq int
+	err := tx.Raw(`INSERT INTO receipt_counters (counter_date, last_number, updated_at) VALUES (?, 1, now())
+		ON CONFLICT (counter_date) DO UPDATE SET last_number = receipt_counters.last_number + 1, updated_at = now()
+		RETURNING last_number`, day.Format("2006-01-02")).Scan(&seq).Error
+	return seq, err
+}
+
+// createNumberedReceipt numbers the receipt and saves it. A number that is
+// already taken is skipped, which only happens for receipts numbered before
+// the counter existed or under another format.
+func createNumberedReceipt(tx *gorm.DB, receipt *entity.Receipt, format, prefix string, now time.Time) error {
+	for attempt := 1; ; attempt++ {
+		seq, err := nextReceiptNumber(tx, now)
+		if err != nil {
+			return err
+		}
+		receipt.ReceiptNumber = formatReceiptNumber(format, prefix, now, seq)
+
+		if err := tx.SavePoint("receipt_number").Error; err != nil {
+			return err
+		}
+		err = tx.Create(receipt).Error
+		if err == nil {
+			return nil
+		}
+		if err := tx.RollbackTo("receipt_number").Error; err != nil {
+			return err
+		}
+		if !isDuplicateKey(tx, err) || attempt == maxReceiptNumberAttempts {
+			return err
+		}
+	}
+}
+
+// isDuplicateKey tells whether err is a unique constraint violation.
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+)
+
+// TestFormatReceiptNumber tests receipt number formats
+func TestFormatReceiptNumber(t *testing.T) {
+	day := time.Date(2026, 2, 3, 23, 59, 0, 0, time.Local)
+	tests := []struct {
+		format string
+		seq    int
+		want   string
+	}{
+		{format: entity.DefaultStoreSettings[entity.SettingReceiptNumberFormat], seq: 1, want: "RCP202602030001"},
+		{format: entity.DefaultStoreSettings[entity.SettingReceiptNumberFormat], seq: 12345, want: "RCP2026020312345"},
+		{format: "{prefix}/{yy}{mm}{dd}/{seq:6}", seq: 42, want: "RCP/260203/000042"},
+		{format: "INV-{dd}.{mm}.{yyyy}-{seq}", seq: 7, want: "INV-03.02.2026-7"},
+	}
+
+	for _, tt := range tests {
+		if got := formatReceiptNumber(tt.format, "RCP", day, tt.seq); got != tt.want {
+			t.Errorf("formatReceiptNumber(%q, %d) = %q, want %q", tt.format, tt.seq, got, tt.want)
+		}
+	}
+}
+
+// TestValidateReceiptNumberFormat tests formats that could repeat or not fit are rejected
+func TestValidateReceiptNumberFormat(t *testing.T) {
+	valid := []string{
+		entity.DefaultStoreSettings[entity.SettingReceiptNumberFormat],
+		"{prefix}/{yy}{mm}{dd}/{seq:6}",
+		"INV-{dd}.{mm}.{yyyy}-{seq}",
+	}
+	for _, format := range valid {
+		if err := validateReceiptNumberFormat(format); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", format, err)
+		}
+	}
+
+	invalid := []string{
+		"{prefix}{seq:4}",
+		"{prefix}{yyyy}{mm}{seq:4}",
+		"{prefix}{yyyy}{mm}{dd}",
+		"{prefix}{yyyy}{mm}{dd}{seq:4}{outlet}",
+		"{prefix:2}{yyyy}{mm}{dd}{seq:4}",
+		"{prefix} {yyyy}{mm}{dd}{seq:4}",
+		"{prefix}-RECEIPT-NUMBER-FOR-THE-STORE-{yyyy}{mm}{dd}{seq:9}",
+	}
+	for _, format := range invalid {
+		if err := validateReceiptNumberFormat(format); err == nil {
+			t.Errorf("Expected %q to be rejected", format)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+	"Kevinmajesta/OrderManagementAPI/pkg/pdf"
+	"Kevinmajesta/OrderManagementAPI/pkg/qr"
+)
+
+// receiptPaper is how a paper size is printed. Sizes are in points and a
+// Height of 0 is a roll cut to the length of the receipt.
+type receiptPaper struct {
+	Width    float64
+	Height   float64
+	Margin   float64
+	FontSize float64
+	Columns  int
+}
+
+var receiptPapers = map[string]receiptPaper{
+	entity.ReceiptPaperA4:   {Width: 210 * pdf.PointsPerMM, Height: 297 * pdf.PointsPerMM, Margin: 20 * pdf.PointsPerMM, FontSize: 10, Columns: 80},
+	entity.ReceiptPaper80mm: {Width: 80 * pdf.PointsPerMM, Margin: 4 * pdf.PointsPerMM, FontSize: 8, Columns: 42},
+}
+
+// receiptLineHeight is the line spacing as a multiple of the font size
+const receiptLineHeight = 1.25
+
+// receiptQRSize is the width of the QR code on a PDF receipt, in points.
+const receiptQRSize = 28 * pdf.PointsPerMM
+
+// receiptLine is one line of a printed receipt, padded to the paper width. A
+// line with QR set is printed as a QR code of it, Text is what stands in for
+// the code in a plain text preview.
+type receiptLine struct {
+	Text string
+	Bold bool
+	QR   string
+}
+
+// parseReceiptTemplate parses a header or footer template and runs it on an
+// empty receipt, so a field that does not exist is caught when it is saved.
+func parseReceiptTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("receipt").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(new(bytes.Buffer), &entity.Receipt{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// receiptTemplateLines runs a header or footer template on the receipt and
+// splits the result into lines.
+func receiptTemplateLines(text string, receipt *entity.Receipt) ([]string, error) {
+	tmpl, err := parseReceiptTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, receipt); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// layoutReceipt lays out the receipt in columns characters, with the header
+// and footer lines centred.
+func layoutReceipt(receipt *entity.Receipt, header, footer []string, columns int) []receiptLine {
+	var lines []receiptLine
+	text := func(s string) {
+		for _, part := range wrapText(s, columns) {
+			lines = append(lines, receiptLine{Text: part})
+		}
+	}
+	centre := func(s string) {
+		for _, part := range wrapText(s, columns) {
+			lines = append(lines, receiptLine{Text: strings.Repeat(" ", (columns-utf8.RuneCountInString(part))/2) + part})
+		}
+	}
+	pair := func(label, value string, bold bool) {
+		lines = append(lines, receiptLine{Text: padBetween(label, value, columns), Bold: bold})
+	}
+	amount := func(label string, value money.Amount) {
+		if !value.IsZero() {
+			pair(label, value.String(), false)
+		}
+	}
+	rule := func(char string) {
+		lines = append(lines, receiptLine{Text: strings.Repeat(char, columns)})
+	}
+	marker := func(s string) {
+		centre(s)
+		lines[len(lines)-1].Bold = true
+	}
+
+	for _, line := range header {
+		centre(line)
+	}
+	// A voided receipt or a reprint must not pass as the original
+	if receipt.VoidedAt != nil {
+		marker("*** VOID ***")
+		if receipt.VoidReason != "" {
+			centre(receipt.VoidReason)
+		}
+	}
+	if receipt.PrintNumber > 0 {
+		marker(fmt.Sprintf("COPY / REPRINT #%d", receipt.PrintNumber))
+	}
+	rule("=")
+	pair("Receipt No", receipt.ReceiptNumber, false)
+	pair("Date", receipt.CreatedAt.Format("02/01/2006 15:04"), false)
+	if receipt.CashierName != "" {
+		pair("Cashier", receipt.CashierName, false)
+	}
+	rule("-")
+
+	for _, item := range receipt.ReceiptItems {
+		text(item.ProductName)
+		pair(fmt.Sprintf("  %d x %s", item.Quantity, item.UnitPrice), item.TotalPrice.String(), false)
+		for _, modifier := range item.Modifiers {
+			label := "  + " + modifier.Name
+			if modifier.PriceDelta.IsZero() {
+				text(label)
+				continue
+			}
+			pair(label, modifier.PriceDelta.String(), false)
+		}
+		if !item.DiscountAmount.IsZero() {
+			pair("  Discount", "-"+item.DiscountAmount.String(), false)
+		}
+	}
+	rule("-")
+
+	pair("Subtotal", receipt.Subtotal.String(), false)
+	if !receipt.DiscountAmount.IsZero() {
+		pair("Discount", "-"+receipt.DiscountAmount.String(), false)
+	}
+	amount("Service charge", receipt.ServiceChargeAmount)
+	pair("Tax", receipt.TaxAmount.String(), false)
+	amount("Tip", receipt.TipAmount)
+	rule("-")
+	pair("TOTAL", receipt.TotalAmount.String(), true)
+
+	if len(receipt.Payments) == 0 {
+		pair("Payment", strings.ToUpper(receipt.PaymentMethod), false)
+	}
+	for _, payment := range receipt.Payments {
+		pair(strings.ToUpper(payment.Method), payment.Amount.String(), false)
+		if payment.Method == entity.PaymentCash && payment.TenderedAmount.IsPositive() {
+			pair("  Tendered", payment.TenderedAmount.String(), false)
+			pair("  Change", payment.ChangeAmount.String(), false)
+		}
+	}
+	pair("Status", strings.ToUpper(receipt.PaymentStatus), false)
+	rule("=")
+
+	for _, line := range footer {
+		centre(line)
+	}
+	if receipt.VerifyURL != "" {
+		centre("[QR]")
+		lines[len(lines)-1].QR = receipt.VerifyURL
+		centre("Scan to verify this receipt")
+	}
+	return lines
+}
+
+// padBetween puts label on the left and value on the right of a line. A
+// label that does not fit is cut short, the value is always printed whole.
+func padBetween(label, value string, columns int) string {
+	room := columns - utf8.RuneCountInString(value) - 1
+	if room < 0 {
+		room = 0
+	}
+	if runes := []rune(label); len(runes) > room {
+		label = string(runes[:room])
+	}
+	pad := columns - utf8.RuneCountInString(label) - utf8.RuneCountInString(value)
+	if pad < 0 {
+		pad = 0
+	}
+	return label + strings.Repeat(" ", pad) + value
+}
+
+// wrapText breaks s into lines of at most columns characters, at a space
+// where there is one.
+func wrapText(s string, columns int) []string {
+	var lines []string
+	runes := []rune(s)
+	for len(runes) > columns {
+		cut := columns
+		for i := columns; i > 0; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	return append(lines, string(runes))
+}
+
+// renderReceiptPDF draws the lines on the paper, going on to a new page when
+// a page is full. A roll is made as long as the receipt.
+func renderReceiptPDF(lines []receiptLine, paper receiptPaper) []byte {
+	leading := paper.FontSize * receiptLineHeight
+
+	// A QR code takes its own height and a line of space above and below. A
+	// link too long to encode is left out.
+	codes := make([]*qr.Code, len(lines))
+	heights := make([]float64, len(lines))
+	total := 0.0
+	for i, line := range lines {
+		heights[i] = leading
+		if line.QR != "" {
+			if code, err := qr.Encode(line.QR); err == nil {
+				codes[i] = code
+				heights[i] = receiptQRSize + 2*leading
+			}
+		}
+		total += heights[i]
+	}
+
+	height := paper.Height
+	if height == 0 {
+		height = 2*paper.Margin + total
+	}
+
+	doc := pdf.New(paper.Width, height)
+	y := paper.Margin
+	for i, line := range lines {
+		// The small margin keeps rounding from pushing the last line of a
+		// roll on to a page of its own
+		if i == 0 || y+heights[i] > height-paper.Margin+1e-6 {
+			doc.AddPage()
+			y = paper.Margin
+		}
+		switch {
+		case codes[i] != nil:
+			drawQR(doc, codes[i], (paper.Width-receiptQRSize)/2, y+leading, receiptQRSize)
+		case line.QR == "":
+			font := pdf.Courier
+			if line.Bold {
+				font = pdf.CourierBold
+			}
+			doc.Text(paper.Margin, y+leading, font, paper.FontSize, line.Text)
+		}
+		y += heights[i]
+	}
+	return doc.Bytes()
+}
+
+// drawQR draws the code size points wide with its top left corner at x, y.
+// Dark modules next to each other in a row are drawn as one rectangle.
+func drawQR(doc *pdf.Document, code *qr.Code, x, y, size float64) {
+	module := size / float64(code.Size)
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; {
+			if !code.Black(col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < code.Size && code.Black(col, row) {
+				col++
+			}
+			doc.Rect(x+float64(start)*module, y+float64(row)*module, float64(col-start)*module, module)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+func printTestReceipt() *entity.Receipt {
+	return &entity.Receipt{
+		ReceiptNumber:  "RCP202602030001",
+		CashierName:    "Sari",
+		StoreName:      "Cuaniaga Store",
+		StoreAddress:   "Jl. Raya No. 123",
+		Subtotal:       money.New(55000),
+		DiscountAmount: money.New(5000),
+		TaxAmount:      money.New(5000),
+		TotalAmount:    money.New(55000),
+		PaymentMethod:  entity.PaymentCash,
+		PaymentStatus:  "paid",
+		CreatedAt:      time.Date(2026, 2, 3, 14, 5, 0, 0, time.Local),
+		ReceiptItems: []entity.ReceiptItem{
+			{ProductName: "Es Kopi Susu Gula Aren Ukuran Besar Sekali Ya", Quantity: 2, UnitPrice: money.New(25000), TotalPrice: money.New(50000),
+				Modifiers: []entity.ReceiptItemModifier{{Name: "Size: Large", PriceDelta: money.New(5000)}, {Name: "Sugar: Less"}}},
+			{ProductName: "Croissant", Quantity: 1, UnitPrice: money.New(5000), TotalPrice: money.New(5000), DiscountAmount: money.New(5000)},
+		},
+		Payments: []entity.OrderPayment{
+			{Method: entity.PaymentCash, Amount: money.New(55000), TenderedAmount: money.New(60000), ChangeAmount: money.New(5000)},
+		},
+	}
+}
+
+// TestLayoutReceipt tests that every line fills the paper width and the totals line up
+func TestLayoutReceipt(t *testing.T) {
+	lines := layoutReceipt(printTestReceipt(), []string{"Cuaniaga Store"}, []string{"Thank you"}, 42)
+
+	var printed []string
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line.Text); n > 42 {
+			t.Errorf("Expected at most 42 characters, got %d in %q", n, line.Text)
+		}
+		if line.Bold && !strings.HasPrefix(line.Text, "TOTAL") {
+			t.Errorf("Expected only the total in bold, got %q", line.Text)
+		}
+		printed = append(printed, line.Text)
+	}
+	text := strings.Join(printed, "\n")
+
+	for _, want := range []string{
+		"              Cuaniaga Store\n",
+		"Receipt No                 RCP202602030001\n",
+		"Date                      03/02/2026 14:05\n",
+		"Es Kopi Susu Gula Aren Ukuran Besar Sekali\nYa\n",
+		"  2 x 25000.00                    50000.00\n",
+		"  + Size: Large                    5000.00\n",
+		"  + Sugar: Less\n",
+		"  Discount                        -5000.00\n",
+		"TOTAL                             55000.00\n",
+		"  Tendered                        60000.00\n",
+		"  Change                           5000.00\n",
+		"                Thank you",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected printed receipt to contain %q, got\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Service charge") || strings.Contains(text, "Tip") {
+		t.Error("Expected zero service charge and tip to be left out")
+	}
+}
+
+// TestLayoutReceiptMarkers tests that voided and reprinted receipts are marked under the header
+func TestLayoutReceiptMarkers(t *testing.T) {
+	receipt := printTestReceipt()
+	voidedAt := time.Date(2026, 2, 3, 15, 0, 0, 0, time.Local)
+	receipt.VoidedAt = &voidedAt
+	receipt.VoidReason = "Wrong table"
+	receipt.PrintNumber = 2
+
+	lines := layoutReceipt(receipt, []string{"Cuaniaga Store"}, nil, 42)
+	want := []receiptLine{
+		{Text: "              Cuaniaga Store"},
+		{Text: "               *** VOID ***", Bold: true},
+		{Text: "               Wrong table"},
+		{Text: "            COPY / REPRINT #2", Bold: true},
+		{Text: strings.Repeat("=", 42)},
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("Line %d: expected %+v, got %+v", i, line, lines[i])
+		}
+	}
+
+	receipt.PrintNumber = 0
+	receipt.VoidedAt = nil
+	for _, line := range layoutReceipt(receipt, nil, nil, 42) {
+		if strings.Contains(line.Text, "VOID") || strings.Contains(line.Text, "REPRINT") {
+			t.Errorf("Expected no marker on the original, got %q", line.Text)
+		}
+	}
+}
+
+// TestLayoutReceiptQR tests that the verify link goes below the footer
+func TestLayoutReceiptQR(t *testing.T) {
+	receipt := printTestReceipt()
+	receipt.VerifyURL = "https://pos.example.com/verify?n=RCP202602030001"
+
+	lines := layoutReceipt(receipt, nil, []string{"Thank you"}, 42)
+	last := lines[len(lines)-2:]
+	if last[0].QR != receipt.VerifyURL || strings.TrimSpace(last[0].Text) != "[QR]" {
+		t.Errorf("Expected the QR line after the footer, got %+v", last[0])
+	}
+	if strings.TrimSpace(last[1].Text) != "Scan to verify this receipt" {
+		t.Errorf("Expected the QR caption, got %q", last[1].Text)
+	}
+}
+
+// TestReceiptTemplateLines tests that header templates read the receipt
+func TestReceiptTemplateLines(t *testing.T) {
+	lines, err := receiptTemplateLines(entity.DefaultStoreSettings[entity.SettingReceiptHeader], printTestReceipt())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "Cuaniaga Store" || lines[1] != "Jl. Raya No. 123" {
+		t.Errorf("Expected store name and address without the empty phone line, got %q", lines)
+	}
+
+	if _, err := receiptTemplateLines("{{.Outlet}}", printTestReceipt()); err == nil {
+		t.Error("Expected error for an unknown field")
+	}
+}
+
+// TestPadBetween tests labels are cut short rather than the value
+func TestPadBetween(t *testing.T) {
+	if got := padBetween("Subtotal", "5000.00", 20); got != "Subtotal     5000.00" {
+		t.Errorf("Expected padded line, got %q", got)
+	}
+	if got := padBetween("A very long product name", "5000.00", 20); got != "A very long  5000.00" {
+		t.Errorf("Expected cut label, got %q", got)
+	}
+	if got := padBetween("Total", "123456789012345678901", 20); got != "123456789012345678901" {
+		t.Errorf("Expected the whole value, got %q", got)
+	}
+}
+
+// TestRenderReceiptPDF tests that a roll fits the receipt on one page and A4 goes on to more
+func TestRenderReceiptPDF(t *testing.T) {
+	lines := make([]receiptLine, 100)
+	for i := range lines {
+		lines[i] = receiptLine{Text: "line"}
+	}
+
+	roll := renderReceiptPDF(lines, receiptPapers[entity.ReceiptPaper80mm])
+	if !bytes.Contains(roll, []byte("/Count 1")) {
+		t.Error("Expected an 80mm receipt on one page")
+	}
+	a4 := renderReceiptPDF(lines, receiptPapers[entity.ReceiptPaperA4])
+	if !bytes.Contains(a4, []byte("/Count 2")) {
+		t.Error("Expected 100 lines to go on to a second A4 page")
+	}
+
+	withQR := renderReceiptPDF([]receiptLine{{Text: "TOTAL"}, {Text: "[QR]", QR: "https://pos.example.com/verify"}}, receiptPapers[entity.ReceiptPaper80mm])
+	if !bytes.Contains(withQR, []byte(" re f\n")) {
+		t.Error("Expected the QR code drawn as rectangles")
+	}
+	if bytes.Contains(withQR, []byte("([QR])")) {
+		t.Error("Expected the QR placeholder text left out of the PDF")
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// TestSummarizeReceiptAudit tests that voids go to the receipt's cashier and reprints to whoever printed them
+func TestSummarizeReceiptAudit(t *testing.T) {
+	day := time.Date(2026, 2, 3, 0, 0, 0, 0, time.Local)
+	voids := []entity.ReceiptVoidEntry{
+		{ReceiptNumber: "RCP202602030001", CashierName: "Sari", TotalAmount: money.New(55000), VoidReason: "Wrong table"},
+		{ReceiptNumber: "RCP202602030004", CashierName: "Sari", TotalAmount: money.New(20000), VoidReason: "Duplicate"},
+		{ReceiptNumber: "RCP202602030007", TotalAmount: money.New(10000), VoidReason: "Test"},
+	}
+	reprints := []entity.ReceiptReprintEntry{
+		{ReceiptNumber: "RCP202602030001", PrintNumber: 1, Format: "pdf", PrintedByName: "Budi"},
+		{ReceiptNumber: "RCP202602030001", PrintNumber: 2, Format: "escpos", PrintedByName: "Sari"},
+	}
+
+	report := summarizeReceiptAudit(day, day, voids, reprints)
+
+	if report.VoidCount != 3 || report.VoidAmount != money.New(85000) || report.ReprintCount != 2 {
+		t.Errorf("Expected 3 voids of 85000.00 and 2 reprints, got %d of %s and %d", report.VoidCount, report.VoidAmount, report.ReprintCount)
+	}
+	if len(report.Cashiers) != 3 {
+		t.Fatalf("Expected 3 cashiers, got %d", len(report.Cashiers))
+	}
+
+	tests := []struct {
+		name     string
+		voids    int
+		amount   money.Amount
+		reprints int
+	}{
+		{name: "Budi", voids: 0, amount: money.Zero, reprints: 1},
+		{name: "Sari", voids: 2, amount: money.New(75000), reprints: 1},
+		{name: "Unknown", voids: 1, amount: money.New(10000), reprints: 0},
+	}
+	for i, tt := range tests {
+		got := report.Cashiers[i]
+		if got.CashierName != tt.name || got.VoidCount != tt.voids || got.VoidAmount != tt.amount || got.ReprintCount != tt.reprints {
+			t.Errorf("Expected %s with %d voids of %s and %d reprints, got %s with %d of %s and %d",
+				tt.name, tt.voids, tt.amount, tt.reprints, got.CashierName, got.VoidCount, got.VoidAmount, got.ReprintCount)
+		}
+		if len(got.Voids) != tt.voids || len(got.Reprints) != tt.reprints {
+			t.Errorf("Expected the entries of %s to be listed", tt.name)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// receiptSignatureSize is how many bytes of the HMAC are kept. 128 bits is
+// plenty against forgery and keeps the QR code small.
+const receiptSignatureSize = 16
+
+// signReceipt signs the receipt number, total and issue time printed in the
+// QR code of a receipt.
+func signReceipt(key []byte, number string, total money.Amount, issuedAt int64) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(number + "\n" + total.String() + "\n" + strconv.FormatInt(issuedAt, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:receiptSignatureSize])
+}
+
+// checkReceiptSignature tells whether signature was made by signReceipt with
+// the same key.
+func checkReceiptSignature(key []byte, number string, total money.Amount, issuedAt int64, signature string) bool {
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	want, _ := base64.RawURLEncoding.DecodeString(signReceipt(key, number, total, issuedAt))
+	return hmac.Equal(got, want)
+}
+
+// receiptVerifyURL is the signed link to the verify endpoint at base that is
+// printed as the QR code of the receipt.
+func receiptVerifyURL(base string, key []byte, receipt *entity.Receipt) string {
+	issuedAt := receipt.CreatedAt.Unix()
+	query := url.Values{}
+	query.Set("n", receipt.ReceiptNumber)
+	query.Set("t", receipt.TotalAmount.String())
+	query.Set("ts", strconv.FormatInt(issuedAt, 10))
+	query.Set("s", signReceipt(key, receipt.ReceiptNumber, receipt.TotalAmount, issuedAt))
+	return base + "?" + query.Encode()
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+// TestReceiptSignature tests that a signature only checks out for the fields and key it was made with
+func TestReceiptSignature(t *testing.T) {
+	key := []byte("secret")
+	signature := signReceipt(key, "RCP202602030001", money.New(55000), 1770102300)
+
+	if !checkReceiptSignature(key, "RCP202602030001", money.New(55000), 1770102300, signature) {
+		t.Fatal("Expected the signature to check out")
+	}
+
+	tests := []struct {
+		name      string
+		key       []byte
+		number    string
+		total     money.Amount
+		issuedAt  int64
+		signature string
+	}{
+		{name: "other key", key: []byte("other"), number: "RCP202602030001", total: money.New(55000), issuedAt: 1770102300, signature: signature},
+		{name: "other number", key: key, number: "RCP202602030002", total: money.New(55000), issuedAt: 1770102300, signature: signature},
+		{name: "other total", key: key, number: "RCP202602030001", total: money.New(5500), issuedAt: 1770102300, signature: signature},
+		{name: "other time", key: key, number: "RCP202602030001", total: money.New(55000), issuedAt: 1770102301, signature: signature},
+		{name: "not base64", key: key, number: "RCP202602030001", total: money.New(55000), issuedAt: 1770102300, signature: "!!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if checkReceiptSignature(tt.key, tt.number, tt.total, tt.issuedAt, tt.signature) {
+				t.Error("Expected the signature to be rejected")
+			}
+		})
+	}
+}
+
+// TestReceiptVerifyURL tests that the link carries everything the verify endpoint checks
+func TestReceiptVerifyURL(t *testing.T) {
+	key := []byte("secret")
+	receipt := printTestReceipt()
+
+	link := receiptVerifyURL("https://pos.example.com/app/api/v1/receipts/verify", key, receipt)
+	if !strings.HasPrefix(link, "https://pos.example.com/app/api/v1/receipts/verify?") {
+		t.Fatalf("Expected the link to the verify endpoint, got %s", link)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+
+	total, err := money.Parse(query.Get("t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedAt, err := strconv.ParseInt(query.Get("ts"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("n") != receipt.ReceiptNumber || total != receipt.TotalAmount || issuedAt != receipt.CreatedAt.Unix() {
+		t.Errorf("Expected the receipt fields in the link, got %s", link)
+	}
+	if !checkReceiptSignature(key, query.Get("n"), total, issuedAt, query.Get("s")) {
+		t.Error("Expected the signature in the link to check out")
+	}
+}
+
+// numberedReceipts finds receipts by number
+type numberedReceipts struct {
+	repository.ReceiptRepository
+	receipts map[string]*entity.Receipt
+}
+
+func (r *numberedReceipts) GetReceiptByNumber(receiptNumber string) (*entity.Receipt, error) {
+	if receipt, ok := r.receipts[receiptNumber]; ok {
+		return receipt, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// TestVerifyReceipt tests that a link only verifies with the total and issue time of the stored receipt
+func TestVerifyReceipt(t *testing.T) {
+	key := []byte("secret")
+	receipt := printTestReceipt()
+	s := &receiptService{
+		receiptRepo: &numberedReceipts{receipts: map[string]*entity.Receipt{receipt.ReceiptNumber: receipt}},
+		signingKey:  key,
+	}
+	issuedAt := receipt.CreatedAt.Unix()
+	verify := func(total money.Amount, issuedAt int64) error {
+		_, err := s.VerifyReceipt(receipt.ReceiptNumber, total.String(), strconv.FormatInt(issuedAt, 10),
+			signReceipt(key, receipt.ReceiptNumber, total, issuedAt))
+		return err
+	}
+
+	if err := verify(receipt.TotalAmount, issuedAt); err != nil {
+		t.Fatalf("Expected the receipt to verify, got %v", err)
+	}
+	if err := verify(money.New(1000), issuedAt); err == nil {
+		t.Error("Expected a signed link with another total to be rejected")
+	}
+	if err := verify(receipt.TotalAmount, issuedAt+3600); err == nil {
+		t.Error("Expected a signed link with another issue time to be rejected")
+	}
+}
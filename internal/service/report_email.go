@@ -0,0 +1,58 @@
+package service
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+)
+
+// reportEmailTemplate is the body of a scheduled sales report email. The full
+// report, with every order line, is attached as CSV or XLSX.
+var reportEmailTemplate = template.Must(template.New("report_email").Funcs(template.FuncMap{
+	"upper": strings.ToUpper,
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; color: #222;">
+<h2 style="margin-bottom: 0;">{{.StoreName}}</h2>
+<p style="margin-top: 4px; color: #666;">{{.Title}}</p>
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse; min-width: 360px;">
+<tr><td>Gross sales</td><td align="right">{{.Report.GrossSales}}</td></tr>
+<tr><td>Discount</td><td align="right">-{{.Report.TotalDiscount}}</td></tr>
+<tr><td>Tax</td><td align="right">{{.Report.TotalTax}}</td></tr>
+{{if not .Report.ServiceChargeAmount.IsZero}}<tr><td>Service charge</td><td align="right">{{.Report.ServiceChargeAmount}}</td></tr>
+{{end}}<tr style="border-top: 1px solid #ccc;"><td><strong>Total sales</strong></td><td align="right"><strong>{{.Report.TotalSales}}</strong></td></tr>
+{{if not .Report.TipAmount.IsZero}}<tr><td>Tips</td><td align="right">{{.Report.TipAmount}}</td></tr>
+{{end}}<tr><td>Transactions</td><td align="right">{{.Report.TotalTransactions}}</td></tr>
+<tr><td>Average transaction</td><td align="right">{{.Report.AverageTransactionValue}}</td></tr>
+<tr><td>Customers</td><td align="right">{{.Report.TotalCustomers}}</td></tr>
+</table>
+{{if .Report.PaymentMethodBreakdown}}<h3>Payment methods</h3>
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse; min-width: 360px;">
+<tr style="border-bottom: 1px solid #ccc;"><th align="left">Method</th><th align="right">Orders</th><th align="right">Amount</th></tr>
+{{range .Report.PaymentMethodBreakdown}}<tr><td>{{upper .PaymentMethod}}</td><td align="right">{{.Count}}</td><td align="right">{{.TotalAmount}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Report.TopProducts}}<h3>Top products</h3>
+<table cellpadding="4" cellspacing="0" style="border-collapse: collapse; min-width: 360px;">
+<tr style="border-bottom: 1px solid #ccc;"><th align="left">Product</th><th align="right">Qty</th><th align="right">Revenue</th></tr>
+{{range .Report.TopProducts}}<tr><td>{{.ProductName}}</td><td align="right">{{.QuantitySold}}</td><td align="right">{{.TotalRevenue}}</td></tr>
+{{end}}</table>
+{{end}}<p>The full report with every order line is attached.</p>
+</body>
+</html>
+`))
+
+// reportEmailHTML is the HTML body of a scheduled report email.
+func reportEmailHTML(report *entity.SalesReport, title, storeName string) (string, error) {
+	var out bytes.Buffer
+	err := reportEmailTemplate.Execute(&out, struct {
+		Report    *entity.SalesReport
+		Title     string
+		StoreName string
+	}{report, title, storeName})
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
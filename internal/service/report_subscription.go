@@ -0,0 +1,324 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/email"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxReportAttempts is how many times a report is sent before its
+	// delivery is marked failed.
+	maxReportAttempts = 5
+	// reportRetryDelay is the wait before the first retry. It doubles with
+	// every failed attempt.
+	reportRetryDelay = 10 * time.Minute
+	// reportSendTimeout is how long a claimed delivery is left to one process
+	// before another may try it.
+	reportSendTimeout = 15 * time.Minute
+	// reportDeliveryHistory is how many deliveries of a subscription are listed.
+	reportDeliveryHistory = 100
+)
+
+// ReportSubscriptionService emails sales reports to admins on a schedule.
+// RunDueReports is called by the report worker every minute.
+type ReportSubscriptionService interface {
+	CreateSubscription(subscription *entity.ReportSubscription) (*entity.ReportSubscription, error)
+	UpdateSubscription(subscription *entity.ReportSubscription) (*entity.ReportSubscription, error)
+	DeleteSubscription(subscriptionID uuid.UUID) error
+	FindAllSubscriptions() ([]entity.ReportSubscription, error)
+	GetDeliveries(subscriptionID uuid.UUID) ([]entity.ReportDelivery, error)
+	RunDueReports(at time.Time) error
+}
+
+type reportSubscriptionService struct {
+	subscriptionRepo   repository.ReportSubscriptionRepository
+	salesReportService SalesReportService
+	settingService     SettingService
+	emailSender        email.EmailSenderService
+}
+
+func NewReportSubscriptionService(subscriptionRepo repository.ReportSubscriptionRepository, salesReportService SalesReportService,
+	settingService SettingService, emailSender email.EmailSenderService) *reportSubscriptionService {
+	return &reportSubscriptionService{
+		subscriptionRepo:   subscriptionRepo,
+		salesReportService: salesReportService,
+		settingService:     settingService,
+		emailSender:        emailSender,
+	}
+}
+
+func (s *reportSubscriptionService) CreateSubscription(subscription *entity.ReportSubscription) (*entity.ReportSubscription, error) {
+	if err := validateReportSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	subscription.SubscriptionID = uuid.New()
+	subscription.CreatedAt = time.Now()
+	subscription.UpdatedAt = time.Now()
+	if err := s.subscriptionRepo.CreateSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *reportSubscriptionService) UpdateSubscription(subscription *entity.ReportSubscription) (*entity.ReportSubscription, error) {
+	existing, err := s.findSubscription(subscription.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateReportSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	subscription.CreatedBy = existing.CreatedBy
+	subscription.CreatedAt = existing.CreatedAt
+	subscription.UpdatedAt = time.Now()
+	if err := s.subscriptionRepo.UpdateSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// DeleteSubscription removes the subscription together with its delivery
+// history. Deactivate it to keep the history.
+func (s *reportSubscriptionService) DeleteSubscription(subscriptionID uuid.UUID) error {
+	if _, err := s.findSubscription(subscriptionID); err != nil {
+		return err
+	}
+	return s.subscriptionRepo.DeleteSubscription(subscriptionID)
+}
+
+func (s *reportSubscriptionService) FindAllSubscriptions() ([]entity.ReportSubscription, error) {
+	return s.subscriptionRepo.FindAllSubscriptions()
+}
+
+func (s *reportSubscriptionService) GetDeliveries(subscriptionID uuid.UUID) ([]entity.ReportDelivery, error) {
+	if _, err := s.findSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.FindDeliveries(subscriptionID, reportDeliveryHistory)
+}
+
+func (s *reportSubscriptionService) findSubscription(subscriptionID uuid.UUID) (*entity.ReportSubscription, error) {
+	subscription, err := s.subscriptionRepo.FindSubscriptionByID(subscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report subscription not found")
+		}
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// RunDueReports queues the latest closed period of every active subscription
+// and sends the deliveries that are due, including retries. Only the latest
+// period is queued, so periods missed while the server was down are skipped.
+// Periods and send hours follow the store's time zone. It keeps going when a
+// report fails.
+func (s *reportSubscriptionService) RunDueReports(at time.Time) error {
+	at = at.In(s.settingService.Location())
+	subscriptions, err := s.subscriptionRepo.FindActiveSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	active := make(map[uuid.UUID]entity.ReportSubscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		active[subscription.SubscriptionID] = subscription
+
+		start, end, sendAt := reportPeriod(subscription, at)
+		// A new subscription starts with the next report that is due
+		if sendAt.Before(subscription.CreatedAt) {
+			continue
+		}
+		delivery := &entity.ReportDelivery{
+			DeliveryID:     uuid.New(),
+			SubscriptionID: subscription.SubscriptionID,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			Status:         entity.ReportDeliveryPending,
+			NextAttemptAt:  &sendAt,
+			CreatedAt:      at,
+			UpdatedAt:      at,
+		}
+		if err := s.subscriptionRepo.CreateDelivery(delivery); err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", subscription.SubscriptionID, err))
+		}
+	}
+
+	deliveries, err := s.subscriptionRepo.FindDueDeliveries(at)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		claimed, err := s.subscriptionRepo.ClaimDelivery(delivery, at.Add(reportSendTimeout))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("delivery %s: %w", delivery.DeliveryID, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		// A subscription deactivated before its report went out is not sent late
+		var sendErr error
+		subscription, ok := active[delivery.SubscriptionID]
+		if ok {
+			sendErr = s.sendReport(subscription, delivery)
+			recordReportAttempt(delivery, sendErr, at)
+		} else {
+			delivery.Status = entity.ReportDeliveryFailed
+			delivery.LastError = "subscription is inactive"
+			delivery.NextAttemptAt = nil
+		}
+		delivery.UpdatedAt = time.Now()
+		if err := s.subscriptionRepo.UpdateDelivery(delivery); err != nil {
+			errs = append(errs, fmt.Errorf("delivery %s: %w", delivery.DeliveryID, err))
+		}
+		if sendErr != nil {
+			errs = append(errs, fmt.Errorf("delivery %s: %w", delivery.DeliveryID, sendErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sendReport emails the report of the delivery's period with the report
+// attached in the subscription's format.
+func (s *reportSubscriptionService) sendReport(subscription entity.ReportSubscription, delivery *entity.ReportDelivery) error {
+	if s.emailSender == nil {
+		return errors.New("email sender is not configured")
+	}
+
+	// The period is read back from the database, so it is put in store time
+	// before its calendar day is taken
+	periodStart := delivery.PeriodStart.In(s.settingService.Location())
+	var report *entity.SalesReport
+	var err error
+	if subscription.ReportType == entity.ReportTypeMonthly {
+		report, err = s.salesReportService.GetMonthlySalesReport(periodStart.Year(), periodStart.Month())
+	} else {
+		report, err = s.salesReportService.GetDailySalesReport(periodStart)
+	}
+	if err != nil {
+		return err
+	}
+
+	storeName := s.settingService.StoreName()
+	title := reportEmailTitle(subscription.ReportType, periodStart)
+	body, err := reportEmailHTML(report, title, storeName)
+	if err != nil {
+		return err
+	}
+
+	var data bytes.Buffer
+	if err := s.salesReportService.ExportSalesReport(report, subscription.Format, &data); err != nil {
+		return err
+	}
+	attachment := email.Attachment{
+		Filename:    reportAttachmentName(subscription.ReportType, periodStart, subscription.Format),
+		ContentType: ReportContentType(subscription.Format),
+		Data:        data.Bytes(),
+	}
+
+	return s.emailSender.SendHTMLEmail(subscription.RecipientList(), title+" | "+storeName, body, []email.Attachment{attachment})
+}
+
+// recordReportAttempt updates the delivery with the outcome of a send at the
+// given time. A failure is retried later with a doubling delay, until
+// maxReportAttempts is reached.
+func recordReportAttempt(delivery *entity.ReportDelivery, sendErr error, at time.Time) {
+	delivery.Attempts++
+	if sendErr == nil {
+		delivery.Status = entity.ReportDeliverySent
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.SentAt = &at
+		return
+	}
+
+	delivery.LastError = sendErr.Error()
+	if delivery.Attempts >= maxReportAttempts {
+		delivery.Status = entity.ReportDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := at.Add(reportRetryDelay << (delivery.Attempts - 1))
+	delivery.NextAttemptAt = &next
+}
+
+// reportPeriod is the latest period of the subscription whose report is due
+// at the given time, and the time it became due. Daily reports cover the day
+// before and monthly reports the month before, both sent at SendHour.
+func reportPeriod(subscription entity.ReportSubscription, at time.Time) (start, end, sendAt time.Time) {
+	if subscription.ReportType == entity.ReportTypeMonthly {
+		end = time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, at.Location())
+		if at.Before(end.Add(time.Duration(subscription.SendHour) * time.Hour)) {
+			end = end.AddDate(0, -1, 0)
+		}
+		return end.AddDate(0, -1, 0), end, end.Add(time.Duration(subscription.SendHour) * time.Hour)
+	}
+
+	end = time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	if at.Before(end.Add(time.Duration(subscription.SendHour) * time.Hour)) {
+		end = end.AddDate(0, 0, -1)
+	}
+	return end.AddDate(0, 0, -1), end, end.Add(time.Duration(subscription.SendHour) * time.Hour)
+}
+
+func reportEmailTitle(reportType string, periodStart time.Time) string {
+	if reportType == entity.ReportTypeMonthly {
+		return "Monthly Sales Report " + periodStart.Format("January 2006")
+	}
+	return "Daily Sales Report " + periodStart.Format("02/01/2006")
+}
+
+func reportAttachmentName(reportType string, periodStart time.Time, format string) string {
+	if reportType == entity.ReportTypeMonthly {
+		return "sales-report-" + periodStart.Format("2006-01") + "." + format
+	}
+	return "sales-report-" + periodStart.Format("2006-01-02") + "." + format
+}
+
+// validateReportSubscription checks the subscription and tidies its
+// recipients into a comma separated list. Format defaults to xlsx.
+func validateReportSubscription(subscription *entity.ReportSubscription) error {
+	if subscription.ReportType != entity.ReportTypeDaily && subscription.ReportType != entity.ReportTypeMonthly {
+		return errors.New("report_type must be daily or monthly")
+	}
+	if subscription.SendHour < 0 || subscription.SendHour > 23 {
+		return errors.New("send_hour must be between 0 and 23")
+	}
+	if subscription.Format == "" {
+		subscription.Format = entity.ReportFormatXLSX
+	}
+	if subscription.Format != entity.ReportFormatCSV && subscription.Format != entity.ReportFormatXLSX {
+		return errors.New("format must be csv or xlsx")
+	}
+
+	recipients := subscription.RecipientList()
+	if len(recipients) == 0 {
+		return errors.New("at least one recipient is required")
+	}
+	for _, recipient := range recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil || address.Address != recipient {
+			return fmt.Errorf("invalid recipient %q", recipient)
+		}
+	}
+	subscription.Recipients = strings.Join(recipients, ",")
+	return nil
+}
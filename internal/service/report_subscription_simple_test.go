@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/email"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// TestReportPeriod tests that a report covers the last closed period once its send hour has passed
+func TestReportPeriod(t *testing.T) {
+	loc := time.FixedZone("WIB", 7*60*60)
+	date := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name       string
+		reportType string
+		at         time.Time
+		start      time.Time
+		sendAt     time.Time
+	}{
+		{name: "daily after send hour", reportType: entity.ReportTypeDaily, at: date(3, 10, 8), start: date(3, 9, 0), sendAt: date(3, 10, 7)},
+		{name: "daily before send hour", reportType: entity.ReportTypeDaily, at: date(3, 10, 6), start: date(3, 8, 0), sendAt: date(3, 9, 7)},
+		{name: "daily over a month end", reportType: entity.ReportTypeDaily, at: date(3, 1, 7), start: date(2, 28, 0), sendAt: date(3, 1, 7)},
+		{name: "monthly on the 1st", reportType: entity.ReportTypeMonthly, at: date(3, 1, 9), start: date(2, 1, 0), sendAt: date(3, 1, 7)},
+		{name: "monthly before send hour", reportType: entity.ReportTypeMonthly, at: date(3, 1, 6), start: date(1, 1, 0), sendAt: date(2, 1, 7)},
+		{name: "monthly later in the month", reportType: entity.ReportTypeMonthly, at: date(3, 20, 0), start: date(2, 1, 0), sendAt: date(3, 1, 7)},
+	}
+
+	for _, tt := range tests {
+		subscription := entity.ReportSubscription{ReportType: tt.reportType, SendHour: 7}
+		start, end, sendAt := reportPeriod(subscription, tt.at)
+		if !start.Equal(tt.start) || !sendAt.Equal(tt.sendAt) {
+			t.Errorf("%s: expected %s sent at %s, got %s sent at %s", tt.name, tt.start, tt.sendAt, start, sendAt)
+		}
+		if end.Hour() != 0 || !end.After(start) {
+			t.Errorf("%s: expected the period to end at midnight after %s, got %s", tt.name, start, end)
+		}
+	}
+}
+
+// TestRecordReportAttempt tests that failures are retried with a doubling delay before giving up
+func TestRecordReportAttempt(t *testing.T) {
+	at := time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC)
+	delivery := &entity.ReportDelivery{Status: entity.ReportDeliveryPending}
+
+	for attempt := 1; attempt < maxReportAttempts; attempt++ {
+		recordReportAttempt(delivery, errors.New("smtp timeout"), at)
+		want := at.Add(reportRetryDelay << (attempt - 1))
+		if delivery.Status != entity.ReportDeliveryPending || delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.Equal(want) {
+			t.Fatalf("Attempt %d: expected a retry at %s, got %+v", attempt, want, delivery)
+		}
+	}
+	recordReportAttempt(delivery, errors.New("smtp timeout"), at)
+	if delivery.Status != entity.ReportDeliveryFailed || delivery.NextAttemptAt != nil || delivery.LastError != "smtp timeout" {
+		t.Errorf("Expected the delivery to fail after %d attempts, got %+v", maxReportAttempts, delivery)
+	}
+
+	delivery = &entity.ReportDelivery{Status: entity.ReportDeliveryPending, Attempts: 1, LastError: "smtp timeout"}
+	recordReportAttempt(delivery, nil, at)
+	if delivery.Status != entity.ReportDeliverySent || delivery.SentAt == nil || delivery.LastError != "" || delivery.Attempts != 2 {
+		t.Errorf("Expected the delivery to be sent on the second attempt, got %+v", delivery)
+	}
+}
+
+// TestValidateReportSubscription tests the checks on a subscription and the tidying of its recipients
+func TestValidateReportSubscription(t *testing.T) {
+	subscription := &entity.ReportSubscription{ReportType: entity.ReportTypeDaily, SendHour: 7, Recipients: " owner@example.com, ,finance@example.com "}
+	if err := validateReportSubscription(subscription); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if subscription.Recipients != "owner@example.com,finance@example.com" || subscription.Format != entity.ReportFormatXLSX {
+		t.Errorf("Expected tidy recipients and the xlsx default, got %q and %q", subscription.Recipients, subscription.Format)
+	}
+
+	tests := []struct {
+		name         string
+		subscription entity.ReportSubscription
+	}{
+		{name: "unknown type", subscription: entity.ReportSubscription{ReportType: "weekly", Recipients: "owner@example.com"}},
+		{name: "send hour", subscription: entity.ReportSubscription{ReportType: entity.ReportTypeDaily, SendHour: 24, Recipients: "owner@example.com"}},
+		{name: "format", subscription: entity.ReportSubscription{ReportType: entity.ReportTypeDaily, Format: "pdf", Recipients: "owner@example.com"}},
+		{name: "no recipients", subscription: entity.ReportSubscription{ReportType: entity.ReportTypeMonthly, Recipients: " , "}},
+		{name: "bad recipient", subscription: entity.ReportSubscription{ReportType: entity.ReportTypeMonthly, Recipients: "Owner <owner@example.com>"}},
+	}
+	for _, tt := range tests {
+		if err := validateReportSubscription(&tt.subscription); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+// deliveryRepository keeps subscriptions and deliveries in memory.
+type deliveryRepository struct {
+	repository.ReportSubscriptionRepository
+	subscriptions []entity.ReportSubscription
+	deliveries    []entity.ReportDelivery
+}
+
+func (r *deliveryRepository) FindActiveSubscriptions() ([]entity.ReportSubscription, error) {
+	return r.subscriptions, nil
+}
+
+func (r *deliveryRepository) CreateDelivery(delivery *entity.ReportDelivery) error {
+	for _, existing := range r.deliveries {
+		if existing.SubscriptionID == delivery.SubscriptionID && existing.PeriodStart.Equal(delivery.PeriodStart) {
+			return nil
+		}
+	}
+	r.deliveries = append(r.deliveries, *delivery)
+	return nil
+}
+
+func (r *deliveryRepository) FindDueDeliveries(at time.Time) ([]entity.ReportDelivery, error) {
+	var due []entity.ReportDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == entity.ReportDeliveryPending && !delivery.NextAttemptAt.After(at) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *deliveryRepository) ClaimDelivery(delivery *entity.ReportDelivery, until time.Time) (bool, error) {
+	delivery.NextAttemptAt = &until
+	return true, nil
+}
+
+func (r *deliveryRepository) UpdateDelivery(delivery *entity.ReportDelivery) error {
+	for i := range r.deliveries {
+		if r.deliveries[i].DeliveryID == delivery.DeliveryID {
+			r.deliveries[i] = *delivery
+		}
+	}
+	return nil
+}
+
+// dailyReportService returns an empty report for any day.
+type dailyReportService struct {
+	SalesReportService
+}
+
+func (s *dailyReportService) GetDailySalesReport(date time.Time) (*entity.SalesReport, error) {
+	return &entity.SalesReport{PeriodStartDate: date, PeriodEndDate: date.AddDate(0, 0, 1), TotalSales: money.New(150000)}, nil
+}
+
+func (s *dailyReportService) ExportSalesReport(report *entity.SalesReport, format string, w io.Writer) error {
+	_, err := w.Write([]byte("Summary\n"))
+	return err
+}
+
+// fixedSettings is a store in Jakarta time, without needing the zone database.
+type fixedSettings struct {
+	SettingService
+}
+
+func (fixedSettings) StoreName() string {
+	return "Toko Budi"
+}
+
+func (fixedSettings) Location() *time.Location {
+	return time.FixedZone("WIB", 7*60*60)
+}
+
+// flakyEmailSender fails the first sends and records the ones that went out.
+type flakyEmailSender struct {
+	email.EmailSenderService
+	failures int
+	sent     []string
+}
+
+func (e *flakyEmailSender) SendHTMLEmail(to []string, subject, body string, attachments []email.Attachment) error {
+	if e.failures > 0 {
+		e.failures--
+		return errors.New("smtp timeout")
+	}
+	e.sent = append(e.sent, strings.Join(to, ",")+" "+subject+" "+attachments[0].Filename)
+	return nil
+}
+
+// TestRunDueReports tests that a due report is retried after a failure and sent once
+func TestRunDueReports(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &deliveryRepository{subscriptions: []entity.ReportSubscription{{
+		SubscriptionID: uuid.New(), ReportType: entity.ReportTypeDaily, SendHour: 7,
+		Recipients: "owner@example.com", Format: entity.ReportFormatCSV, Active: true, CreatedAt: created,
+	}}}
+	sender := &flakyEmailSender{failures: 1}
+	service := NewReportSubscriptionService(repo, &dailyReportService{}, fixedSettings{}, sender)
+
+	// Nothing is due yet for a subscription made after today's send hour
+	if err := service.RunDueReports(created.Add(time.Hour)); err != nil || len(repo.deliveries) != 0 {
+		t.Fatalf("Expected nothing to be due, got %v and %d deliveries", err, len(repo.deliveries))
+	}
+
+	sendAt := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	if err := service.RunDueReports(sendAt); err == nil {
+		t.Error("Expected the failed send to be reported")
+	}
+	if len(repo.deliveries) != 1 || repo.deliveries[0].Status != entity.ReportDeliveryPending || repo.deliveries[0].Attempts != 1 {
+		t.Fatalf("Expected one pending delivery after a failure, got %+v", repo.deliveries)
+	}
+
+	if err := service.RunDueReports(sendAt.Add(reportRetryDelay)); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if err := service.RunDueReports(sendAt.Add(2 * reportRetryDelay)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	delivery := repo.deliveries[0]
+	if len(repo.deliveries) != 1 || delivery.Status != entity.ReportDeliverySent || delivery.Attempts != 2 {
+		t.Errorf("Expected the delivery to be sent on the second attempt, got %+v", repo.deliveries)
+	}
+	if len(sender.sent) != 1 || !strings.HasPrefix(sender.sent[0], "owner@example.com Daily Sales Report 01/03/2026 | Toko Budi ") || !strings.HasSuffix(sender.sent[0], "sales-report-2026-03-01.csv") {
+		t.Errorf("Expected the report of 1 March to be sent once, got %v", sender.sent)
+	}
+}
+
+// TestReportEmailHTML tests that the email lists the totals and escapes the store's text
+func TestReportEmailHTML(t *testing.T) {
+	report := &entity.SalesReport{
+		TotalSales:             money.New(150000),
+		PaymentMethodBreakdown: []entity.PaymentMethodStat{{PaymentMethod: "cash", TotalAmount: money.New(150000), Count: 3}},
+		TopProducts:            []entity.TopProductStat{{ProductName: "Kopi & Roti", QuantitySold: 4, TotalRevenue: money.New(80000)}},
+	}
+
+	body, err := reportEmailHTML(report, "Daily Sales Report 01/03/2026", "Toko <Budi>")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"<h2 style=\"margin-bottom: 0;\">Toko &lt;Budi&gt;</h2>",
+		"Daily Sales Report 01/03/2026",
+		"<strong>150000.00</strong>",
+		"<td>CASH</td><td align=\"right\">3</td><td align=\"right\">150000.00</td>",
+		"<td>Kopi &amp; Roti</td><td align=\"right\">4</td><td align=\"right\">80000.00</td>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the email to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "Tips") {
+		t.Error("Expected zero tips to be left out")
+	}
+}
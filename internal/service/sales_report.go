@@ -2,10 +2,12 @@ package service
 
 import (
 	"errors"
+	"io"
 	"time"
 
 	"Kevinmajesta/OrderManagementAPI/internal/entity"
 	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
 
 	"github.com/google/uuid"
 )
@@ -14,23 +16,62 @@ type SalesReportService interface {
 	GetSalesReportByDateRange(startDate, endDate time.Time) (*entity.SalesReport, error)
 	GetDailySalesReport(date time.Time) (*entity.SalesReport, error)
 	GetMonthlySalesReport(year int, month time.Month) (*entity.SalesReport, error)
+	ExportSalesReport(report *entity.SalesReport, format string, w io.Writer) error
 }
 
+// Reports cover whole business days in the store's time zone. Dates passed
+// in are read as calendar days, whatever their location; zero dates mean the
+// current day or month in the store.
 type salesReportService struct {
 	salesReportRepo repository.SalesReportRepository
+	settingService  SettingService
 }
 
-func NewSalesReportService(salesReportRepo repository.SalesReportRepository) *salesReportService {
+func NewSalesReportService(salesReportRepo repository.SalesReportRepository, settingService SettingService) *salesReportService {
 	return &salesReportService{
 		salesReportRepo: salesReportRepo,
+		settingService:  settingService,
 	}
 }
 
+// GetSalesReportByDateRange reports from the start of startDate up to the end
+// of endDate.
 func (s *salesReportService) GetSalesReportByDateRange(startDate, endDate time.Time) (*entity.SalesReport, error) {
 	if startDate.After(endDate) {
 		return nil, errors.New("start_date must be before end_date")
 	}
 
+	location := s.settingService.Location()
+	return s.salesReport(businessDay(startDate, location), businessDay(endDate, location).AddDate(0, 0, 1))
+}
+
+func (s *salesReportService) GetDailySalesReport(date time.Time) (*entity.SalesReport, error) {
+	location := s.settingService.Location()
+	if date.IsZero() {
+		date = time.Now().In(location)
+	}
+	startDate := businessDay(date, location)
+
+	return s.salesReport(startDate, startDate.AddDate(0, 0, 1))
+}
+
+func (s *salesReportService) GetMonthlySalesReport(year int, month time.Month) (*entity.SalesReport, error) {
+	location := s.settingService.Location()
+	now := time.Now().In(location)
+	if year == 0 {
+		year = now.Year()
+	}
+	if month == 0 {
+		month = now.Month()
+	}
+	startDate := time.Date(year, month, 1, 0, 0, 0, 0, location)
+
+	return s.salesReport(startDate, startDate.AddDate(0, 1, 0))
+}
+
+// salesReport covers the orders from startDate up to, but not including,
+// endDate.
+func (s *salesReportService) salesReport(startDate, endDate time.Time) (*entity.SalesReport, error) {
 	reportData, err := s.salesReportRepo.GetSalesReportByDateRange(startDate, endDate)
 	if err != nil {
 		return nil, err
@@ -38,37 +79,37 @@ func (s *salesReportService) GetSalesReportByDateRange(startDate, endDate time.T
 
 	paymentBreakdown, _ := s.salesReportRepo.GetPaymentMethodBreakdown(startDate, endDate)
 	topProducts, _ := s.salesReportRepo.GetTopProducts(startDate, endDate, 10)
+	tipsByCashier, _ := s.salesReportRepo.GetTipsByCashier(startDate, endDate)
 
 	report := &entity.SalesReport{
 		ReportID:                uuid.New(),
-		ReportDate:              time.Now(),
+		ReportDate:              time.Now().In(startDate.Location()),
 		PeriodStartDate:         startDate,
 		PeriodEndDate:           endDate,
-		TotalSales:              reportData["total_sales"].(float64),
+		GrossSales:              reportData["gross_sales"].(money.Amount),
+		TotalDiscount:           reportData["total_discount"].(money.Amount),
+		TotalSales:              reportData["total_sales"].(money.Amount),
 		TotalTransactions:       reportData["total_transactions"].(int64),
-		TotalTax:                reportData["total_tax"].(float64),
-		AverageTransactionValue: reportData["average_transaction_value"].(float64),
-		CashAmount:              reportData["cash_amount"].(float64),
-		MidtransAmount:          reportData["midtrans_amount"].(float64),
+		TotalTax:                reportData["total_tax"].(money.Amount),
+		AverageTransactionValue: reportData["average_transaction_value"].(money.Amount),
+		CashAmount:              reportData["cash_amount"].(money.Amount),
+		MidtransAmount:          reportData["midtrans_amount"].(money.Amount),
+		GiftCardSales:           reportData["gift_card_sales"].(money.Amount),
+		GiftCardRedeemed:        reportData["gift_card_redeemed"].(money.Amount),
+		GiftCardLiability:       reportData["gift_card_liability"].(money.Amount),
+		ServiceChargeAmount:     reportData["service_charge_amount"].(money.Amount),
+		TipAmount:               reportData["tip_amount"].(money.Amount),
 		TotalCustomers:          reportData["total_customers"].(int64),
 		PaymentMethodBreakdown:  paymentBreakdown,
 		TopProducts:             topProducts,
+		TipsByCashier:           tipsByCashier,
 		CreatedAt:               time.Now(),
 	}
 
 	return report, nil
 }
 
-func (s *salesReportService) GetDailySalesReport(date time.Time) (*entity.SalesReport, error) {
-	startDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endDate := startDate.Add(24 * time.Hour)
-
-	return s.GetSalesReportByDateRange(startDate, endDate)
-}
-
-func (s *salesReportService) GetMonthlySalesReport(year int, month time.Month) (*entity.SalesReport, error) {
-	startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0)
-
-	return s.GetSalesReportByDateRange(startDate, endDate)
+// businessDay is midnight in location at the start of date's calendar day.
+func businessDay(date time.Time, location *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
 }
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+	"Kevinmajesta/OrderManagementAPI/pkg/xlsx"
+)
+
+// ReportContentType is the MIME type of an exported report in format.
+func ReportContentType(format string) string {
+	if format == entity.ReportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// reportTableWriter writes the tables of an exported report. Cells are
+// strings, money amounts, integers, floats and times.
+type reportTableWriter interface {
+	StartTable(name string, header ...string) error
+	WriteRow(cells ...interface{}) error
+	Close() error
+}
+
+// ExportSalesReport writes the report as CSV or XLSX: a summary, the payment
+// method breakdown and the top products, followed by every order line of the
+// period. Order lines are streamed from the database straight into w.
+func (s *salesReportService) ExportSalesReport(report *entity.SalesReport, format string, w io.Writer) error {
+	var out reportTableWriter
+	switch format {
+	case entity.ReportFormatCSV:
+		out = &csvReportWriter{w: csv.NewWriter(w)}
+	case entity.ReportFormatXLSX:
+		out = &xlsxReportWriter{w: xlsx.NewWriter(w)}
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+
+	if err := writeSalesReportTables(out, report); err != nil {
+		return err
+	}
+
+	if err := out.StartTable("Order Lines", "Order ID", "Ordered At", "Payment Method", "Cashier", "Item Type", "Item",
+		"Quantity", "Price Per Item", "Discount", "Tax", "Total"); err != nil {
+		return err
+	}
+	location := report.PeriodStartDate.Location()
+	err := s.salesReportRepo.StreamOrderLines(report.PeriodStartDate, report.PeriodEndDate, func(line entity.SalesOrderLine) error {
+		return out.WriteRow(line.OrderID.String(), line.OrderedAt.In(location), line.PaymentMethod, line.CashierName, line.ItemType, line.ItemName,
+			line.Quantity, line.PricePerItem, line.DiscountAmount, line.TaxAmount, line.TotalPrice)
+	})
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// writeSalesReportTables writes the summary, payment method and top product
+// tables of the report.
+func writeSalesReportTables(out reportTableWriter, report *entity.SalesReport) error {
+	if err := out.StartTable("Summary", "Metric", "Value"); err != nil {
+		return err
+	}
+	summary := [][2]interface{}{
+		{"Period Start", report.PeriodStartDate},
+		{"Period End", report.PeriodEndDate},
+		{"Gross Sales", report.GrossSales},
+		{"Total Discount", report.TotalDiscount},
+		{"Total Sales", report.TotalSales},
+		{"Total Tax", report.TotalTax},
+		{"Service Charge", report.ServiceChargeAmount},
+		{"Tips", report.TipAmount},
+		{"Transactions", report.TotalTransactions},
+		{"Customers", report.TotalCustomers},
+		{"Average Transaction Value", report.AverageTransactionValue},
+		{"Cash", report.CashAmount},
+		{"Midtrans", report.MidtransAmount},
+		{"Gift Card Sales", report.GiftCardSales},
+		{"Gift Card Redeemed", report.GiftCardRedeemed},
+		{"Gift Card Liability", report.GiftCardLiability},
+		{"Generated At", report.ReportDate},
+	}
+	for _, row := range summary {
+		if err := out.WriteRow(row[0], row[1]); err != nil {
+			return err
+		}
+	}
+
+	if err := out.StartTable("Payment Methods", "Payment Method", "Total Amount", "Orders", "Percentage"); err != nil {
+		return err
+	}
+	for _, stat := range report.PaymentMethodBreakdown {
+		if err := out.WriteRow(stat.PaymentMethod, stat.TotalAmount, stat.Count, math.Round(stat.Percentage*100)/100); err != nil {
+			return err
+		}
+	}
+
+	if err := out.StartTable("Top Products", "Product ID", "Product", "Quantity Sold", "Revenue"); err != nil {
+		return err
+	}
+	for _, stat := range report.TopProducts {
+		if err := out.WriteRow(stat.ProductID.String(), stat.ProductName, stat.QuantitySold, stat.TotalRevenue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// csvReportWriter puts every table in one CSV file. Each table starts with
+// its name on a row of its own and is separated from the one before by an
+// empty row.
+type csvReportWriter struct {
+	w      *csv.Writer
+	tables int
+}
+
+func (c *csvReportWriter) StartTable(name string, header ...string) error {
+	if c.tables > 0 {
+		if err := c.w.Write([]string{}); err != nil {
+			return err
+		}
+	}
+	c.tables++
+	if err := c.w.Write([]string{name}); err != nil {
+		return err
+	}
+	return c.w.Write(header)
+}
+
+func (c *csvReportWriter) WriteRow(cells ...interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case string:
+			record[i] = v
+		case money.Amount:
+			record[i] = v.String()
+		case int:
+			record[i] = strconv.Itoa(v)
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', 2, 64)
+		case time.Time:
+			record[i] = v.Format("2006-01-02 15:04:05")
+		default:
+			return fmt.Errorf("unsupported report cell %T", cell)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvReportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxReportWriter puts every table on a sheet of its own, with money as
+// numbers so it can be summed in the spreadsheet.
+type xlsxReportWriter struct {
+	w *xlsx.Writer
+}
+
+func (x *xlsxReportWriter) StartTable(name string, header ...string) error {
+	if err := x.w.AddSheet(name); err != nil {
+		return err
+	}
+	cells := make([]interface{}, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+	return x.w.WriteRow(cells...)
+}
+
+func (x *xlsxReportWriter) WriteRow(cells ...interface{}) error {
+	for i, cell := range cells {
+		if amount, ok := cell.(money.Amount); ok {
+			cells[i] = xlsx.Number(amount.String())
+		}
+	}
+	return x.w.WriteRow(cells...)
+}
+
+func (x *xlsxReportWriter) Close() error {
+	return x.w.Close()
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// orderLineRepository streams fixed order lines.
+type orderLineRepository struct {
+	repository.SalesReportRepository
+	lines []entity.SalesOrderLine
+}
+
+func (r *orderLineRepository) StreamOrderLines(startDate, endDate time.Time, fn func(entity.SalesOrderLine) error) error {
+	for _, line := range r.lines {
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestExportSalesReportCSV tests that the CSV lists every table of the report followed by the order lines
+func TestExportSalesReportCSV(t *testing.T) {
+	orderID := uuid.MustParse("6a1f4c3e-1b2d-4e5f-8a9b-0c1d2e3f4a5b")
+	productID := uuid.MustParse("0f9e8d7c-6b5a-4938-8271-605f4e3d2c1b")
+	at := time.Date(2026, 2, 3, 14, 5, 0, 0, time.UTC)
+	repo := &orderLineRepository{lines: []entity.SalesOrderLine{{
+		OrderID: orderID, OrderedAt: at, PaymentMethod: "cash", CashierName: "Sari", ItemType: entity.OrderItemProduct,
+		ItemName: "Kopi, Susu", Quantity: 2, PricePerItem: money.New(25000), DiscountAmount: money.New(5000),
+		TaxAmount: money.New(4500), TotalPrice: money.New(50000),
+	}}}
+	report := &entity.SalesReport{
+		PeriodStartDate: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC),
+		PeriodEndDate:   time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC),
+		ReportDate:      at,
+		TotalSales:      money.New(50000),
+		PaymentMethodBreakdown: []entity.PaymentMethodStat{
+			{PaymentMethod: "cash", TotalAmount: money.New(50000), Count: 1, Percentage: 100.0 / 3},
+		},
+		TopProducts: []entity.TopProductStat{
+			{ProductID: productID, ProductName: "Kopi, Susu", QuantitySold: 2, TotalRevenue: money.New(50000)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewSalesReportService(repo, fixedSettings{}).ExportSalesReport(report, entity.ReportFormatCSV, &buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, want := range []string{
+		"Summary\nMetric,Value\nPeriod Start,2026-02-03 00:00:00\n",
+		"Total Sales,50000.00\n",
+		"Transactions,0\n",
+		"\n\nPayment Methods\nPayment Method,Total Amount,Orders,Percentage\ncash,50000.00,1,33.33\n",
+		"\n\nTop Products\nProduct ID,Product,Quantity Sold,Revenue\n" + productID.String() + ",\"Kopi, Susu\",2,50000.00\n",
+		"\n\nOrder Lines\nOrder ID,Ordered At,Payment Method,Cashier,Item Type,Item,Quantity,Price Per Item,Discount,Tax,Total\n" +
+			orderID.String() + ",2026-02-03 14:05:00,cash,Sari,product,\"Kopi, Susu\",2,25000.00,5000.00,4500.00,50000.00\n",
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("Expected the CSV to contain %q, got %s", want, buf.String())
+		}
+	}
+}
+
+// TestExportSalesReportXLSX tests that the workbook is written and that an unknown format is refused
+func TestExportSalesReportXLSX(t *testing.T) {
+	service := NewSalesReportService(&orderLineRepository{}, fixedSettings{})
+	report := &entity.SalesReport{}
+
+	var buf bytes.Buffer
+	if err := service.ExportSalesReport(report, entity.ReportFormatXLSX, &buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("PK")) {
+		t.Error("Expected a zip file")
+	}
+
+	if err := service.ExportSalesReport(report, "pdf", &buf); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	failing := &failingOrderLineRepository{}
+	if err := NewSalesReportService(failing, fixedSettings{}).ExportSalesReport(report, entity.ReportFormatCSV, &bytes.Buffer{}); err == nil {
+		t.Error("Expected the stream error to be returned")
+	}
+}
+
+type failingOrderLineRepository struct {
+	repository.SalesReportRepository
+}
+
+func (r *failingOrderLineRepository) StreamOrderLines(startDate, endDate time.Time, fn func(entity.SalesOrderLine) error) error {
+	return errors.New("connection reset")
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// periodRepository records the period it is asked for.
+type periodRepository struct {
+	repository.SalesReportRepository
+	start, end time.Time
+}
+
+func (r *periodRepository) GetSalesReportByDateRange(startDate, endDate time.Time) (map[string]interface{}, error) {
+	r.start, r.end = startDate, endDate
+	data := map[string]interface{}{"total_transactions": int64(0), "total_customers": int64(0)}
+	for _, key := range []string{"gross_sales", "total_discount", "total_sales", "total_tax", "average_transaction_value", "cash_amount",
+		"midtrans_amount", "gift_card_sales", "gift_card_redeemed", "gift_card_liability", "service_charge_amount", "tip_amount"} {
+		data[key] = money.Zero
+	}
+	return data, nil
+}
+
+func (r *periodRepository) GetPaymentMethodBreakdown(startDate, endDate time.Time) ([]entity.PaymentMethodStat, error) {
+	return nil, nil
+}
+
+func (r *periodRepository) GetTopProducts(startDate, endDate time.Time, limit int) ([]entity.TopProductStat, error) {
+	return nil, nil
+}
+
+func (r *periodRepository) GetTipsByCashier(startDate, endDate time.Time) ([]entity.CashierTipStat, error) {
+	return nil, nil
+}
+
+// TestSalesReportPeriods tests that reports cover whole days in the store's time zone, whatever the location of the dates passed in
+func TestSalesReportPeriods(t *testing.T) {
+	wib := fixedSettings{}.Location()
+	repo := &periodRepository{}
+	service := NewSalesReportService(repo, fixedSettings{})
+
+	tests := []struct {
+		name  string
+		run   func() (*entity.SalesReport, error)
+		start time.Time
+		end   time.Time
+	}{
+		{
+			name: "daily",
+			run: func() (*entity.SalesReport, error) {
+				return service.GetDailySalesReport(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+			},
+			start: time.Date(2026, 3, 1, 0, 0, 0, 0, wib),
+			end:   time.Date(2026, 3, 2, 0, 0, 0, 0, wib),
+		},
+		{
+			name:  "monthly",
+			run:   func() (*entity.SalesReport, error) { return service.GetMonthlySalesReport(2026, time.February) },
+			start: time.Date(2026, 2, 1, 0, 0, 0, 0, wib),
+			end:   time.Date(2026, 3, 1, 0, 0, 0, 0, wib),
+		},
+		{
+			name: "date range up to the end of the last day",
+			run: func() (*entity.SalesReport, error) {
+				return service.GetSalesReportByDateRange(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC))
+			},
+			start: time.Date(2026, 3, 1, 0, 0, 0, 0, wib),
+			end:   time.Date(2026, 3, 8, 0, 0, 0, 0, wib),
+		},
+	}
+
+	for _, tt := range tests {
+		report, err := tt.run()
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+		if !repo.start.Equal(tt.start) || !repo.end.Equal(tt.end) {
+			t.Errorf("%s: expected %s to %s, got %s to %s", tt.name, tt.start, tt.end, repo.start, repo.end)
+		}
+		if report.PeriodStartDate.Location().String() != "WIB" {
+			t.Errorf("%s: expected the period in store time, got %s", tt.name, report.PeriodStartDate.Location())
+		}
+	}
+
+	today := time.Now().In(wib)
+	if _, err := service.GetDailySalesReport(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, wib); !repo.start.Equal(want) {
+		t.Errorf("Expected no date to report today in store time, %s, got %s", want, repo.start)
+	}
+}
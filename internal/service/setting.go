@@ -0,0 +1,333 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// settingCacheTTL bounds how long another instance's update can stay invisible.
+const settingCacheTTL = time.Minute
+
+var receiptPrefixPattern = regexp.MustCompile(`^[A-Z0-9]{1,8}$`)
+
+type SettingService interface {
+	GetSettings() (*entity.StoreSettings, error)
+	UpdateSettings(values map[string]string) (*entity.StoreSettings, error)
+	Invalidate()
+	StoreName() string
+	StoreAddress() string
+	StorePhone() string
+	TaxRate() float64
+	PricesIncludeTax() bool
+	ReceiptPrefix() string
+	ReceiptNumberFormat() string
+	LoyaltySpendPerPoint() money.Amount
+	LoyaltyPointValue() money.Amount
+	MembershipWindowDays() int
+	GiftCardValidityDays() int
+	ServiceChargeRate() float64
+	ReceiptHeader() string
+	ReceiptFooter() string
+	ReceiptPaper() string
+	ReceiptVerifyURL() string
+	Location() *time.Location
+}
+
+type settingService struct {
+	settingRepo repository.SettingRepository
+
+	mu       sync.RWMutex
+	values   map[string]string
+	loadedAt time.Time
+}
+
+func NewSettingService(settingRepo repository.SettingRepository) *settingService {
+	return &settingService{
+		settingRepo: settingRepo,
+	}
+}
+
+func (s *settingService) GetSettings() (*entity.StoreSettings, error) {
+	if _, err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return &entity.StoreSettings{
+		StoreName:            s.StoreName(),
+		StoreAddress:         s.StoreAddress(),
+		StorePhone:           s.StorePhone(),
+		TaxRate:              s.TaxRate(),
+		PricesIncludeTax:     s.PricesIncludeTax(),
+		ReceiptPrefix:        s.ReceiptPrefix(),
+		ReceiptNumberFormat:  s.ReceiptNumberFormat(),
+		LoyaltySpendPerPoint: s.LoyaltySpendPerPoint(),
+		LoyaltyPointValue:    s.LoyaltyPointValue(),
+		MembershipWindowDays: s.MembershipWindowDays(),
+		GiftCardValidityDays: s.GiftCardValidityDays(),
+		ServiceChargeRate:    s.ServiceChargeRate(),
+		ReceiptHeader:        s.ReceiptHeader(),
+		ReceiptFooter:        s.ReceiptFooter(),
+		ReceiptPaper:         s.ReceiptPaper(),
+		ReceiptVerifyURL:     s.ReceiptVerifyURL(),
+		Timezone:             s.Location().String(),
+	}, nil
+}
+
+func (s *settingService) UpdateSettings(values map[string]string) (*entity.StoreSettings, error) {
+	for key, value := range values {
+		if err := validateSetting(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.settingRepo.UpsertSettings(values); err != nil {
+		return nil, err
+	}
+	s.Invalidate()
+
+	return s.GetSettings()
+}
+
+func (s *settingService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = nil
+}
+
+func (s *settingService) StoreName() string {
+	return s.get(entity.SettingStoreName)
+}
+
+func (s *settingService) StoreAddress() string {
+	return s.get(entity.SettingStoreAddress)
+}
+
+func (s *settingService) StorePhone() string {
+	return s.get(entity.SettingStorePhone)
+}
+
+func (s *settingService) TaxRate() float64 {
+	return s.getFloat(entity.SettingTaxRate)
+}
+
+func (s *settingService) PricesIncludeTax() bool {
+	return s.getBool(entity.SettingPricesIncludeTax)
+}
+
+func (s *settingService) ReceiptPrefix() string {
+	return s.get(entity.SettingReceiptPrefix)
+}
+
+func (s *settingService) ReceiptNumberFormat() string {
+	return s.get(entity.SettingReceiptNumberFormat)
+}
+
+func (s *settingService) LoyaltySpendPerPoint() money.Amount {
+	return s.getAmount(entity.SettingLoyaltySpendPerPoint)
+}
+
+func (s *settingService) LoyaltyPointValue() money.Amount {
+	return s.getAmount(entity.SettingLoyaltyPointValue)
+}
+
+func (s *settingService) MembershipWindowDays() int {
+	value, err := strconv.Atoi(s.get(entity.SettingMembershipWindowDays))
+	if err != nil {
+		value, _ = strconv.Atoi(entity.DefaultStoreSettings[entity.SettingMembershipWindowDays])
+	}
+	return value
+}
+
+func (s *settingService) GiftCardValidityDays() int {
+	value, err := strconv.Atoi(s.get(entity.SettingGiftCardValidityDays))
+	if err != nil {
+		value, _ = strconv.Atoi(entity.DefaultStoreSettings[entity.SettingGiftCardValidityDays])
+	}
+	return value
+}
+
+func (s *settingService) ServiceChargeRate() float64 {
+	return s.getFloat(entity.SettingServiceChargeRate)
+}
+
+func (s *settingService) ReceiptHeader() string {
+	return s.get(entity.SettingReceiptHeader)
+}
+
+func (s *settingService) ReceiptFooter() string {
+	return s.get(entity.SettingReceiptFooter)
+}
+
+func (s *settingService) ReceiptPaper() string {
+	return s.get(entity.SettingReceiptPaper)
+}
+
+func (s *settingService) ReceiptVerifyURL() string {
+	return s.get(entity.SettingReceiptVerifyURL)
+}
+
+// Location is the time zone of the store. A zone that cannot be loaded falls
+// back to the default, then to UTC.
+func (s *settingService) Location() *time.Location {
+	location, err := time.LoadLocation(s.get(entity.SettingTimezone))
+	if err != nil {
+		log.Printf("failed to load timezone, using default: %v", err)
+		if location, err = time.LoadLocation(entity.DefaultStoreSettings[entity.SettingTimezone]); err != nil {
+			return time.UTC
+		}
+	}
+	return location
+}
+
+func (s *settingService) get(key string) string {
+	values, err := s.load()
+	if err != nil {
+		log.Printf("failed to load store settings, using default for %s: %v", key, err)
+		return entity.DefaultStoreSettings[key]
+	}
+	if value, ok := values[key]; ok {
+		return value
+	}
+	return entity.DefaultStoreSettings[key]
+}
+
+func (s *settingService) getFloat(key string) float64 {
+	value, err := strconv.ParseFloat(s.get(key), 64)
+	if err != nil {
+		value, _ = strconv.ParseFloat(entity.DefaultStoreSettings[key], 64)
+	}
+	return value
+}
+
+func (s *settingService) getBool(key string) bool {
+	value, err := strconv.ParseBool(s.get(key))
+	if err != nil {
+		value, _ = strconv.ParseBool(entity.DefaultStoreSettings[key])
+	}
+	return value
+}
+
+func (s *settingService) getAmount(key string) money.Amount {
+	value, err := money.Parse(s.get(key))
+	if err != nil {
+		value, _ = money.Parse(entity.DefaultStoreSettings[key])
+	}
+	return value
+}
+
+func (s *settingService) load() (map[string]string, error) {
+	s.mu.RLock()
+	if s.values != nil && time.Since(s.loadedAt) < settingCacheTTL {
+		values := s.values
+		s.mu.RUnlock()
+		return values, nil
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another goroutine may have refreshed the cache while we waited for the lock
+	if s.values != nil && time.Since(s.loadedAt) < settingCacheTTL {
+		return s.values, nil
+	}
+
+	settings, err := s.settingRepo.GetAllSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		values[setting.Key] = setting.Value
+	}
+	s.values = values
+	s.loadedAt = time.Now()
+
+	return values, nil
+}
+
+func validateSetting(key, value string) error {
+	switch key {
+	case entity.SettingStoreName:
+		if value == "" {
+			return errors.New("store_name cannot be empty")
+		}
+	case entity.SettingStoreAddress, entity.SettingStorePhone:
+	case entity.SettingTaxRate:
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return errors.New("tax_rate must be a number between 0 and 1")
+		}
+	case entity.SettingServiceChargeRate:
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return errors.New("service_charge_rate must be a number between 0 and 1")
+		}
+	case entity.SettingPricesIncludeTax:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("prices_include_tax must be true or false")
+		}
+	case entity.SettingReceiptPrefix:
+		if !receiptPrefixPattern.MatchString(value) {
+			return errors.New("receipt_prefix must be 1-8 uppercase letters or digits")
+		}
+	case entity.SettingReceiptNumberFormat:
+		if err := validateReceiptNumberFormat(value); err != nil {
+			return errors.New("receipt_number_format: " + err.Error())
+		}
+	case entity.SettingLoyaltySpendPerPoint, entity.SettingLoyaltyPointValue:
+		amount, err := money.Parse(value)
+		if err != nil || !amount.IsPositive() || !amount.IsWholeRupiah() {
+			return errors.New(key + " must be a positive whole rupiah amount")
+		}
+	case entity.SettingMembershipWindowDays:
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > 3650 {
+			return errors.New("membership_window_days must be between 1 and 3650")
+		}
+	case entity.SettingGiftCardValidityDays:
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 || days > 3650 {
+			return errors.New("gift_card_validity_days must be between 0 and 3650")
+		}
+	case entity.SettingReceiptHeader, entity.SettingReceiptFooter:
+		if len(value) > 1000 {
+			return errors.New(key + " must be at most 1000 characters")
+		}
+		if _, err := parseReceiptTemplate(value); err != nil {
+			return errors.New(key + " is not a valid template: " + err.Error())
+		}
+	case entity.SettingReceiptPaper:
+		if _, ok := receiptPapers[value]; !ok {
+			return errors.New("receipt_paper must be a4 or 80mm")
+		}
+	case entity.SettingReceiptVerifyURL:
+		if value == "" {
+			return nil
+		}
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" {
+			return errors.New("receipt_verify_url must be an http or https URL without a query")
+		}
+	case entity.SettingTimezone:
+		if value == "" || value == "Local" {
+			return errors.New("timezone must be an IANA time zone such as Asia/Jakarta")
+		}
+		if _, err := time.LoadLocation(value); err != nil {
+			return errors.New("timezone must be an IANA time zone such as Asia/Jakarta")
+		}
+	default:
+		return errors.New("unknown setting: " + key)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+)
+
+type fakeSettingRepository struct {
+	values map[string]string
+	loads  int
+}
+
+func (r *fakeSettingRepository) GetAllSettings() ([]entity.StoreSetting, error) {
+	r.loads++
+	settings := make([]entity.StoreSetting, 0, len(r.values))
+	for key, value := range r.values {
+		settings = append(settings, entity.StoreSetting{Key: key, Value: value})
+	}
+	return settings, nil
+}
+
+func (r *fakeSettingRepository) UpsertSettings(values map[string]string) error {
+	for key, value := range values {
+		r.values[key] = value
+	}
+	return nil
+}
+
+// TestSettingDefaults tests that missing keys fall back to the defaults
+func TestSettingDefaults(t *testing.T) {
+	s := NewSettingService(&fakeSettingRepository{values: map[string]string{}})
+
+	if s.TaxRate() != 0.10 {
+		t.Errorf("Expected default tax rate 0.10, got %v", s.TaxRate())
+	}
+	if s.ReceiptPrefix() != "RCP" {
+		t.Errorf("Expected default receipt prefix RCP, got %s", s.ReceiptPrefix())
+	}
+	if s.StoreName() != "Cuaniaga Store" {
+		t.Errorf("Expected default store name, got %s", s.StoreName())
+	}
+}
+
+// TestSettingCache tests that settings are cached and reloaded after an update
+func TestSettingCache(t *testing.T) {
+	repo := &fakeSettingRepository{values: map[string]string{entity.SettingTaxRate: "0.11"}}
+	s := NewSettingService(repo)
+
+	s.TaxRate()
+	s.StoreName()
+	if repo.loads != 1 {
+		t.Errorf("Expected settings to be loaded once, got %d", repo.loads)
+	}
+
+	if _, err := s.UpdateSettings(map[string]string{entity.SettingTaxRate: "0.12"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.TaxRate() != 0.12 {
+		t.Errorf("Expected updated tax rate 0.12, got %v", s.TaxRate())
+	}
+	if repo.loads != 2 {
+		t.Errorf("Expected settings to be reloaded after update, got %d loads", repo.loads)
+	}
+}
+
+// TestSettingLocation tests that the store time zone is loaded and falls back to the default
+func TestSettingLocation(t *testing.T) {
+	s := NewSettingService(&fakeSettingRepository{values: map[string]string{}})
+	if got := s.Location().String(); got != "Asia/Jakarta" {
+		t.Errorf("Expected the default Asia/Jakarta, got %s", got)
+	}
+
+	s = NewSettingService(&fakeSettingRepository{values: map[string]string{entity.SettingTimezone: "Asia/Makassar"}})
+	if got := s.Location().String(); got != "Asia/Makassar" {
+		t.Errorf("Expected Asia/Makassar, got %s", got)
+	}
+
+	s = NewSettingService(&fakeSettingRepository{values: map[string]string{entity.SettingTimezone: "Mars/Olympus"}})
+	if got := s.Location().String(); got != "Asia/Jakarta" {
+		t.Errorf("Expected a broken zone to fall back to Asia/Jakarta, got %s", got)
+	}
+}
+
+// TestSettingValidation tests invalid setting values are rejected
+func TestSettingValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{name: "negative tax rate", key: entity.SettingTaxRate, value: "-0.1"},
+		{name: "tax rate above one", key: entity.SettingTaxRate, value: "11"},
+		{name: "lowercase receipt prefix", key: entity.SettingReceiptPrefix, value: "rcp"},
+		{name: "receipt prefix too long", key: entity.SettingReceiptPrefix, value: "RECEIPTNO"},
+		{name: "empty store name", key: entity.SettingStoreName, value: ""},
+		{name: "receipt number without day", key: entity.SettingReceiptNumberFormat, value: "{prefix}{seq:4}"},
+		{name: "broken receipt header", key: entity.SettingReceiptHeader, value: "{{.StoreName"},
+		{name: "unknown receipt footer field", key: entity.SettingReceiptFooter, value: "{{.Outlet}}"},
+		{name: "unknown receipt paper", key: entity.SettingReceiptPaper, value: "letter"},
+		{name: "relative verify url", key: entity.SettingReceiptVerifyURL, value: "/receipts/verify"},
+		{name: "verify url with query", key: entity.SettingReceiptVerifyURL, value: "https://pos.example.com/verify?x=1"},
+		{name: "unknown timezone", key: entity.SettingTimezone, value: "Asia/Bandung"},
+		{name: "server timezone", key: entity.SettingTimezone, value: "Local"},
+		{name: "unknown key", key: "currency", value: "IDR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSettingService(&fakeSettingRepository{values: map[string]string{}})
+			if _, err := s.UpdateSettings(map[string]string{tt.key: tt.value}); err == nil {
+				t.Errorf("Expected error for %s=%q", tt.key, tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,631 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TabService runs dine-in: tables, tabs that get rounds of items over time,
+// split and merged bills, and the kitchen tickets of each round. A tab is
+// paid through OrderService.CreateOrder, which closes it.
+type TabService interface {
+	CreateTable(table *entity.DiningTable) error
+	UpdateTable(table *entity.DiningTable) (*entity.DiningTable, error)
+	GetFloorPlan() ([]entity.FloorPlanTable, error)
+	OpenTab(tableID, userID uuid.UUID, openedBy *uuid.UUID, guestCount int, note string) (*entity.Tab, error)
+	GetTab(tabID uuid.UUID) (*entity.Tab, error)
+	AddRound(tabID uuid.UUID, items []entity.TabItem, hold bool, note string) (*entity.KitchenTicket, error)
+	SplitTab(tabID uuid.UUID, splits []entity.TabItemSplit) (*entity.Tab, error)
+	MergeTabs(tabID, sourceTabID uuid.UUID) (*entity.Tab, error)
+	CancelTab(tabID uuid.UUID) error
+	QuoteTab(tabID uuid.UUID, order *entity.Order) (*entity.Order, error)
+	PayTab(tabID uuid.UUID, order *entity.Order) (*entity.Order, error)
+	FindKitchenTickets(status string) ([]entity.KitchenTicket, error)
+	SendKitchenTicket(ticketID uuid.UUID) (*entity.KitchenTicket, error)
+	CompleteKitchenTicket(ticketID uuid.UUID) (*entity.KitchenTicket, error)
+}
+
+type tabService struct {
+	tabRepo         repository.TabRepository
+	orderService    OrderService
+	modifierService ModifierService
+	db              *gorm.DB
+}
+
+func NewTabService(tabRepo repository.TabRepository, orderService OrderService, modifierService ModifierService, db *gorm.DB) *tabService {
+	return &tabService{
+		tabRepo:         tabRepo,
+		orderService:    orderService,
+		modifierService: modifierService,
+		db:              db,
+	}
+}
+
+func (s *tabService) CreateTable(table *entity.DiningTable) error {
+	if err := validateTable(table); err != nil {
+		return err
+	}
+	table.TableID = uuid.New()
+	table.IsActive = true
+	return s.tabRepo.CreateTable(table)
+}
+
+// UpdateTable replaces the details of a table. A table with an open tab
+// cannot be taken out of use.
+func (s *tabService) UpdateTable(table *entity.DiningTable) (*entity.DiningTable, error) {
+	if err := validateTable(table); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.tabRepo.FindTableByID(table.TableID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("table not found")
+		}
+		return nil, err
+	}
+
+	if existing.IsActive && !table.IsActive {
+		var openTabs int64
+		if err := s.db.Model(&entity.Tab{}).
+			Where("table_id = ? AND status = ?", table.TableID, entity.TabOpen).
+			Count(&openTabs).Error; err != nil {
+			return nil, err
+		}
+		if openTabs > 0 {
+			return nil, errors.New("table has an open tab")
+		}
+	}
+
+	existing.Name = table.Name
+	existing.Area = table.Area
+	existing.Seats = table.Seats
+	existing.IsActive = table.IsActive
+	if err := s.tabRepo.SaveTable(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func validateTable(table *entity.DiningTable) error {
+	table.Name = strings.TrimSpace(table.Name)
+	if table.Name == "" {
+		return errors.New("name is required")
+	}
+	if table.Seats <= 0 {
+		return errors.New("seats must be greater than 0")
+	}
+	return nil
+}
+
+// GetFloorPlan lists every table with the tabs open on it.
+func (s *tabService) GetFloorPlan() ([]entity.FloorPlanTable, error) {
+	tables, err := s.tabRepo.FindAllTables()
+	if err != nil {
+		return nil, err
+	}
+	tabs, err := s.tabRepo.FindOpenTabs()
+	if err != nil {
+		return nil, err
+	}
+	return floorPlan(tables, tabs), nil
+}
+
+func floorPlan(tables []entity.DiningTable, openTabs []entity.Tab) []entity.FloorPlanTable {
+	tabsByTable := make(map[uuid.UUID][]entity.Tab)
+	for _, tab := range openTabs {
+		tabsByTable[tab.TableID] = append(tabsByTable[tab.TableID], tab)
+	}
+
+	plan := make([]entity.FloorPlanTable, 0, len(tables))
+	for _, table := range tables {
+		status := "available"
+		if len(tabsByTable[table.TableID]) > 0 {
+			status = "occupied"
+		} else if !table.IsActive {
+			status = "inactive"
+		}
+		plan = append(plan, entity.FloorPlanTable{
+			DiningTable: table,
+			Status:      status,
+			OpenTabs:    tabsByTable[table.TableID],
+		})
+	}
+	return plan
+}
+
+// OpenTab starts a bill on a table. A table can have more than one open tab,
+// for example when guests pay separately.
+func (s *tabService) OpenTab(tableID, userID uuid.UUID, openedBy *uuid.UUID, guestCount int, note string) (*entity.Tab, error) {
+	if guestCount <= 0 {
+		return nil, errors.New("guest_count must be greater than 0")
+	}
+
+	table, err := s.tabRepo.FindTableByID(tableID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("table not found")
+		}
+		return nil, err
+	}
+	if !table.IsActive {
+		return nil, errors.New("table is not in use")
+	}
+
+	tab := &entity.Tab{
+		TabID:      uuid.New(),
+		TableID:    table.TableID,
+		UserID:     userID,
+		OpenedBy:   openedBy,
+		GuestCount: guestCount,
+		Status:     entity.TabOpen,
+		Note:       note,
+		OpenedAt:   time.Now(),
+		Items:      []entity.TabItem{},
+	}
+	if err := s.db.Create(tab).Error; err != nil {
+		return nil, err
+	}
+	return tab, nil
+}
+
+func (s *tabService) GetTab(tabID uuid.UUID) (*entity.Tab, error) {
+	tab, err := s.tabRepo.FindTabByID(tabID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tab not found")
+		}
+		return nil, err
+	}
+	return tab, nil
+}
+
+// AddRound adds items to a tab and puts them on a kitchen ticket for the
+// round. A held ticket is not sent to the kitchen until SendKitchenTicket.
+func (s *tabService) AddRound(tabID uuid.UUID, items []entity.TabItem, hold bool, note string) (*entity.KitchenTicket, error) {
+	if len(items) == 0 {
+		return nil, errors.New("items are required")
+	}
+	productIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, errors.New("quantity must be greater than 0")
+		}
+		if _, err := s.modifierService.ResolveModifiers(item.ProductID, tabItemOptionIDs(item)); err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	var ticket *entity.KitchenTicket
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		tab, err := lockTab(tx, tabID)
+		if err != nil {
+			return err
+		}
+
+		var found int64
+		if err := tx.Model(&entity.Products{}).Where("product_id IN ?", productIDs).Count(&found).Error; err != nil {
+			return err
+		}
+		if int(found) != len(uniqueIDs(productIDs)) {
+			return errors.New("product not found")
+		}
+
+		now := time.Now()
+		ticket = &entity.KitchenTicket{
+			TicketID:  uuid.New(),
+			TabID:     tab.TabID,
+			TableID:   tab.TableID,
+			Round:     tab.Rounds + 1,
+			Status:    entity.TicketSent,
+			Note:      note,
+			CreatedAt: now,
+			SentAt:    &now,
+		}
+		if hold {
+			ticket.Status = entity.TicketHeld
+			ticket.SentAt = nil
+		}
+		if err := tx.Create(ticket).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			tabItemID := uuid.New()
+			ticket.Items = append(ticket.Items, entity.TabItem{
+				TabItemID: tabItemID,
+				TabID:     tab.TabID,
+				TicketID:  ticket.TicketID,
+				Round:     ticket.Round,
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+				Note:      item.Note,
+				CreatedAt: now,
+				Modifiers: copyTabItemModifiers(item.Modifiers, tabItemID),
+			})
+		}
+		if err := tx.Create(&ticket.Items).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Tab{}).Where("tab_id = ?", tab.TabID).Updates(map[string]interface{}{
+			"rounds":  ticket.Round,
+			"version": tab.Version + 1,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// SplitTab moves items, or part of their quantity, to a new tab on the same
+// table so they can be paid separately.
+func (s *tabService) SplitTab(tabID uuid.UUID, splits []entity.TabItemSplit) (*entity.Tab, error) {
+	if len(splits) == 0 {
+		return nil, errors.New("items are required")
+	}
+
+	var newTabID uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		tab, err := lockTab(tx, tabID)
+		if err != nil {
+			return err
+		}
+
+		newTab := &entity.Tab{
+			TabID:      uuid.New(),
+			TableID:    tab.TableID,
+			UserID:     tab.UserID,
+			OpenedBy:   tab.OpenedBy,
+			GuestCount: 1,
+			Status:     entity.TabOpen,
+			Rounds:     tab.Rounds,
+			Note:       tab.Note,
+			OpenedAt:   time.Now(),
+		}
+		newTabID = newTab.TabID
+
+		moved, left, err := splitTabItems(tab.Items, splits, newTab.TabID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(newTab).Error; err != nil {
+			return err
+		}
+
+		for _, item := range moved {
+			if item.TabID == newTab.TabID {
+				if err := tx.Create(&item).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(&entity.TabItem{}).Where("tab_item_id = ?", item.TabItemID).
+				Update("tab_id", newTab.TabID).Error; err != nil {
+				return err
+			}
+		}
+		for tabItemID, quantity := range left {
+			if err := tx.Model(&entity.TabItem{}).Where("tab_item_id = ?", tabItemID).
+				Update("quantity", quantity).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.Tab{}).Where("tab_id = ?", tab.TabID).Update("version", tab.Version+1).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTab(newTabID)
+}
+
+// splitTabItems works out the items of the new tab. An item moved in full
+// keeps its ID and gets the new tab ID when it is saved. A partly moved item
+// is cut in two: the moved part is a new item with the new tab ID and left
+// holds the quantity that stays on the tab. Something must stay on the tab.
+func splitTabItems(items []entity.TabItem, splits []entity.TabItemSplit, newTabID uuid.UUID) ([]entity.TabItem, map[uuid.UUID]int, error) {
+	byID := make(map[uuid.UUID]entity.TabItem, len(items))
+	for _, item := range items {
+		byID[item.TabItemID] = item
+	}
+
+	var moved []entity.TabItem
+	left := make(map[uuid.UUID]int)
+	seen := make(map[uuid.UUID]bool)
+	for _, split := range splits {
+		item, ok := byID[split.TabItemID]
+		if !ok {
+			return nil, nil, errors.New("tab item not found on this tab")
+		}
+		if seen[split.TabItemID] {
+			return nil, nil, errors.New("tab item is listed more than once")
+		}
+		seen[split.TabItemID] = true
+
+		if split.Quantity <= 0 || split.Quantity > item.Quantity {
+			return nil, nil, errors.New("quantity must be between 1 and the quantity on the tab")
+		}
+		if split.Quantity == item.Quantity {
+			moved = append(moved, item)
+			continue
+		}
+
+		part := item
+		part.TabItemID = uuid.New()
+		part.TabID = newTabID
+		part.Quantity = split.Quantity
+		part.Modifiers = copyTabItemModifiers(item.Modifiers, part.TabItemID)
+		moved = append(moved, part)
+		left[item.TabItemID] = item.Quantity - split.Quantity
+	}
+
+	if len(left) == 0 && len(moved) == len(items) {
+		return nil, nil, errors.New("a split must leave something on the tab")
+	}
+	return moved, left, nil
+}
+
+// MergeTabs moves every item of the source tab onto the tab, for example
+// when two tables are put together.
+func (s *tabService) MergeTabs(tabID, sourceTabID uuid.UUID) (*entity.Tab, error) {
+	if tabID == sourceTabID {
+		return nil, errors.New("a tab cannot be merged into itself")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock in a fixed order so two merges of the same tabs cannot deadlock
+		ids := []uuid.UUID{tabID, sourceTabID}
+		sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+		locked := make(map[uuid.UUID]*entity.Tab, 2)
+		for _, id := range ids {
+			tab, err := lockTab(tx, id)
+			if err != nil {
+				return err
+			}
+			locked[id] = tab
+		}
+		tab, source := locked[tabID], locked[sourceTabID]
+
+		if err := tx.Model(&entity.TabItem{}).Where("tab_id = ?", source.TabID).
+			Update("tab_id", tab.TabID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Tab{}).Where("tab_id = ?", source.TabID).Updates(map[string]interface{}{
+			"status":      entity.TabMerged,
+			"merged_into": tab.TabID,
+			"closed_at":   time.Now(),
+			"version":     source.Version + 1,
+		}).Error; err != nil {
+			return err
+		}
+
+		rounds := tab.Rounds
+		if source.Rounds > rounds {
+			rounds = source.Rounds
+		}
+		return tx.Model(&entity.Tab{}).Where("tab_id = ?", tab.TabID).Updates(map[string]interface{}{
+			"guest_count": tab.GuestCount + source.GuestCount,
+			"rounds":      rounds,
+			"version":     tab.Version + 1,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTab(tabID)
+}
+
+// CancelTab closes a tab that was opened by mistake. Only an empty tab can be
+// cancelled.
+func (s *tabService) CancelTab(tabID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		tab, err := lockTab(tx, tabID)
+		if err != nil {
+			return err
+		}
+		if len(tab.Items) > 0 {
+			return errors.New("tab has items, pay, split or merge it instead")
+		}
+		return tx.Model(&entity.Tab{}).Where("tab_id = ?", tab.TabID).Updates(map[string]interface{}{
+			"status":    entity.TabCancelled,
+			"closed_at": time.Now(),
+			"version":   tab.Version + 1,
+		}).Error
+	})
+}
+
+// QuoteTab prices the bill of a tab without closing it.
+func (s *tabService) QuoteTab(tabID uuid.UUID, order *entity.Order) (*entity.Order, error) {
+	if err := s.tabOrder(tabID, order); err != nil {
+		return nil, err
+	}
+	if err := s.orderService.QuoteOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// PayTab places the order for a tab. The order carries the payment details;
+// its items are taken from the tab, which is closed with the order.
+func (s *tabService) PayTab(tabID uuid.UUID, order *entity.Order) (*entity.Order, error) {
+	if err := s.tabOrder(tabID, order); err != nil {
+		return nil, err
+	}
+	if err := s.orderService.CreateOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *tabService) tabOrder(tabID uuid.UUID, order *entity.Order) error {
+	tab, err := s.GetTab(tabID)
+	if err != nil {
+		return err
+	}
+	if tab.Status != entity.TabOpen {
+		return errors.New("tab is not open")
+	}
+	if len(tab.Items) == 0 {
+		return errors.New("tab is empty")
+	}
+
+	order.UserID = tab.UserID
+	order.TabID = &tab.TabID
+	order.TabVersion = tab.Version
+	order.OrderItems = tabOrderItems(tab.Items)
+	return nil
+}
+
+// tabOrderItems puts the items of every round together, one order line per
+// product and set of modifiers.
+func tabOrderItems(items []entity.TabItem) []entity.OrderItem {
+	var orderItems []entity.OrderItem
+	lines := make(map[string]int)
+	for _, item := range items {
+		optionIDs := tabItemOptionIDs(item)
+		key := modifierKey(item.ProductID, optionIDs)
+		if i, ok := lines[key]; ok {
+			orderItems[i].Quantity += item.Quantity
+			continue
+		}
+
+		productID := item.ProductID
+		orderItem := entity.OrderItem{
+			ProductID: &productID,
+			Quantity:  item.Quantity,
+		}
+		for _, optionID := range optionIDs {
+			orderItem.Modifiers = append(orderItem.Modifiers, entity.OrderItemModifier{ModifierOptionID: &optionID})
+		}
+		lines[key] = len(orderItems)
+		orderItems = append(orderItems, orderItem)
+	}
+	return orderItems
+}
+
+func tabItemOptionIDs(item entity.TabItem) []uuid.UUID {
+	optionIDs := make([]uuid.UUID, 0, len(item.Modifiers))
+	for _, modifier := range item.Modifiers {
+		optionIDs = append(optionIDs, modifier.ModifierOptionID)
+	}
+	return optionIDs
+}
+
+func copyTabItemModifiers(modifiers []entity.TabItemModifier, tabItemID uuid.UUID) []entity.TabItemModifier {
+	var copied []entity.TabItemModifier
+	for _, modifier := range modifiers {
+		copied = append(copied, entity.TabItemModifier{TabItemID: tabItemID, ModifierOptionID: modifier.ModifierOptionID})
+	}
+	return copied
+}
+
+// closeTab closes the tab an order was made from. The order was priced from
+// the tab at order.TabVersion, so if a round was added or the bill split in
+// the meantime the order is not placed.
+func closeTab(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	if order.TabID == nil {
+		return nil
+	}
+
+	tab, err := lockTab(tx, *order.TabID)
+	if err != nil {
+		return err
+	}
+	if tab.Version != order.TabVersion {
+		return errors.New("tab changed while it was being paid, please try again")
+	}
+
+	return tx.Model(&entity.Tab{}).Where("tab_id = ?", tab.TabID).Updates(map[string]interface{}{
+		"status":    entity.TabClosed,
+		"order_id":  order.OrderID,
+		"closed_at": now,
+		"version":   tab.Version + 1,
+	}).Error
+}
+
+// lockTab locks an open tab and loads its items.
+func lockTab(tx *gorm.DB, tabID uuid.UUID) (*entity.Tab, error) {
+	var tab entity.Tab
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tab_id = ?", tabID).
+		First(&tab).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tab not found")
+		}
+		return nil, err
+	}
+	if tab.Status != entity.TabOpen {
+		return nil, errors.New("tab is not open")
+	}
+	if err := tx.Preload("Modifiers").Where("tab_id = ?", tab.TabID).Order("round, created_at").Find(&tab.Items).Error; err != nil {
+		return nil, err
+	}
+	return &tab, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	var unique []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func (s *tabService) FindKitchenTickets(status string) ([]entity.KitchenTicket, error) {
+	if status != "" && status != entity.TicketHeld && status != entity.TicketSent && status != entity.TicketDone {
+		return nil, errors.New("status must be 'held', 'sent' or 'done'")
+	}
+	return s.tabRepo.FindKitchenTickets(status)
+}
+
+// SendKitchenTicket sends a held ticket to the kitchen.
+func (s *tabService) SendKitchenTicket(ticketID uuid.UUID) (*entity.KitchenTicket, error) {
+	return s.moveTicket(ticketID, entity.TicketHeld, entity.TicketSent, "sent_at")
+}
+
+// CompleteKitchenTicket marks a ticket the kitchen has finished.
+func (s *tabService) CompleteKitchenTicket(ticketID uuid.UUID) (*entity.KitchenTicket, error) {
+	return s.moveTicket(ticketID, entity.TicketSent, entity.TicketDone, "done_at")
+}
+
+// moveTicket changes the status of a ticket only if it still has the from
+// status, so a ticket is sent or completed once.
+func (s *tabService) moveTicket(ticketID uuid.UUID, from, to, timeColumn string) (*entity.KitchenTicket, error) {
+	result := s.db.Model(&entity.KitchenTicket{}).
+		Where("ticket_id = ? AND status = ?", ticketID, from).
+		Updates(map[string]interface{}{"status": to, timeColumn: time.Now()})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	ticket, err := s.tabRepo.FindKitchenTicketByID(ticketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("kitchen ticket not found")
+		}
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("kitchen ticket is %s, not %s", ticket.Status, from)
+	}
+	return ticket, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+// TestSplitTabItems tests moving whole and partial items to a new tab
+func TestSplitTabItems(t *testing.T) {
+	tabID, newTabID := uuid.New(), uuid.New()
+	coffee := entity.TabItem{TabItemID: uuid.New(), TabID: tabID, ProductID: uuid.New(), Quantity: 3}
+	cake := entity.TabItem{TabItemID: uuid.New(), TabID: tabID, ProductID: uuid.New(), Quantity: 1}
+	items := []entity.TabItem{coffee, cake}
+
+	moved, left, err := splitTabItems(items, []entity.TabItemSplit{
+		{TabItemID: coffee.TabItemID, Quantity: 1},
+		{TabItemID: cake.TabItemID, Quantity: 1},
+	}, newTabID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(moved) != 2 {
+		t.Fatalf("Expected 2 moved items, got %d", len(moved))
+	}
+	if moved[0].TabItemID == coffee.TabItemID || moved[0].TabID != newTabID || moved[0].Quantity != 1 {
+		t.Errorf("Expected a new coffee item of 1 on the new tab, got %+v", moved[0])
+	}
+	if left[coffee.TabItemID] != 2 {
+		t.Errorf("Expected 2 coffees left on the tab, got %d", left[coffee.TabItemID])
+	}
+	if moved[1].TabItemID != cake.TabItemID {
+		t.Errorf("Expected the cake to move whole, got %+v", moved[1])
+	}
+
+	tests := []struct {
+		name   string
+		splits []entity.TabItemSplit
+	}{
+		{"everything", []entity.TabItemSplit{{TabItemID: coffee.TabItemID, Quantity: 3}, {TabItemID: cake.TabItemID, Quantity: 1}}},
+		{"too many", []entity.TabItemSplit{{TabItemID: coffee.TabItemID, Quantity: 4}}},
+		{"zero", []entity.TabItemSplit{{TabItemID: coffee.TabItemID, Quantity: 0}}},
+		{"unknown item", []entity.TabItemSplit{{TabItemID: uuid.New(), Quantity: 1}}},
+		{"duplicate", []entity.TabItemSplit{{TabItemID: coffee.TabItemID, Quantity: 1}, {TabItemID: coffee.TabItemID, Quantity: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := splitTabItems(items, tt.splits, newTabID); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+// TestTabOrderItems tests that rounds of the same product become one order line
+func TestTabOrderItems(t *testing.T) {
+	coffee, cake := uuid.New(), uuid.New()
+	orderItems := tabOrderItems([]entity.TabItem{
+		{Round: 1, ProductID: coffee, Quantity: 2},
+		{Round: 1, ProductID: cake, Quantity: 1},
+		{Round: 2, ProductID: coffee, Quantity: 1},
+	})
+
+	if len(orderItems) != 2 {
+		t.Fatalf("Expected 2 order lines, got %d", len(orderItems))
+	}
+	if *orderItems[0].ProductID != coffee || orderItems[0].Quantity != 3 {
+		t.Errorf("Expected 3 coffees, got %+v", orderItems[0])
+	}
+	if *orderItems[1].ProductID != cake || orderItems[1].Quantity != 1 {
+		t.Errorf("Expected 1 cake, got %+v", orderItems[1])
+	}
+
+	large := uuid.New()
+	orderItems = tabOrderItems([]entity.TabItem{
+		{Round: 1, ProductID: coffee, Quantity: 1},
+		{Round: 2, ProductID: coffee, Quantity: 1, Modifiers: []entity.TabItemModifier{{ModifierOptionID: large}}},
+	})
+	if len(orderItems) != 2 || len(orderItems[1].Modifiers) != 1 || *orderItems[1].Modifiers[0].ModifierOptionID != large {
+		t.Errorf("Expected a separate line for the large coffee, got %+v", orderItems)
+	}
+}
+
+// TestFloorPlan tests table status from open tabs
+func TestFloorPlan(t *testing.T) {
+	busy := entity.DiningTable{TableID: uuid.New(), Name: "T1", IsActive: true}
+	free := entity.DiningTable{TableID: uuid.New(), Name: "T2", IsActive: true}
+	closed := entity.DiningTable{TableID: uuid.New(), Name: "T3"}
+
+	plan := floorPlan([]entity.DiningTable{busy, free, closed}, []entity.Tab{{TabID: uuid.New(), TableID: busy.TableID}})
+
+	expected := []string{"occupied", "available", "inactive"}
+	for i, table := range plan {
+		if table.Status != expected[i] {
+			t.Errorf("Expected %s to be %s, got %s", table.Name, expected[i], table.Status)
+		}
+	}
+	if len(plan[0].OpenTabs) != 1 {
+		t.Errorf("Expected 1 open tab on T1, got %d", len(plan[0].OpenTabs))
+	}
+}
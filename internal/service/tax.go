@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"Kevinmajesta/OrderManagementAPI/internal/entity"
+	"Kevinmajesta/OrderManagementAPI/internal/repository"
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaxService interface {
+	CreateTaxClass(name, description string) (*entity.TaxClass, error)
+	FindAllTaxClasses() ([]entity.TaxClass, error)
+	AddTaxRate(taxClassID uuid.UUID, rate float64, effectiveFrom time.Time) (*entity.TaxRate, error)
+	GetTaxRates(taxClassID uuid.UUID) ([]entity.TaxRate, error)
+	ResolveRate(taxClassID *uuid.UUID, at time.Time) (float64, error)
+	CalculateLineTax(lineTotal money.Amount, rate float64) (net, tax, gross money.Amount)
+}
+
+type taxService struct {
+	taxRepo        repository.TaxRepository
+	settingService SettingService
+}
+
+func NewTaxService(taxRepo repository.TaxRepository, settingService SettingService) *taxService {
+	return &taxService{
+		taxRepo:        taxRepo,
+		settingService: settingService,
+	}
+}
+
+func (s *taxService) CreateTaxClass(name, description string) (*entity.TaxClass, error) {
+	if name == "" {
+		return nil, errors.New("tax class name cannot be empty")
+	}
+
+	taxClass := &entity.TaxClass{
+		TaxClassID:  uuid.New(),
+		Name:        name,
+		Description: description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.taxRepo.CreateTaxClass(taxClass); err != nil {
+		return nil, err
+	}
+	return taxClass, nil
+}
+
+func (s *taxService) FindAllTaxClasses() ([]entity.TaxClass, error) {
+	return s.taxRepo.FindAllTaxClasses()
+}
+
+func (s *taxService) AddTaxRate(taxClassID uuid.UUID, rate float64, effectiveFrom time.Time) (*entity.TaxRate, error) {
+	if rate < 0 || rate > 1 {
+		return nil, errors.New("rate must be between 0 and 1")
+	}
+	if _, err := s.taxRepo.FindTaxClassByID(taxClassID); err != nil {
+		return nil, errors.New("tax class not found")
+	}
+	if effectiveFrom.IsZero() {
+		effectiveFrom = time.Now()
+	}
+
+	taxRate := &entity.TaxRate{
+		TaxRateID:     uuid.New(),
+		TaxClassID:    taxClassID,
+		Rate:          rate,
+		EffectiveFrom: effectiveFrom,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.taxRepo.CreateTaxRate(taxRate); err != nil {
+		return nil, err
+	}
+	return taxRate, nil
+}
+
+func (s *taxService) GetTaxRates(taxClassID uuid.UUID) ([]entity.TaxRate, error) {
+	return s.taxRepo.FindTaxRatesByClassID(taxClassID)
+}
+
+// ResolveRate returns the rate of the tax class in effect at the given time.
+// Products without a tax class use the store's default tax rate.
+func (s *taxService) ResolveRate(taxClassID *uuid.UUID, at time.Time) (float64, error) {
+	if taxClassID == nil {
+		return s.settingService.TaxRate(), nil
+	}
+
+	taxRate, err := s.taxRepo.FindEffectiveTaxRate(*taxClassID, at)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("no tax rate in effect for product tax class")
+		}
+		return 0, err
+	}
+	return taxRate.Rate, nil
+}
+
+// CalculateLineTax splits a line total into its net, tax and gross parts
+// according to the store's price-includes-tax setting.
+func (s *taxService) CalculateLineTax(lineTotal money.Amount, rate float64) (net, tax, gross money.Amount) {
+	return calculateLineTax(lineTotal, rate, s.settingService.PricesIncludeTax())
+}
+
+// calculateLineTax rounds the tax of each line to whole rupiah, so order
+// totals built from these lines can be charged through Midtrans exactly.
+func calculateLineTax(lineTotal money.Amount, rate float64, pricesIncludeTax bool) (net, tax, gross money.Amount) {
+	if pricesIncludeTax {
+		gross = lineTotal
+		tax = gross.Sub(gross.DivRate(rate)).RoundRupiah()
+		return gross.Sub(tax), tax, gross
+	}
+
+	net = lineTotal
+	tax = net.MulRate(rate).RoundRupiah()
+	return net, tax, net.Add(tax)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/money"
+)
+
+// TestCalculateLineTax tests tax split for exclusive and inclusive prices
+func TestCalculateLineTax(t *testing.T) {
+	tests := []struct {
+		name             string
+		lineTotal        money.Amount
+		rate             float64
+		pricesIncludeTax bool
+		wantNet          money.Amount
+		wantTax          money.Amount
+		wantGross        money.Amount
+	}{
+		{name: "exclusive 11%", lineTotal: money.New(100000), rate: 0.11, wantNet: money.New(100000), wantTax: money.New(11000), wantGross: money.New(111000)},
+		{name: "exclusive 12%", lineTotal: money.New(150000), rate: 0.12, wantNet: money.New(150000), wantTax: money.New(18000), wantGross: money.New(168000)},
+		{name: "inclusive 11%", lineTotal: money.New(111000), rate: 0.11, pricesIncludeTax: true, wantNet: money.New(100000), wantTax: money.New(11000), wantGross: money.New(111000)},
+		{name: "inclusive rounds tax to rupiah", lineTotal: money.New(10000), rate: 0.11, pricesIncludeTax: true, wantNet: money.New(9009), wantTax: money.New(991), wantGross: money.New(10000)},
+		{name: "exclusive rounds tax to rupiah", lineTotal: money.New(4545), rate: 0.11, wantNet: money.New(4545), wantTax: money.New(500), wantGross: money.New(5045)},
+		{name: "exempt", lineTotal: money.New(50000), rate: 0, wantNet: money.New(50000), wantTax: money.New(0), wantGross: money.New(50000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			net, tax, gross := calculateLineTax(tt.lineTotal, tt.rate, tt.pricesIncludeTax)
+			if net != tt.wantNet || tax != tt.wantTax || gross != tt.wantGross {
+				t.Errorf("Expected net=%s tax=%s gross=%s, got net=%s tax=%s gross=%s",
+					tt.wantNet, tt.wantTax, tt.wantGross, net, tax, gross)
+			}
+		})
+	}
+}
@@ -65,22 +65,13 @@ func (s *userService) LoginUser(email string, password string) (string, error) {
 
 	expiredTime := time.Now().Local().Add(24 * time.Hour)
 
-	location, err := time.LoadLocation("Asia/Jakarta")
-	if err != nil {
-		// Sebaiknya hindari panic di logic bisnis.
-		// return "", errors.New("internal server error: failed to load timezone")
-		panic(err) // Sesuai dengan kode asli Anda, biarkan ini panic
-	}
-
-	expiredTimeInJakarta := expiredTime.In(location)
-
 	claims := token.JwtCustomClaims{
 		ID:    user.UserId.String(),
 		Email: user.Email,
 		Role:  "user",
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "Depublic",
-			ExpiresAt: jwt.NewNumericDate(expiredTimeInJakarta),
+			ExpiresAt: jwt.NewNumericDate(expiredTime),
 		},
 	}
 
@@ -3,6 +3,7 @@ package email
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 
 	"Kevinmajesta/OrderManagementAPI/configs" // Pastikan ini path modul Anda
@@ -18,6 +19,7 @@ import (
 // dan struct EmailSenderImpl.
 type EmailSenderService interface {
 	SendEmail(to []string, subject, body string) error
+	SendHTMLEmail(to []string, subject, body string, attachments []Attachment) error
 	SendWelcomeEmail(to, name, message string) error
 	SendResetPasswordEmail(to, name, resetCode string) error
 	SendVerificationEmail(to, name, code string) error
@@ -62,6 +64,43 @@ func (e *EmailSender) SendEmail(to []string, subject, body string) error {
 	return nil
 }
 
+// Attachment is a file sent along with an email.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// SendHTMLEmail sends an HTML body with the attachments added as files.
+func (e *EmailSender) SendHTMLEmail(to []string, subject, body string, attachments []Attachment) error {
+	from := e.Config.SMTP.User
+	smtpPort, err := strconv.Atoi(e.Config.SMTP.Port)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP port in config: %s", e.Config.SMTP.Port)
+	}
+
+	mailer := gomail.NewMessage()
+	mailer.SetHeader("From", from)
+	mailer.SetHeader("To", to...)
+	mailer.SetHeader("Subject", subject)
+	mailer.SetBody("text/html", body)
+	for _, attachment := range attachments {
+		data := attachment.Data
+		mailer.Attach(attachment.Filename,
+			gomail.SetHeader(map[string][]string{"Content-Type": {attachment.ContentType}}),
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}))
+	}
+
+	dialer := gomail.NewDialer(e.Config.SMTP.Host, smtpPort, from, e.Config.SMTP.Password)
+	if err := dialer.DialAndSend(mailer); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return nil
+}
+
 // Metode lainnya tetap sama, karena mereka sudah memiliki receiver *EmailSender
 // yang secara implisit akan mengimplementasikan metode-metode pada EmailSenderService.
 func (e *EmailSender) SendWelcomeEmail(to, name, message string) error {
@@ -1,6 +1,10 @@
 package midtrans
 
 import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+
 	"Kevinmajesta/OrderManagementAPI/configs"
 
 	"github.com/midtrans/midtrans-go"
@@ -45,3 +49,15 @@ func (s *MidtransService) CreateTransaction(orderID string, grossAmount int64, c
 
 	return snapResp, nil
 }
+
+// VerifySignature checks the signature_key of a payment notification, the
+// SHA-512 of order_id, status_code, gross_amount and the server key. Only
+// Midtrans knows the server key, so a forged notification does not match.
+func VerifySignature(serverKey, orderID, statusCode, grossAmount, signature string) bool {
+	if serverKey == "" || signature == "" {
+		return false
+	}
+	sum := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	want := hex.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(want), []byte(signature)) == 1
+}
@@ -0,0 +1,35 @@
+package midtrans
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifySignature(t *testing.T) {
+	sum := sha512.Sum512([]byte("ORDER-1" + "200" + "55000.00" + "server-key"))
+	signature := hex.EncodeToString(sum[:])
+
+	if !VerifySignature("server-key", "ORDER-1", "200", "55000.00", signature) {
+		t.Fatal("Expected the signature to check out")
+	}
+
+	tests := []struct {
+		name        string
+		serverKey   string
+		grossAmount string
+		signature   string
+	}{
+		{name: "other server key", serverKey: "other-key", grossAmount: "55000.00", signature: signature},
+		{name: "other amount", serverKey: "server-key", grossAmount: "1.00", signature: signature},
+		{name: "no server key", serverKey: "", grossAmount: "55000.00", signature: signature},
+		{name: "no signature", serverKey: "server-key", grossAmount: "55000.00", signature: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifySignature(tt.serverKey, "ORDER-1", "200", tt.grossAmount, tt.signature) {
+				t.Error("Expected the signature to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Package money represents Rupiah amounts as integer sen so that totals
+// reconcile exactly with NUMERIC(10,2) columns and the payment gateway.
+//
+// Rounding rules:
+//   - Amounts keep two decimals (sen), matching the database columns.
+//   - Parsing and rate multiplication round half away from zero to the sen.
+//   - Derived amounts such as tax are rounded to whole rupiah with RoundRupiah,
+//     because IDR is settled without sen. Midtrans only accepts whole rupiah.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const senPerRupiah = 100
+
+// rateScale is the precision of tax and discount rates (NUMERIC(6,4)).
+const rateScale = 10000
+
+// Amount is a Rupiah amount stored in sen. The zero value is Rp0.
+type Amount struct {
+	sen int64
+}
+
+// Zero is Rp0.
+var Zero = Amount{}
+
+// New returns an amount of whole rupiah.
+func New(rupiah int64) Amount {
+	return Amount{sen: rupiah * senPerRupiah}
+}
+
+// FromSen returns an amount of the given number of sen.
+func FromSen(sen int64) Amount {
+	return Amount{sen: sen}
+}
+
+// FromFloat converts a float rupiah value, rounding to the nearest sen.
+func FromFloat(rupiah float64) Amount {
+	return Amount{sen: int64(math.Round(rupiah * senPerRupiah))}
+}
+
+// Parse reads a decimal rupiah value such as "150000", "1250.5" or "1e5".
+func Parse(value string) (Amount, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return Zero, fmt.Errorf("invalid amount %q", value)
+	}
+	r.Mul(r, big.NewRat(senPerRupiah, 1))
+
+	sen, err := roundRat(r)
+	if err != nil {
+		return Zero, fmt.Errorf("invalid amount %q: %v", value, err)
+	}
+	return Amount{sen: sen}, nil
+}
+
+func (a Amount) Sen() int64 {
+	return a.sen
+}
+
+// Rupiah returns the amount in whole rupiah, rounding half away from zero.
+func (a Amount) Rupiah() int64 {
+	return roundDiv(a.sen, senPerRupiah)
+}
+
+func (a Amount) Float64() float64 {
+	return float64(a.sen) / senPerRupiah
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return Amount{sen: a.sen + b.sen}
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{sen: a.sen - b.sen}
+}
+
+func (a Amount) Mul(n int64) Amount {
+	return Amount{sen: a.sen * n}
+}
+
+// Div splits the amount into n parts, rounding to the nearest sen.
+func (a Amount) Div(n int64) Amount {
+	if n == 0 {
+		return Zero
+	}
+	return Amount{sen: roundDiv(a.sen, n)}
+}
+
+// MulRate multiplies by a rate such as 0.11, rounding to the nearest sen.
+// Rates are limited to four decimals like the tax_rates.rate column.
+func (a Amount) MulRate(rate float64) Amount {
+	return Amount{sen: roundDiv(a.sen*rateUnits(rate), rateScale)}
+}
+
+// DivRate removes a rate that is already contained in the amount, so that
+// a.DivRate(r).MulRate(1+r) is a (within one sen).
+func (a Amount) DivRate(rate float64) Amount {
+	return Amount{sen: roundDiv(a.sen*rateScale, rateScale+rateUnits(rate))}
+}
+
+// RoundRupiah rounds to whole rupiah, half away from zero.
+func (a Amount) RoundRupiah() Amount {
+	return New(a.Rupiah())
+}
+
+func (a Amount) IsWholeRupiah() bool {
+	return a.sen%senPerRupiah == 0
+}
+
+func (a Amount) IsZero() bool {
+	return a.sen == 0
+}
+
+func (a Amount) IsPositive() bool {
+	return a.sen > 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a.sen < 0
+}
+
+// Cmp returns -1, 0 or +1 depending on whether a is less than, equal to or greater than b.
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a.sen < b.sen:
+		return -1
+	case a.sen > b.sen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (a Amount) LessThan(b Amount) bool {
+	return a.sen < b.sen
+}
+
+func (a Amount) GreaterThan(b Amount) bool {
+	return a.sen > b.sen
+}
+
+func Min(a, b Amount) Amount {
+	if a.sen < b.sen {
+		return a
+	}
+	return b
+}
+
+func Max(a, b Amount) Amount {
+	if a.sen > b.sen {
+		return a
+	}
+	return b
+}
+
+// String formats the amount with two decimals, e.g. "150000.00".
+func (a Amount) String() string {
+	sign := ""
+	sen := a.sen
+	if sen < 0 {
+		sign = "-"
+		sen = -sen
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, sen/senPerRupiah, sen%senPerRupiah)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and numeric strings.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		*a = Zero
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// UnmarshalParam lets Echo bind amounts from form and query values.
+func (a *Amount) UnmarshalParam(param string) error {
+	if param == "" {
+		*a = Zero
+		return nil
+	}
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Zero
+		return nil
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	case int64:
+		*a = New(v)
+		return nil
+	case float64:
+		*a = FromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+}
+
+func (a *Amount) scanString(value string) error {
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func rateUnits(rate float64) int64 {
+	return int64(math.Round(rate * rateScale))
+}
+
+// roundDiv divides n by d, rounding half away from zero.
+func roundDiv(n, d int64) int64 {
+	if d < 0 {
+		n, d = -n, -d
+	}
+	if n < 0 {
+		return -((-n + d/2) / d)
+	}
+	return (n + d/2) / d
+}
+
+func roundRat(r *big.Rat) (int64, error) {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	// floor(|num|/den + 1/2) rounds half away from zero for the absolute value
+	num.Mul(num, big.NewInt(2)).Add(num, den)
+	num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+
+	if !num.IsInt64() {
+		return 0, errors.New("amount out of range")
+	}
+	if r.Sign() < 0 {
+		return -num.Int64(), nil
+	}
+	return num.Int64(), nil
+}
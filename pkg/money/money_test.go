@@ -0,0 +1,80 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{input: "150000", want: 15000000},
+		{input: "1250.5", want: 125050},
+		{input: "0.005", want: 1},
+		{input: "-0.005", want: -1},
+		{input: "1e5", want: 10000000},
+		{input: "99.994", want: 9999},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+		}
+		if got.Sen() != tt.want {
+			t.Errorf("Parse(%q) = %d sen, want %d", tt.input, got.Sen(), tt.want)
+		}
+	}
+
+	if _, err := Parse("abc"); err == nil {
+		t.Error("Expected error for non-numeric input")
+	}
+}
+
+func TestRounding(t *testing.T) {
+	if got := FromSen(4999950).RoundRupiah(); got != New(50000) {
+		t.Errorf("Expected half sen to round up to Rp50000, got %s", got)
+	}
+	if got := FromSen(-150).Rupiah(); got != -2 {
+		t.Errorf("Expected -1.50 to round away from zero to -2, got %d", got)
+	}
+	if got := New(4545).MulRate(0.11); got != FromSen(49995) {
+		t.Errorf("Expected 4545 * 11%% = 499.95, got %s", got)
+	}
+	if got := New(111000).DivRate(0.11); got != New(100000) {
+		t.Errorf("Expected 111000 / 1.11 = 100000, got %s", got)
+	}
+	if got := New(100).Div(3); got != FromSen(3333) {
+		t.Errorf("Expected 100 / 3 = 33.33, got %s", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var payload struct {
+		Number Amount `json:"number"`
+		String Amount `json:"string"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 150000.5, "string": "2500"}`), &payload); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if payload.Number != FromSen(15000050) || payload.String != New(2500) {
+		t.Errorf("Unexpected amounts %s and %s", payload.Number, payload.String)
+	}
+
+	encoded, _ := json.Marshal(payload)
+	if string(encoded) != `{"number":150000.50,"string":2500.00}` {
+		t.Errorf("Unexpected encoding %s", encoded)
+	}
+}
+
+func TestScan(t *testing.T) {
+	var a Amount
+	if err := a.Scan([]byte("12345.67")); err != nil || a != FromSen(1234567) {
+		t.Errorf("Expected 12345.67 from numeric bytes, got %s (%v)", a, err)
+	}
+	if err := a.Scan(nil); err != nil || !a.IsZero() {
+		t.Errorf("Expected NULL to scan as zero, got %s (%v)", a, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Package pdf writes simple PDF documents of text lines and filled
+// rectangles. It only uses the standard Courier fonts, which every PDF reader
+// provides, so no font data is embedded and text can be lined up in columns
+// by counting characters.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Font is one of the standard fonts of the document.
+type Font string
+
+const (
+	Courier     Font = "F1"
+	CourierBold Font = "F2"
+)
+
+// PointsPerMM converts millimetres to PDF points.
+const PointsPerMM = 72 / 25.4
+
+// charWidth is the advance of every Courier character per point of font size.
+const charWidth = 0.6
+
+// Document is a PDF being built page by page. Sizes are in points.
+type Document struct {
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+}
+
+// New starts a document whose pages are width by height points.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// AddPage starts a new page. Text goes on the last page added.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+}
+
+// Text writes s with its baseline at x, y, measured from the top left of the
+// page. Characters outside Latin-1 are written as "?".
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, number(size), number(x), number(d.height-y), escape(s))
+}
+
+// Rect fills a black rectangle w by h points whose top left corner is at x, y,
+// measured from the top left of the page.
+func (d *Document) Rect(x, y, w, h float64) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "%s %s %s %s re f\n", number(x), number(d.height-y-h), number(w), number(h))
+}
+
+// TextWidth is the width of s in points at the given font size.
+func TextWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * charWidth * size
+}
+
+// Bytes writes out the document.
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var b bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-4 are the catalog, the page tree and the two fonts. Every
+	// page is followed by its content stream.
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			number(d.width), number(d.height), 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return b.Bytes()
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// escape turns s into the bytes of a PDF string in WinAnsiEncoding, which
+// matches Latin-1 outside 0x80-0x9F.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+		case r < 0x7F || (r >= 0xA0 && r <= 0xFF):
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
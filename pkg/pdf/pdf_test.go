@@ -0,0 +1,73 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "Total (incl. tax)", want: `Total \(incl. tax\)`},
+		{input: `C:\receipts`, want: `C:\\receipts`},
+		{input: "Café", want: "Caf\xe9"},
+		{input: "Kopi ☕", want: "Kopi ?"},
+		{input: "line\nbreak", want: "linebreak"},
+	}
+
+	for _, tt := range tests {
+		if got := escape(tt.input); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	doc := New(80*PointsPerMM, 200)
+	doc.Text(10, 20, CourierBold, 8, "TOTAL")
+	doc.Rect(10, 30, 4, 2)
+	doc.AddPage()
+	doc.Text(10, 20, Courier, 8, "Page 2")
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("Expected a PDF header and trailer")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("Expected two pages")
+	}
+	if !bytes.Contains(out, []byte("BT /F2 8.00 Tf 10.00 180.00 Td (TOTAL) Tj ET")) {
+		t.Error("Expected the text measured from the top of the page")
+	}
+	if !bytes.Contains(out, []byte("10.00 168.00 4.00 2.00 re f")) {
+		t.Error("Expected the rectangle measured from the top of the page")
+	}
+
+	// Every xref entry must point at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if startxref == nil {
+		t.Fatal("Expected startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 8 {
+		t.Fatalf("Expected 8 objects, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		want := strconv.Itoa(i+1) + " 0 obj"
+		if !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("Expected object %d at offset %d", i+1, offset)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := TextWidth("Kopi", 10); got != 24 {
+		t.Errorf("Expected 24pt, got %v", got)
+	}
+}
@@ -0,0 +1,483 @@
+// Package qr encodes text as a QR code. It covers what a receipt needs: byte
+// mode at error correction level M, in versions 1 to 10, which holds up to
+// 213 bytes.
+package qr
+
+import "errors"
+
+// ErrTooLong is returned for data that does not fit in version 10.
+var ErrTooLong = errors.New("qr: data is too long")
+
+// Code is a QR code, Size modules on each side, without the quiet zone.
+type Code struct {
+	Size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// Black reports whether the module at column x, row y is dark.
+func (c *Code) Black(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// blocks is the error correction layout of a version at level M: every
+// block gets ecPerBlock codewords, group 1 has count1 blocks of data1 data
+// codewords and group 2 count2 blocks of one more.
+type blocks struct {
+	ecPerBlock int
+	count1     int
+	data1      int
+	count2     int
+}
+
+func (b blocks) dataCodewords() int {
+	return b.count1*b.data1 + b.count2*(b.data1+1)
+}
+
+var levelM = [...]blocks{
+	1:  {ecPerBlock: 10, count1: 1, data1: 16},
+	2:  {ecPerBlock: 16, count1: 1, data1: 28},
+	3:  {ecPerBlock: 26, count1: 1, data1: 44},
+	4:  {ecPerBlock: 18, count1: 2, data1: 32},
+	5:  {ecPerBlock: 24, count1: 2, data1: 43},
+	6:  {ecPerBlock: 16, count1: 4, data1: 27},
+	7:  {ecPerBlock: 18, count1: 4, data1: 31},
+	8:  {ecPerBlock: 22, count1: 2, data1: 38, count2: 2},
+	9:  {ecPerBlock: 22, count1: 3, data1: 36, count2: 2},
+	10: {ecPerBlock: 26, count1: 4, data1: 43, count2: 1},
+}
+
+var alignment = [...][]int{
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+const maxVersion = 10
+
+// Encode encodes data in the smallest version it fits, with the mask that
+// scores the lowest penalty.
+func Encode(data string) (*Code, error) {
+	version := 0
+	for v := 1; v <= maxVersion; v++ {
+		if bits := 4 + countBits(v) + 8*len(data); bits <= 8*levelM[v].dataCodewords() {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := addErrorCorrection(dataCodewords([]byte(data), version), version)
+	base := newCode(version)
+	base.drawCodewords(codewords)
+
+	var best *Code
+	bestPenalty := 0
+	for mask := 0; mask < 8; mask++ {
+		code := base.clone()
+		code.applyMask(mask)
+		code.drawFormat(mask)
+		if penalty := code.penalty(); best == nil || penalty < bestPenalty {
+			best, bestPenalty = code, penalty
+		}
+	}
+	return best, nil
+}
+
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// dataCodewords is data in byte mode, padded to the capacity of version.
+func dataCodewords(data []byte, version int) []byte {
+	capacity := levelM[version].dataCodewords()
+	var w bitWriter
+	w.write(0x4, 4)
+	w.write(len(data), countBits(version))
+	for _, b := range data {
+		w.write(int(b), 8)
+	}
+
+	terminator := 8*capacity - w.n
+	if terminator > 4 {
+		terminator = 4
+	}
+	w.write(0, terminator)
+	if w.n%8 != 0 {
+		w.write(0, 8-w.n%8)
+	}
+	for pad := 0xEC; len(w.bytes) < capacity; pad ^= 0xEC ^ 0x11 {
+		w.write(pad, 8)
+	}
+	return w.bytes
+}
+
+type bitWriter struct {
+	bytes []byte
+	n     int
+}
+
+func (w *bitWriter) write(value, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.bytes = append(w.bytes, 0)
+		}
+		if value>>i&1 == 1 {
+			w.bytes[w.n/8] |= 0x80 >> (w.n % 8)
+		}
+		w.n++
+	}
+}
+
+// addErrorCorrection splits the data into blocks, adds the error correction
+// codewords of each and interleaves the result.
+func addErrorCorrection(data []byte, version int) []byte {
+	layout := levelM[version]
+	divisor := rsDivisor(layout.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	for i := 0; i < layout.count1+layout.count2; i++ {
+		size := layout.data1
+		if i >= layout.count1 {
+			size++
+		}
+		block := data[:size]
+		data = data[size:]
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+	}
+
+	var out []byte
+	for i := 0; i <= layout.data1; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			out = append(out, block[i])
+		}
+	}
+	return out
+}
+
+// rsDivisor is the Reed-Solomon generator polynomial of the given degree,
+// highest power first with the leading 1 left out.
+func rsDivisor(degree int) []byte {
+	divisor := make([]byte, degree)
+	divisor[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range divisor {
+			divisor[j] = gfMultiply(divisor[j], root)
+			if j+1 < degree {
+				divisor[j] ^= divisor[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return divisor
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// newCode draws the finder, timing and alignment patterns and the version
+// information, and reserves the format information.
+func newCode(version int) *Code {
+	size := 17 + 4*version
+	c := &Code{Size: size, modules: grid(size), function: grid(size)}
+
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(size-4, 3)
+	c.drawFinder(3, size-4)
+
+	positions := alignment[version]
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+
+	c.drawFormat(0)
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			bit := bits>>i&1 == 1
+			a, b := size-11+i%3, i/3
+			c.setFunction(a, b, bit)
+			c.setFunction(b, a, bit)
+		}
+	}
+	return c
+}
+
+func grid(size int) [][]bool {
+	rows := make([][]bool, size)
+	for i := range rows {
+		rows[i] = make([]bool, size)
+	}
+	return rows
+}
+
+func (c *Code) clone() *Code {
+	out := &Code{Size: c.Size, modules: grid(c.Size), function: c.function}
+	for y := range c.modules {
+		copy(out.modules[y], c.modules[y])
+	}
+	return out
+}
+
+func (c *Code) setFunction(x, y int, black bool) {
+	c.modules[y][x] = black
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormat writes both copies of the format information for level M and
+// the mask, and the dark module.
+func (c *Code) drawFormat(mask int) {
+	data := mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+// drawCodewords places the codewords in the two module wide columns that
+// zigzag up and down from the bottom right corner.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the code by the four rules of the standard: runs of one
+// colour, 2x2 blocks, patterns that look like a finder, and the balance of
+// dark and light.
+func (c *Code) penalty() int {
+	n := c.Size
+	score := 0
+	line := make([]bool, n)
+	for _, horizontal := range []bool{true, false} {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if horizontal {
+					line[j] = c.modules[i][j]
+				} else {
+					line[j] = c.modules[j][i]
+				}
+			}
+			score += runPenalty(line) + finderPenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < n && y+1 < n {
+				v := c.modules[y][x]
+				if v == c.modules[y][x+1] && v == c.modules[y+1][x] && v == c.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+	score += 10 * (abs(dark*20-n*n*10) / (n * n))
+	return score
+}
+
+func runPenalty(line []bool) int {
+	score, run := 0, 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+	return score
+}
+
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// finderPenalty counts 1:1:3:1:1 patterns with four light modules on either
+// side.
+func finderPenalty(line []bool) int {
+	score := 0
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		match := true
+		for j, v := range finderLike {
+			if line[i+j] != v {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if light(line, i-4, i) || light(line, i+len(finderLike), i+len(finderLike)+4) {
+			score += 40
+		}
+	}
+	return score
+}
+
+// light reports whether the modules in [from, to) are light, counting the
+// quiet zone outside the code as light.
+func light(line []bool, from, to int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
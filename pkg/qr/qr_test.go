@@ -0,0 +1,113 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// HELLO WORLD at 1-M, from the worked example of the standard
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestDataCodewords(t *testing.T) {
+	got := dataCodewords([]byte("Hi"), 1)
+	want := []byte{0x40, 0x24, 0x86, 0x90, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Expected %x, got %x", want, got)
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		length int
+		size   int
+	}{
+		{length: 1, size: 21},
+		{length: 14, size: 21},
+		{length: 15, size: 25},
+		{length: 122, size: 45},
+		{length: 213, size: 57},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(strings.Repeat("a", tt.length))
+		if err != nil {
+			t.Fatalf("Expected no error for %d bytes, got %v", tt.length, err)
+		}
+		if code.Size != tt.size {
+			t.Errorf("Expected %d bytes to take %d modules, got %d", tt.length, tt.size, code.Size)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("a", 214)); err != ErrTooLong {
+		t.Errorf("Expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestEncodePatterns(t *testing.T) {
+	code, err := Encode("https://example.com/receipts/verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := code.Size
+
+	// The finder patterns have a dark ring, a light ring and a dark centre
+	for _, corner := range [][2]int{{0, 0}, {n - 7, 0}, {0, n - 7}} {
+		for _, cell := range []struct {
+			x, y  int
+			black bool
+		}{{0, 0, true}, {6, 6, true}, {1, 1, false}, {5, 1, false}, {3, 3, true}, {2, 4, true}} {
+			if got := code.Black(corner[0]+cell.x, corner[1]+cell.y); got != cell.black {
+				t.Errorf("Finder at %v: expected module %d,%d black=%v", corner, cell.x, cell.y, cell.black)
+			}
+		}
+	}
+	for i := 8; i < n-8; i++ {
+		if code.Black(i, 6) != (i%2 == 0) || code.Black(6, i) != (i%2 == 0) {
+			t.Errorf("Expected alternating timing modules at %d", i)
+		}
+	}
+	if !code.Black(8, n-8) {
+		t.Error("Expected the dark module")
+	}
+
+	// Both copies of the format information must be the same valid code word
+	var first, second int
+	for i, xy := range [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}} {
+		if code.Black(xy[0], xy[1]) {
+			first |= 1 << i
+		}
+	}
+	for i := 0; i < 15; i++ {
+		x, y := n-1-i, 8
+		if i >= 8 {
+			x, y = 8, n-15+i
+		}
+		if code.Black(x, y) {
+			second |= 1 << i
+		}
+	}
+	if first != second {
+		t.Errorf("Expected the format copies to match, got %015b and %015b", first, second)
+	}
+	format := first ^ 0x5412
+	if format>>13 != 0 {
+		t.Errorf("Expected level M, got format %015b", format)
+	}
+	rem := format
+	for i := 14; i >= 10; i-- {
+		if rem>>i&1 == 1 {
+			rem ^= 0x537 << (i - 10)
+		}
+	}
+	if rem != 0 {
+		t.Errorf("Expected a valid BCH code, got %015b", format)
+	}
+}
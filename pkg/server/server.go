@@ -33,6 +33,7 @@ func NewServer(serverName string, publicRoutes, privateRoutes []*route.Route, se
 			http.MethodGet,
 			http.MethodPost,
 			http.MethodPut,
+			http.MethodPatch,
 			http.MethodDelete,
 			http.MethodOptions,
 		},
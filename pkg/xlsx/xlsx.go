@@ -0,0 +1,206 @@
+// Package xlsx writes Excel workbooks row by row. Every sheet goes straight
+// into the zip file as it is written, so a workbook of any size is never held
+// in memory. Sheets are written one after the other and strings are stored
+// inline, which every spreadsheet program reads.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Number is a numeric cell written exactly as given, such as "150000.00", so
+// amounts are not rounded through a float.
+type Number string
+
+// Writer writes a workbook to an io.Writer.
+type Writer struct {
+	zip    *zip.Writer
+	sheets []string
+	sheet  *bufio.Writer
+	row    int
+	closed bool
+}
+
+// NewWriter starts a workbook on w. Close must be called to finish it.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zip: zip.NewWriter(w)}
+}
+
+// AddSheet finishes the current sheet and starts a new one. Names are cut to
+// the 31 characters Excel allows.
+func (w *Writer) AddSheet(name string) error {
+	if w.closed {
+		return errors.New("xlsx: writer is closed")
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	w.sheets = append(w.sheets, name)
+
+	f, err := w.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return err
+	}
+	w.sheet = bufio.NewWriter(f)
+	w.row = 0
+	_, err = w.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return err
+}
+
+// WriteRow adds a row to the current sheet. Cells can be strings, Numbers,
+// ints, floats and times; nil leaves a cell empty.
+func (w *Writer) WriteRow(cells ...interface{}) error {
+	if w.sheet == nil {
+		return errors.New("xlsx: no sheet added")
+	}
+	w.row++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.row)
+	for i, cell := range cells {
+		ref := column(i) + strconv.Itoa(w.row)
+		switch v := cell.(type) {
+		case nil:
+		case string:
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(w.sheet, []byte(clean(v))); err != nil {
+				return err
+			}
+			w.sheet.WriteString(`</t></is></c>`)
+		case Number:
+			if _, err := strconv.ParseFloat(string(v), 64); err != nil {
+				return fmt.Errorf("xlsx: %q is not a number", v)
+			}
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, v)
+		case int:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case int64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		case time.Time:
+			fmt.Fprintf(w.sheet, `<c r="%s" s="1"><v>%s</v></c>`, ref, strconv.FormatFloat(serial(v), 'f', -1, 64))
+		default:
+			return fmt.Errorf("xlsx: unsupported cell type %T", cell)
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Close finishes the last sheet and writes the workbook parts that list the
+// sheets.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.sheets) == 0 {
+		return errors.New("xlsx: a workbook needs at least one sheet")
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	var contentTypes, workbook, rels strings.Builder
+	contentTypes.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, name := range w.sheets {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		f, err := w.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+	return w.zip.Close()
+}
+
+func (w *Writer) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	err := w.sheet.Flush()
+	w.sheet = nil
+	return err
+}
+
+// styles has the default cell format and, at index 1, a date and time.
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
+
+// column is the letter name of the zero based column i: A, B, ..., Z, AA.
+func column(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// serial is t as an Excel date serial number, the days since 30 December 1899
+// in t's own wall clock.
+func serial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return float64(wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Milliseconds()) / float64(24*time.Hour/time.Millisecond)
+}
+
+// clean drops the control characters XML 1.0 does not allow.
+func clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(clean(s)))
+	return b.String()
+}
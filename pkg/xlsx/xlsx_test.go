@@ -0,0 +1,92 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.AddSheet("Summary"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("Total <Sales> & Tax", Number("150000.50"), 3, nil, time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddSheet("A sheet name that is far too long for Excel"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected a zip file, got %v", err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Expected part %s", name)
+		}
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">Total &lt;Sales&gt; &amp; Tax</t></is></c>`,
+		`<c r="B1"><v>150000.50</v></c>`,
+		`<c r="C1"><v>3</v></c>`,
+		`<c r="E1" s="1"><v>46024.5</v></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Expected the sheet to contain %s", want)
+		}
+	}
+	if strings.Contains(sheet, `r="D1"`) {
+		t.Error("Expected the nil cell to be left out")
+	}
+	if !strings.Contains(files["xl/workbook.xml"], `<sheet name="A sheet name that is far too lo" sheetId="2" r:id="rId2"/>`) {
+		t.Errorf("Expected the second sheet name to be cut to 31 characters, got %s", files["xl/workbook.xml"])
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	w := NewWriter(io.Discard)
+	if err := w.WriteRow("x"); err == nil {
+		t.Error("Expected an error writing before a sheet is added")
+	}
+	w.AddSheet("Sheet")
+	if err := w.WriteRow(Number("12,5")); err == nil {
+		t.Error("Expected an error for a malformed number")
+	}
+	if err := w.WriteRow(struct{}{}); err == nil {
+		t.Error("Expected an error for an unsupported cell")
+	}
+}
+
+func TestColumn(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := column(i); got != want {
+			t.Errorf("column(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
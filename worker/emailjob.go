@@ -1,8 +1,15 @@
 package worker
 
+import "Kevinmajesta/OrderManagementAPI/pkg/email"
+
 type EmailJob struct {
 	Type     string 
 	To       string
 	Name     string
 	ResetCode string
+	// Subject and Body are used by "notification" and "html" jobs
+	Subject   string
+	Body      string
+	// Attachments are sent with "html" jobs
+	Attachments []email.Attachment
 }
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+)
+
+type TierRecalculator interface {
+	RecalculateTiers(at time.Time) error
+}
+
+// StartMembershipWorker recalculates membership tiers once at startup and
+// then every interval.
+func StartMembershipWorker(recalculator TierRecalculator, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := recalculator.RecalculateTiers(time.Now()); err != nil {
+				fmt.Printf("Failed to recalculate membership tiers: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
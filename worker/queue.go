@@ -3,6 +3,8 @@ package worker
 import (
 	"fmt"
 	"time"
+
+	"Kevinmajesta/OrderManagementAPI/pkg/email"
 )
 
 var EmailQueue = make(chan EmailJob, 100) // buffer 100 job
@@ -10,6 +12,8 @@ var EmailQueue = make(chan EmailJob, 100) // buffer 100 job
 type EmailSender interface {
 	SendWelcomeEmail(to, name, extra string) error
 	SendVerificationEmail(to, name, resetCode string) error
+	SendEmail(to []string, subject, body string) error
+	SendHTMLEmail(to []string, subject, body string, attachments []email.Attachment) error
 }
 
 func StartEmailWorker(emailSender EmailSender) {
@@ -20,6 +24,14 @@ func StartEmailWorker(emailSender EmailSender) {
                 _ = emailSender.SendWelcomeEmail(job.To, job.Name, "")
             case "verification":
                 _ = emailSender.SendVerificationEmail(job.To, job.Name, job.ResetCode)
+            case "notification":
+                if err := emailSender.SendEmail([]string{job.To}, job.Subject, job.Body); err != nil {
+                    fmt.Printf("Failed to send notification to %s: %v\n", job.To, err)
+                }
+            case "html":
+                if err := emailSender.SendHTMLEmail([]string{job.To}, job.Subject, job.Body, job.Attachments); err != nil {
+                    fmt.Printf("Failed to send email to %s: %v\n", job.To, err)
+                }
             }
         }
     }()
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+)
+
+type ReportRunner interface {
+	RunDueReports(at time.Time) error
+}
+
+// StartReportWorker sends the scheduled sales reports that are due, once at
+// startup and then every interval.
+func StartReportWorker(runner ReportRunner, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := runner.RunDueReports(time.Now()); err != nil {
+				fmt.Printf("Failed to send scheduled reports: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}